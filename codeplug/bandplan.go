@@ -0,0 +1,377 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+//go:generate genCodeplugInfo -bandplans bandplandata.go bandplans.json
+
+// A BandPlan divides amateur bands into segments for particular uses,
+// giving the standard repeater offsets and the simplex segments.
+type BandPlan struct {
+	Name  string  `json:"name"`
+	Bands []*Band `json:"bands"`
+}
+
+// A Band is a range of frequencies, in MHz, within a band plan.
+type Band struct {
+	Name     string     `json:"name"`
+	Low      float64    `json:"low"`
+	High     float64    `json:"high"`
+	Segments []*Segment `json:"segments"`
+}
+
+// A Segment is a range of frequencies, in MHz, within a band, having
+// a particular use.  Offset, for a repeater output segment, is added
+// to a repeater's output frequency to give its input frequency.  Note
+// tells what the segment is for, as in "satellite".
+type Segment struct {
+	Low    float64    `json:"low"`
+	High   float64    `json:"high"`
+	Use    SegmentUse `json:"use"`
+	Offset float64    `json:"offset,omitempty"`
+	Note   string     `json:"note,omitempty"`
+}
+
+// A SegmentUse tells what a band plan segment is for.
+type SegmentUse string
+
+const (
+	SegmentSimplex        SegmentUse = "simplex"
+	SegmentRepeaterOutput SegmentUse = "repeaterOutput"
+	SegmentRepeaterInput  SegmentUse = "repeaterInput"
+	SegmentNoTransmit     SegmentUse = "noTransmit"
+)
+
+// frequencyEpsilon is the difference, in MHz, below which frequencies
+// are considered equal.
+const frequencyEpsilon = 0.0000005
+
+// contains returns true if the segment contains the frequency.
+func (s *Segment) contains(freq float64) bool {
+	return freq > s.Low-frequencyEpsilon && freq < s.High+frequencyEpsilon
+}
+
+// String returns a description of the segment, as in
+// "145.80000-146.00000 MHz satellite".
+func (s *Segment) String() string {
+	note := s.Note
+	if note == "" {
+		note = string(s.Use)
+	}
+
+	return fmt.Sprintf("%s-%s MHz %s",
+		frequencyToString(s.Low), frequencyToString(s.High), note)
+}
+
+// Band returns the band containing the frequency, or nil if there is
+// none.
+func (bp *BandPlan) Band(freq float64) *Band {
+	for _, b := range bp.Bands {
+		if freq > b.Low-frequencyEpsilon && freq < b.High+frequencyEpsilon {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// Segment returns the first segment containing the frequency, or nil
+// if there is none.
+func (bp *BandPlan) Segment(freq float64) *Segment {
+	b := bp.Band(freq)
+	if b == nil {
+		return nil
+	}
+	for _, s := range b.Segments {
+		if s.contains(freq) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// ProposedTxFrequency returns the transmit frequency that the band plan
+// gives for a channel with the given receive frequency: that of the
+// repeater input for a repeater output frequency, or the receive
+// frequency itself in a simplex segment.  It returns false if the plan
+// gives none.
+func (bp *BandPlan) ProposedTxFrequency(rx float64) (float64, bool) {
+	s := bp.Segment(rx)
+	if s == nil {
+		return 0, false
+	}
+
+	switch s.Use {
+	case SegmentSimplex:
+		return rx, true
+	case SegmentRepeaterOutput:
+		if s.Offset != 0 {
+			return rx + s.Offset, true
+		}
+	}
+
+	return 0, false
+}
+
+// valid returns an error describing the first problem found in the
+// band plan, or nil.
+func (bp *BandPlan) valid() error {
+	if bp.Name == "" {
+		return fmt.Errorf("band plan has no name")
+	}
+
+	for _, b := range bp.Bands {
+		if b.Low >= b.High {
+			return fmt.Errorf("%s: %s: bad frequency range", bp.Name, b.Name)
+		}
+		for _, s := range b.Segments {
+			if s.Low > s.High || s.Low < b.Low || s.High > b.High {
+				return fmt.Errorf("%s: %s: bad segment %s", bp.Name, b.Name, s)
+			}
+			switch s.Use {
+			case SegmentSimplex, SegmentRepeaterOutput,
+				SegmentRepeaterInput, SegmentNoTransmit:
+			default:
+				return fmt.Errorf("%s: %s: unknown segment use: %s",
+					bp.Name, b.Name, s.Use)
+			}
+		}
+	}
+
+	return nil
+}
+
+// An encodedBandPlans is the form of a band plan file.
+type encodedBandPlans struct {
+	Comments  []string    `json:"comments,omitempty"`
+	BandPlans []*BandPlan `json:"bandPlans"`
+}
+
+// bandPlans holds the known band plans, in the order they were loaded.
+var bandPlans []*BandPlan
+var bandPlansMutex sync.Mutex
+
+func init() {
+	err := addBandPlans(builtinBandPlans)
+	if err != nil {
+		panic("bandplans.json: " + err.Error())
+	}
+}
+
+// BandPlans returns the known band plans: those in bandplans.json,
+// followed by any that have been loaded.
+func BandPlans() []*BandPlan {
+	bandPlansMutex.Lock()
+	defer bandPlansMutex.Unlock()
+
+	return append([]*BandPlan{}, bandPlans...)
+}
+
+// BandPlanByName returns the known band plan with the given name, or
+// nil if there is none.
+func BandPlanByName(name string) *BandPlan {
+	bandPlansMutex.Lock()
+	defer bandPlansMutex.Unlock()
+
+	for _, bp := range bandPlans {
+		if bp.Name == name {
+			return bp
+		}
+	}
+
+	return nil
+}
+
+// LoadBandPlans reads band plans, in the JSON form of bandplans.json,
+// from rdr and adds them to the known band plans, replacing any having
+// the same names.  It returns the plans read.
+func LoadBandPlans(rdr io.Reader) ([]*BandPlan, error) {
+	var ebp encodedBandPlans
+	err := json.NewDecoder(rdr).Decode(&ebp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addBandPlans(ebp.BandPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	return ebp.BandPlans, nil
+}
+
+// addBandPlans adds the band plans to the known band plans, replacing
+// any having the same names.  No plans are added if any is invalid.
+func addBandPlans(bps []*BandPlan) error {
+	for _, bp := range bps {
+		if err := bp.valid(); err != nil {
+			return err
+		}
+	}
+
+	bandPlansMutex.Lock()
+	defer bandPlansMutex.Unlock()
+
+	for _, bp := range bps {
+		replaced := false
+		for i, known := range bandPlans {
+			if known.Name == bp.Name {
+				bandPlans[i] = bp
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			bandPlans = append(bandPlans, bp)
+		}
+	}
+
+	return nil
+}
+
+// LoadBandPlanFile reads band plans from the named file.  See
+// LoadBandPlans.
+func LoadBandPlanFile(filename string) ([]*BandPlan, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadBandPlans(file)
+}
+
+// SetBandPlan sets the band plan used to propose transmit frequencies
+// and by the band plan lint rules.  A nil band plan disables them.
+func (cp *Codeplug) SetBandPlan(bp *BandPlan) {
+	cp.bandPlan = bp
+}
+
+// BandPlan returns the codeplug's band plan, or nil if it has none.
+func (cp *Codeplug) BandPlan() *BandPlan {
+	return cp.bandPlan
+}
+
+// ProposedTxFrequency returns, in the form shown by Export, the
+// transmit frequency that the codeplug's band plan gives for a channel
+// with the given receive frequency.  It returns false if there is no
+// band plan, if the plan gives no frequency, or if the radio can't
+// transmit on it.
+func (cp *Codeplug) ProposedTxFrequency(rx string) (string, bool) {
+	if cp.bandPlan == nil {
+		return "", false
+	}
+
+	rxFreq, err := stringToFrequency(rx)
+	if err != nil {
+		return "", false
+	}
+
+	txFreq, ok := cp.bandPlan.ProposedTxFrequency(rxFreq)
+	if !ok || cp.frequencyValid(txFreq) != nil {
+		return "", false
+	}
+
+	return frequencyToString(txFreq), true
+}
+
+// channelFrequencies returns a channel's receive and transmit
+// frequencies.  It returns false if either is not a valid number.
+func channelFrequencies(r *Record) (rx float64, tx float64, ok bool) {
+	rx, err := stringToFrequency(lintValue(r, FtRxFrequency))
+	if err != nil {
+		return 0, 0, false
+	}
+	tx, err = stringToFrequency(lintValue(r, FtTxFrequency))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return rx, tx, true
+}
+
+// lintNonstandardSplit, like the other band plan lint rules, checks
+// nothing unless the codeplug has a band plan.
+func lintNonstandardSplit(l *Linter) {
+	bp := l.Codeplug().BandPlan()
+	if bp == nil {
+		return
+	}
+
+	for _, r := range l.Codeplug().Records(RtChannelInformation) {
+		rx, tx, ok := channelFrequencies(r)
+		if !ok || lintValue(r, FtRxOnly) == "On" {
+			continue
+		}
+		s := bp.Segment(rx)
+		if s == nil {
+			continue
+		}
+		split := tx - rx
+		if math.Abs(split) < frequencyEpsilon {
+			continue
+		}
+
+		switch s.Use {
+		case SegmentSimplex:
+			l.Report(r, r.Field(FtTxFrequency),
+				"split of %+.4f MHz in %s simplex segment %s",
+				split, bp.Name, s)
+		case SegmentRepeaterOutput:
+			if s.Offset != 0 && math.Abs(split-s.Offset) > frequencyEpsilon {
+				l.Report(r, r.Field(FtTxFrequency),
+					"split of %+.4f MHz differs from the %s repeater offset of %+.4f MHz",
+					split, bp.Name, s.Offset)
+			}
+		}
+	}
+}
+
+func lintTxNoTransmitSegment(l *Linter) {
+	bp := l.Codeplug().BandPlan()
+	if bp == nil {
+		return
+	}
+
+	for _, r := range l.Codeplug().Records(RtChannelInformation) {
+		_, tx, ok := channelFrequencies(r)
+		if !ok || lintValue(r, FtRxOnly) == "On" {
+			continue
+		}
+		s := bp.Segment(tx)
+		if s != nil && s.Use == SegmentNoTransmit {
+			l.Report(r, r.Field(FtTxFrequency),
+				"transmit frequency is in %s segment %s", bp.Name, s)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package codeplug
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestBuiltinBandPlans(t *testing.T) {
+	for _, name := range []string{"IARU Region 1", "IARU Region 2", "IARU Region 3"} {
+		if BandPlanByName(name) == nil {
+			t.Errorf("no built-in band plan %q", name)
+		}
+	}
+
+	bp := BandPlanByName("IARU Region 2")
+	tx, ok := bp.ProposedTxFrequency(146.94)
+	if !ok || tx < 146.34-frequencyEpsilon || tx > 146.34+frequencyEpsilon {
+		t.Errorf("proposed tx for 146.94 is %v, %v", tx, ok)
+	}
+}
+
+// TestLoadBandPlansConcurrently loads band plans while others are
+// looked up.  It is meant to be run with -race.
+func TestLoadBandPlansConcurrently(t *testing.T) {
+	const plans = 4
+
+	var wg sync.WaitGroup
+	for i := 0; i < plans; i++ {
+		name := fmt.Sprintf("Test Plan %d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			json := `{"bandPlans": [{"name": "` + name + `", "bands": [
+				{"name": "2 m", "low": 144, "high": 148, "segments": [
+					{"low": 146.4, "high": 146.6, "use": "simplex"}]}]}]}`
+			if _, err := LoadBandPlans(strings.NewReader(json)); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for _, bp := range BandPlans() {
+				BandPlanByName(bp.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < plans; i++ {
+		name := fmt.Sprintf("Test Plan %d", i)
+		if BandPlanByName(name) == nil {
+			t.Errorf("%s wasn't loaded", name)
+		}
+	}
+}
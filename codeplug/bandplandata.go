@@ -0,0 +1,126 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by genCodeplugInfo from bandplans.json. DO NOT EDIT.
+
+package codeplug
+
+// builtinBandPlans holds the band plans of bandplans.json.
+//
+// Band plans for the amateur 2 m and 70 cm bands.
+//
+// Frequencies are in MHz.  A segment's use is one of simplex,
+// repeaterOutput, repeaterInput or noTransmit.  A repeaterOutput
+// segment's offset is added to a repeater's output (receive)
+// frequency to give its input (transmit) frequency.  The first
+// segment containing a frequency applies to it.
+//
+// These plans give the segments in common use.  National plans
+// differ; a plan in the same form may be loaded from a file to
+// replace or add to these.
+var builtinBandPlans = []*BandPlan{
+	{
+		Name: "IARU Region 1",
+		Bands: []*Band{
+			{
+				Name: "2 m",
+				Low:  144,
+				High: 146,
+				Segments: []*Segment{
+					{Low: 144.4, High: 144.49, Use: SegmentNoTransmit, Note: "beacon"},
+					{Low: 145, High: 145.1875, Use: SegmentRepeaterInput},
+					{Low: 145.2, High: 145.5875, Use: SegmentSimplex},
+					{Low: 145.6, High: 145.7875, Use: SegmentRepeaterOutput, Offset: -0.6},
+					{Low: 145.8, High: 146, Use: SegmentNoTransmit, Note: "satellite"},
+				},
+			},
+			{
+				Name: "70 cm",
+				Low:  430,
+				High: 440,
+				Segments: []*Segment{
+					{Low: 431.05, High: 431.825, Use: SegmentRepeaterInput},
+					{Low: 432.4, High: 432.49, Use: SegmentNoTransmit, Note: "beacon"},
+					{Low: 433, High: 433.6, Use: SegmentSimplex},
+					{Low: 435, High: 438, Use: SegmentNoTransmit, Note: "satellite"},
+					{Low: 438.65, High: 439.425, Use: SegmentRepeaterOutput, Offset: -7.6},
+				},
+			},
+		},
+	},
+	{
+		Name: "IARU Region 2",
+		Bands: []*Band{
+			{
+				Name: "2 m",
+				Low:  144,
+				High: 148,
+				Segments: []*Segment{
+					{Low: 144.275, High: 144.3, Use: SegmentNoTransmit, Note: "beacon"},
+					{Low: 144.6, High: 144.9, Use: SegmentRepeaterInput},
+					{Low: 145.2, High: 145.5, Use: SegmentRepeaterOutput, Offset: -0.6},
+					{Low: 145.8, High: 146, Use: SegmentNoTransmit, Note: "satellite"},
+					{Low: 146.01, High: 146.37, Use: SegmentRepeaterInput},
+					{Low: 146.4, High: 146.58, Use: SegmentSimplex},
+					{Low: 146.61, High: 146.97, Use: SegmentRepeaterOutput, Offset: -0.6},
+					{Low: 147, High: 147.39, Use: SegmentRepeaterOutput, Offset: 0.6},
+					{Low: 147.42, High: 147.57, Use: SegmentSimplex},
+					{Low: 147.6, High: 147.99, Use: SegmentRepeaterInput},
+				},
+			},
+			{
+				Name: "70 cm",
+				Low:  420,
+				High: 450,
+				Segments: []*Segment{
+					{Low: 432.3, High: 432.4, Use: SegmentNoTransmit, Note: "beacon"},
+					{Low: 435, High: 438, Use: SegmentNoTransmit, Note: "satellite"},
+					{Low: 442, High: 445, Use: SegmentRepeaterOutput, Offset: 5},
+					{Low: 446, High: 446.5, Use: SegmentSimplex},
+					{Low: 447, High: 450, Use: SegmentRepeaterOutput, Offset: -5},
+				},
+			},
+		},
+	},
+	{
+		Name: "IARU Region 3",
+		Bands: []*Band{
+			{
+				Name: "2 m",
+				Low:  144,
+				High: 148,
+				Segments: []*Segment{
+					{Low: 145.8, High: 146, Use: SegmentNoTransmit, Note: "satellite"},
+				},
+			},
+			{
+				Name: "70 cm",
+				Low:  430,
+				High: 440,
+				Segments: []*Segment{
+					{Low: 435, High: 438, Use: SegmentNoTransmit, Note: "satellite"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,242 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// An rdt file is a DfuSe file containing a single element, which holds
+// a 256-byte vendor header followed by the contents of the bin file.
+const (
+	dfuPrefixSize       = 11
+	dfuTargetPrefixSize = 274
+	dfuElementSize      = 8
+	dfuSuffixSize       = 16
+	dfuElementOffset    = dfuPrefixSize + dfuTargetPrefixSize + dfuElementSize
+)
+
+// FrequencyRanges returns the frequency ranges of the radios for which
+// codeplugs of the given type may be created by NewBlankCodeplug.  A
+// multi-band radio has a single range, spanning all of its bands.
+func FrequencyRanges(cpType CodeplugType) []FrequencyRange {
+	bands := cpInfos[cpType].bands
+	if bands != nil {
+		low := bands[0].Low
+		high := bands[len(bands)-1].High
+		return []FrequencyRange{{low, high}}
+	}
+
+	// The last of frequencyRanges is only used to infer a range.
+	n := len(frequencyRanges) - 1
+	return append([]FrequencyRange(nil), frequencyRanges[:n]...)
+}
+
+// NewBlankCodeplug returns a new rdt Codeplug of the given codeplug type,
+// for a radio covering the given frequency range, which must be one of
+// those returned by FrequencyRanges.  General settings have their
+// default values.  There is one record of each of the other types, since
+// Import requires them, each named after its type.  The codeplug has no
+// file name, so it must be saved with SaveAs.
+func NewBlankCodeplug(cpType CodeplugType, fRange FrequencyRange) (*Codeplug, error) {
+	found := false
+	for _, r := range FrequencyRanges(cpType) {
+		if r == fRange {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s codeplugs do not support %s", cpType, fRange)
+	}
+
+	cp, err := newCodeplug(cpType)
+	if err != nil {
+		return nil, err
+	}
+
+	info := cpInfos[cpType]
+	cp.fileType = FileTypeRdt
+	cp.fileSize = info.rdtSize
+	cp.fileOffset = fileOffsetRdt
+	cp.bytes = blankRdtBytes(info.rdtSize)
+	cp.load(cp.bytes)
+
+	for _, rd := range cp.rDesc {
+		rd.records = nil
+	}
+
+	// The rdt header determines which channel frequencies are valid,
+	// so it comes first.
+	for _, rType := range append([]RecordType{RtRdtHeader}, cp.RecordTypes()...) {
+		r := cp.blankRecord(rType)
+		if err := r.setBlankValues(fRange); err != nil {
+			return nil, err
+		}
+		if err := cp.InsertRecord(r); err != nil {
+			return nil, err
+		}
+	}
+
+	cp.bytes = cp.imageBytes()
+	if err = cp.Revert(); err != nil {
+		return nil, err
+	}
+
+	codeplugsMutex.Lock()
+	codeplugs = append(codeplugs, cp)
+	codeplugsMutex.Unlock()
+
+	return cp, nil
+}
+
+// blankRdtBytes returns the contents of an rdt file of the given size,
+// with its DfuSe header and suffix, but otherwise zeroed.  Like the
+// rest of the package, it leaves the suffix's CRC unset.
+func blankRdtBytes(size int) []byte {
+	cpBytes := make([]byte, size)
+	le := binary.LittleEndian
+
+	prefix := cpBytes[:dfuPrefixSize]
+	copy(prefix, "DfuSe")
+	prefix[5] = 1 // version
+	le.PutUint32(prefix[6:], uint32(size-dfuSuffixSize))
+	prefix[10] = 1 // number of targets
+
+	elementSize := size - dfuElementOffset - dfuSuffixSize
+	target := cpBytes[dfuPrefixSize : dfuPrefixSize+dfuTargetPrefixSize]
+	copy(target, "Target")
+	le.PutUint32(target[266:], uint32(dfuElementSize+elementSize))
+	le.PutUint32(target[270:], 1) // number of elements
+
+	element := cpBytes[dfuPrefixSize+dfuTargetPrefixSize : dfuElementOffset]
+	le.PutUint32(element[4:], uint32(elementSize))
+
+	suffix := cpBytes[size-dfuSuffixSize:]
+	le.PutUint16(suffix[0:], 0xffff) // device
+	le.PutUint16(suffix[2:], 0xffff) // product
+	le.PutUint16(suffix[4:], 0xffff) // vendor
+	le.PutUint16(suffix[6:], 0x011a) // DFU version
+	copy(suffix[8:], "UFD")
+	suffix[11] = dfuSuffixSize
+
+	return cpBytes
+}
+
+// blankRecord returns a new record of the given type, with all of its
+// fields at their default values, or zero.
+func (cp *Codeplug) blankRecord(rType RecordType) *Record {
+	rd := cp.rDesc[rType]
+	r := cp.bytesToRecord(rType, 0, make([]byte, rd.size))
+
+	for _, fType := range r.FieldTypes() {
+		for _, f := range r.Fields(fType) {
+			if f.defaultValue != "" {
+				f.SetString(f.defaultValue)
+			}
+		}
+	}
+
+	return r
+}
+
+// blankRecordNames holds the names given to the records of a blank
+// codeplug, where the record type's name is too long.
+var blankRecordNames = map[RecordType]string{
+	RtChannelInformation: "Channel",
+	RtDigitalContacts:    "Contact",
+	RtGroupList:          "Group List",
+	RtZoneInformation:    "Zone",
+}
+
+// setBlankValues sets the fields of a blank record whose default or
+// zero values are not suitable.
+func (r *Record) setBlankValues(fRange FrequencyRange) error {
+	freq := frequencyToString(fRange.Low)
+	var name string
+	if r.NameField() != nil {
+		name = blankRecordNames[r.rType]
+		if name == "" {
+			name = r.TypeName()
+		}
+		if r.max > 1 {
+			name += " 1"
+		}
+		name = truncateName(r.NameField(), name)
+	}
+
+	var values map[FieldType]string
+	switch r.rType {
+	case RtRdtHeader:
+		values = map[FieldType]string{
+			FtLowFrequency:  frequencyToString(fRange.Low),
+			FtHighFrequency: frequencyToString(fRange.High),
+		}
+
+	case RtGeneralSettings:
+		// An empty password is stored as 0xff bytes.
+		values = map[FieldType]string{
+			FtPcProgPw: "",
+		}
+
+	case RtChannelInformation:
+		values = map[FieldType]string{
+			FtChannelName: name,
+			FtChannelMode: "Analog",
+			FtRxFrequency: freq,
+			FtTxFrequency: freq,
+		}
+
+	case RtDigitalContacts:
+		values = map[FieldType]string{
+			FtContactName: name,
+			FtCallID:      "1",
+			FtCallType:    "Group",
+		}
+
+	case RtTextMessage:
+		// An empty text message is a deleted one.
+		values = map[FieldType]string{
+			FtTextMessage: "Text Message 1",
+		}
+
+	default:
+		if name != "" {
+			values = map[FieldType]string{
+				r.NameFieldType(): name,
+			}
+		}
+	}
+
+	for fType, str := range values {
+		f := r.Field(fType)
+		err := f.SetString(str)
+		if err != nil {
+			return fmt.Errorf("%s: %s", f.location(), err)
+		}
+	}
+
+	return nil
+}
@@ -39,6 +39,9 @@ type Change struct {
 	strings      []string
 	afterStrings []string
 	changes      []*Change
+	remote       bool
+	codeplug     *Codeplug
+	offset       int
 }
 
 func (change *Change) Type() ChangeType {
@@ -61,7 +64,17 @@ func (change *Change) previousValue() string {
 	return ""
 }
 
+// Remote returns true if the change was made by ReplayChange.  A
+// subscriber that sends changes to another codeplug should not send
+// remote changes, which came from elsewhere.
+func (change *Change) Remote() bool {
+	return change.remote
+}
+
 func (change *Change) Codeplug() *Codeplug {
+	if change.codeplug != nil {
+		return change.codeplug
+	}
 	return change.records[0].codeplug
 }
 
@@ -80,7 +93,12 @@ func (change *Change) FieldType() FieldType {
 	return change.fields[0].fType
 }
 
+// RecordType returns the type of the change's records.  A
+// BytesChange has no records, so its record type is empty.
 func (change *Change) RecordType() RecordType {
+	if len(change.records) == 0 {
+		return ""
+	}
 	return change.records[0].rType
 }
 
@@ -165,6 +183,8 @@ const (
 	InsertFieldsChange  ChangeType = "InsertFieldsChange"
 	RemoveFieldsChange  ChangeType = "RemoveFieldsChange"
 	ListIndexChange     ChangeType = "ListIndexChange"
+	BatchFieldChange    ChangeType = "BatchFieldChange"
+	BytesChange         ChangeType = "BytesChange"
 )
 
 func fieldChange(f *Field, previousValue string) *Change {
@@ -210,11 +230,108 @@ func listIndexChange(r *Record, fields []*Field) *Change {
 	return &change
 }
 
+// SetFields sets the fields of the given type in each of the given
+// records to value, as a single change that may be undone.  The value
+// is checked against every record before any field is set, so if an
+// error is returned, the codeplug is unchanged.
+func (cp *Codeplug) SetFields(records []*Record, fType FieldType, value string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	rType := records[0].rType
+	for _, r := range records {
+		if r.rType != rType {
+			return fmt.Errorf("records are not all of type %s", rType)
+		}
+		fd := (*r.fDesc)[fType]
+		if fd == nil || len(fd.fields) != 1 || fd.max != 1 {
+			return fmt.Errorf("%s has no single %s field", rType, fType)
+		}
+		_, err := r.NewFieldWithValue(fType, 0, value)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %s", r.Name(), fd.typeName, err)
+		}
+	}
+
+	change := Change{
+		cType:        BatchFieldChange,
+		records:      records,
+		afterStrings: []string{value},
+	}
+	for _, r := range records {
+		f := r.Field(fType)
+		previousValue := f.String()
+		f.SetString(value)
+		change.fields = append(change.fields, f)
+		change.strings = append(change.strings, previousValue)
+		change.changes = append(change.changes, fieldChange(f, previousValue))
+	}
+	change.Complete()
+
+	return nil
+}
+
+// SetFieldStrings sets each of the given fields to the corresponding
+// value, as a single change that may be undone.  The fields may be of
+// any types, in any records of the same type.  Each value is checked before any
+// field is set, so if an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) SetFieldStrings(fields []*Field, values []string) error {
+	if len(fields) != len(values) {
+		return fmt.Errorf("%d fields but %d values", len(fields), len(values))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	rType := fields[0].record.rType
+	seen := make(map[*Field]bool)
+	for i, f := range fields {
+		r := f.record
+		if r.rType != rType {
+			return fmt.Errorf("records are not all of type %s", rType)
+		}
+		if seen[f] {
+			return fmt.Errorf("%s: %s is set more than once",
+				r.Name(), f.typeName)
+		}
+		seen[f] = true
+		_, err := r.NewFieldWithValue(f.fType, f.fIndex, values[i])
+		if err != nil {
+			return fmt.Errorf("%s: %s: %s", r.Name(), f.typeName, err)
+		}
+	}
+
+	change := Change{
+		cType:        BatchFieldChange,
+		afterStrings: values,
+	}
+	inChange := make(map[*Record]bool)
+	for i, f := range fields {
+		previousValue := f.String()
+		err := f.SetString(values[i])
+		if err != nil {
+			cp.undoListIndexChanges(&change)
+			return fmt.Errorf("%s: %s: %s", f.record.Name(), f.typeName, err)
+		}
+		if !inChange[f.record] {
+			inChange[f.record] = true
+			change.records = append(change.records, f.record)
+		}
+		change.fields = append(change.fields, f)
+		change.strings = append(change.strings, previousValue)
+		change.changes = append(change.changes, fieldChange(f, previousValue))
+	}
+	change.Complete()
+
+	return nil
+}
+
 func (f *Field) Change(previousValue string) *Change {
 	cp := f.record.codeplug
 
 	change := cp.currentChange()
-	if change != nil && change.cType == FieldChange &&
+	if change != nil && change.cType == FieldChange && !change.remote &&
 		change.Field() == f && f.String() != change.previousValue() &&
 		change.previousValue() != invalidValueString {
 
@@ -266,13 +383,7 @@ func (cp *Codeplug) RemoveRecordsChange(records []*Record) *Change {
 }
 
 func (cp *Codeplug) listIndexChanges(change *Change) []*Change {
-	rType := change.RecordType()
-	fType := change.FieldType()
-	changes := []*Change{}
-	for _, rd := range cp.rDesc {
-		changes = append(changes, rDescChanges(rd, rType, fType)...)
-	}
-	return changes
+	return cp.ReferenceGraph().referenceChanges(change.RecordType())
 }
 
 func rDescChanges(rd *rDesc, rType RecordType, fType FieldType) []*Change {
@@ -317,36 +428,42 @@ func (cp *Codeplug) updateListIndexChanges(changes []*Change) []*Change {
 			i--
 			continue
 		}
-		r := change.Record()
-		fType := change.FieldType()
-		fields := r.Fields(fType)
-		for i := len(fields) - 1; i >= 0; i-- {
-			r.RemoveField(fields[i])
-		}
-		for i, str := range changeStrings {
-			f, err := r.NewFieldWithValue(fType, i, str)
-			if err == nil {
-				r.addField(f)
-			}
-		}
-		if len(r.Fields(fType)) == 0 {
-			fd := (*r.fDesc)[fType]
-			indexedStrings := fd.indexedStrings
-			if indexedStrings != nil {
-				iStrs := *indexedStrings
-				str := iStrs[len(iStrs)-1].String
-				f, _ := r.NewFieldWithValue(fType, 0, str)
-				r.addField(f)
-			}
+		change.afterStrings = setListFields(change.Record(), change.FieldType(), changeStrings)
+	}
+	return changes
+}
+
+// setListFields replaces the record's fields of the given type with
+// fields having the given values, skipping any invalid values, and
+// returns the values of the new fields.
+func setListFields(r *Record, fType FieldType, strs []string) []string {
+	fields := r.Fields(fType)
+	for i := len(fields) - 1; i >= 0; i-- {
+		r.RemoveField(fields[i])
+	}
+	for i, str := range strs {
+		f, err := r.NewFieldWithValue(fType, i, str)
+		if err == nil {
+			r.addField(f)
 		}
-		fields = r.Fields(fType)
-		strings := make([]string, len(fields))
-		for i, f := range fields {
-			strings[i] = f.String()
+	}
+	if len(r.Fields(fType)) == 0 {
+		fd := (*r.fDesc)[fType]
+		indexedStrings := fd.indexedStrings
+		if indexedStrings != nil {
+			iStrs := *indexedStrings
+			str := iStrs[len(iStrs)-1].String
+			f, _ := r.NewFieldWithValue(fType, 0, str)
+			r.addField(f)
 		}
-		change.afterStrings = strings
 	}
-	return changes
+	fields = r.Fields(fType)
+	strings := make([]string, len(fields))
+	for i, f := range fields {
+		strings[i] = f.String()
+	}
+
+	return strings
 }
 
 func (cp *Codeplug) addChange(change *Change) {
@@ -364,17 +481,21 @@ func (cp *Codeplug) addChange(change *Change) {
 }
 
 func (change *Change) Complete() {
+	change.add()
+	change.Codeplug().publishChange(change)
+}
+
+// add adds the change, if it is new, to its codeplug's change list.
+func (change *Change) add() {
 	switch change.cType {
 	case InsertRecordsChange, InsertFieldsChange:
 		change.strings = change.refStrings()
 	}
 
-	r := change.Record()
-	cp := r.codeplug
+	cp := change.Codeplug()
 	if change != cp.currentChange() {
 		cp.addChange(change)
 	}
-	cp.publishChange(change)
 }
 
 func (cp *Codeplug) FindRecordByName(rType RecordType, name string) *Record {
@@ -463,6 +584,10 @@ func (cp *Codeplug) UndoString() string {
 	}
 
 	change := changeList[changeIndex]
+	if change.cType == BytesChange {
+		return change.bytesString(change.strings[0], change.afterStrings[0])
+	}
+
 	r := change.Record()
 	rTypeName := r.TypeName()
 	rName := r.Name()
@@ -477,6 +602,9 @@ func (cp *Codeplug) UndoString() string {
 		str = fmt.Sprintf("%s.%s: %s: %s -> %s",
 			rTypeName, rName, fTypeName, prevVal, value)
 
+	case BatchFieldChange:
+		str = change.batchString()
+
 	case MoveRecordsChange:
 		names := maxNamesString(recordNames(change.records), 5)
 		ref := change.undoReference()
@@ -534,6 +662,10 @@ func (cp *Codeplug) RedoString() string {
 	}
 
 	change := changeList[changeIndex+1]
+	if change.cType == BytesChange {
+		return change.bytesString(change.afterStrings[0], change.strings[0])
+	}
+
 	r := change.Record()
 	rTypeName := r.TypeName()
 	rName := r.Name()
@@ -548,6 +680,9 @@ func (cp *Codeplug) RedoString() string {
 		str = fmt.Sprintf("%s.%s: %s: %s -> %s",
 			rTypeName, rName, fName, value, prevVal)
 
+	case BatchFieldChange:
+		str = change.batchString()
+
 	case MoveRecordsChange:
 		names := maxNamesString(recordNames(change.records), 5)
 		ref := change.redoReference()
@@ -585,6 +720,49 @@ func (cp *Codeplug) RedoString() string {
 	return str
 }
 
+// batchString returns a description of a BatchFieldChange.
+func (change *Change) batchString() string {
+	rTypeName := change.Record().TypeName()
+	fTypeName := change.fields[0].TypeName()
+	names := maxNamesString(recordNames(change.records), 5)
+
+	switch {
+	case change.mixedFieldTypes():
+		return fmt.Sprintf("%s: set %d fields on %s",
+			rTypeName, len(change.fields), names)
+
+	case len(change.afterStrings) > 1:
+		return fmt.Sprintf("%s: %s: set %d values on %s",
+			rTypeName, fTypeName, len(change.fields), names)
+	}
+
+	return fmt.Sprintf("%s: %s: set to %s on %s",
+		rTypeName, fTypeName, change.afterStrings[0], names)
+}
+
+// batchValue returns the value to which a BatchFieldChange sets its
+// i'th field.  A change made by SetFields sets all of its fields to
+// the same value.
+func (change *Change) batchValue(i int) string {
+	if len(change.afterStrings) == 1 {
+		return change.afterStrings[0]
+	}
+
+	return change.afterStrings[i]
+}
+
+// mixedFieldTypes returns true if the change's fields are not all of
+// the same type.
+func (change *Change) mixedFieldTypes() bool {
+	for _, f := range change.fields {
+		if f.fType != change.fields[0].fType {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (cp *Codeplug) UndoChange() {
 	changeList := cp.changeList
 	index := cp.changeIndex
@@ -602,6 +780,7 @@ func (cp *Codeplug) UndoChange() {
 
 	change := changeList[index]
 	change = cp.undoChange(change)
+	change.remote = false
 	cp.publishChange(change)
 }
 
@@ -673,6 +852,7 @@ func (cp *Codeplug) undoChange(change *Change) *Change {
 
 	case InsertFieldsChange:
 		r := change.Record()
+		change.fields = change.currentFields()
 		fields := change.fields
 		for i := len(fields) - 1; i >= 0; i-- {
 			r.RemoveField(fields[i])
@@ -695,6 +875,9 @@ func (cp *Codeplug) undoChange(change *Change) *Change {
 		change = &newChange
 		change.cType = InsertFieldsChange
 
+	case BatchFieldChange:
+		cp.undoListIndexChanges(change)
+
 	case ListIndexChange:
 		fType := change.FieldType()
 		r := change.Record()
@@ -703,15 +886,19 @@ func (cp *Codeplug) undoChange(change *Change) *Change {
 			r.RemoveField(fields[i])
 		}
 		for i, str := range change.strings {
+			// Values are invalid only if read from a corrupt
+			// history, in which case they are dropped.
 			f, err := r.NewFieldWithValue(fType, i, str)
-			if err != nil {
-				log.Fatal("NewField error on ", str)
+			if err == nil {
+				r.addField(f)
 			}
-			r.addField(f)
 		}
 		c := change
 		c.strings, c.afterStrings = c.afterStrings, c.strings
 
+	case BytesChange:
+		cp.applyBytesChange(change)
+
 	default:
 		log.Fatal("Undo: unexpected change type:", cType)
 	}
@@ -737,6 +924,7 @@ func (cp *Codeplug) RedoChange() {
 
 	change := changeList[index]
 	change = cp.redoChange(change)
+	change.remote = false
 	cp.publishChange(change)
 }
 
@@ -811,6 +999,7 @@ func (cp *Codeplug) redoChange(change *Change) *Change {
 
 	case RemoveFieldsChange:
 		r := change.Record()
+		change.fields = change.currentFields()
 		fields := change.fields
 		for i := len(fields) - 1; i >= 0; i-- {
 			r.RemoveField(fields[i])
@@ -822,6 +1011,9 @@ func (cp *Codeplug) redoChange(change *Change) *Change {
 		change = &newChange
 		change.cType = InsertFieldsChange
 
+	case BatchFieldChange:
+		cp.redoListIndexChanges(change)
+
 	case ListIndexChange:
 		fType := change.FieldType()
 		r := change.Record()
@@ -830,12 +1022,17 @@ func (cp *Codeplug) redoChange(change *Change) *Change {
 			r.RemoveField(fields[i])
 		}
 		for i, str := range change.strings {
-			f, _ := r.NewFieldWithValue(fType, i, str)
-			r.addField(f)
+			f, err := r.NewFieldWithValue(fType, i, str)
+			if err == nil {
+				r.addField(f)
+			}
 		}
 		c := change
 		c.strings, c.afterStrings = c.afterStrings, c.strings
 
+	case BytesChange:
+		cp.applyBytesChange(change)
+
 	default:
 		log.Fatal("Redo: unexpected change type:", cType)
 	}
@@ -843,6 +1040,26 @@ func (cp *Codeplug) redoChange(change *Change) *Change {
 	return change
 }
 
+// currentFields returns the change's fields as they are now found in
+// its record.  Undoing or redoing a ListIndexChange replaces the fields
+// of its record, so an earlier change may refer to fields that have
+// since been replaced by equal ones.
+func (change *Change) currentFields() []*Field {
+	r := change.Record()
+	fields := make([]*Field, len(change.fields))
+	for i, f := range change.fields {
+		if !f.inRecord() {
+			found := r.FindFieldByName(f.fType, f.String())
+			if found != nil {
+				f = found
+			}
+		}
+		fields[i] = f
+	}
+
+	return fields
+}
+
 func (cp *Codeplug) undoListIndexChanges(change *Change) {
 	for _, change = range change.changes {
 		cp.undoChange(change)
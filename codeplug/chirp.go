@@ -0,0 +1,367 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A SkippedRow describes a row of a CSV file that was not imported.
+// Location is the row's CHIRP location, if any.
+type SkippedRow struct {
+	Line     int
+	Location string
+	Reason   string
+}
+
+// String returns a description of the skipped row.
+func (s SkippedRow) String() string {
+	if s.Location == "" {
+		return fmt.Sprintf("line %d: %s", s.Line, s.Reason)
+	}
+
+	return fmt.Sprintf("line %d (location %s): %s", s.Line, s.Location, s.Reason)
+}
+
+// ImportChirpCsvFrom appends the channels read from the named CHIRP
+// CSV file to the codeplug.  See ImportChirpCsv.
+func (cp *Codeplug) ImportChirpCsvFrom(filename string) ([]SkippedRow, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return cp.ImportChirpCsv(file)
+}
+
+// ImportChirpCsv appends the channels read, in CHIRP's CSV form, from
+// rdr to the codeplug, as a single change that may be undone.  Rows
+// that cannot be represented as channels are skipped and returned.
+// If an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) ImportChirpCsv(rdr io.Reader) ([]SkippedRow, error) {
+	records, skipped, err := cp.ParseChirpCsv(rdr)
+	if err != nil || len(records) == 0 {
+		return skipped, err
+	}
+
+	change := cp.InsertRecordsChange(records)
+	for i, r := range records {
+		r.rIndex = len(cp.Records(r.rType))
+		err := cp.InsertRecord(r)
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				cp.RemoveRecord(records[j])
+			}
+			return skipped, fmt.Errorf("%s: %s", r.Name(), err)
+		}
+	}
+	change.Complete()
+
+	return skipped, nil
+}
+
+// ParseChirpCsv reads channels, in CHIRP's CSV form, from rdr and
+// returns them as analog ChannelInformation records that have not yet
+// been inserted into the codeplug.  Settings that CHIRP doesn't
+// describe are copied from the codeplug's first channel.  Rows that
+// cannot be represented as channels, including those that would exceed
+// the codeplug's maximum number of channels, are skipped and returned.
+func (cp *Codeplug) ParseChirpCsv(rdr io.Reader) ([]*Record, []SkippedRow, error) {
+	channels := cp.Records(RtChannelInformation)
+	if len(channels) == 0 {
+		return nil, nil, fmt.Errorf("no %s records found", RtChannelInformation)
+	}
+	template := channels[0].recordBytes()
+
+	csvRdr := csv.NewReader(rdr)
+	csvRdr.FieldsPerRecord = -1
+	csvRdr.TrimLeadingSpace = true
+
+	header, err := csvRdr.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("no CSV header found")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["Frequency"]; !ok {
+		return nil, nil, fmt.Errorf("no Frequency column found")
+	}
+
+	var records []*Record
+	var skipped []SkippedRow
+	room := cp.MaxRecords(RtChannelInformation) - len(channels)
+
+	for line := 2; ; line++ {
+		row, err := csvRdr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c := chirpRow{columns, row}
+		if c.value("Frequency") == "" {
+			continue
+		}
+
+		skip := func(reason string) {
+			skipped = append(skipped, SkippedRow{
+				Line:     line,
+				Location: c.value("Location"),
+				Reason:   reason,
+			})
+		}
+
+		if len(records) >= room {
+			skip("too many channels")
+			continue
+		}
+
+		r := cp.bytesToRecord(RtChannelInformation, 0, template)
+		err = c.setChannel(r)
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+
+		records = append(records, r)
+	}
+
+	return records, skipped, nil
+}
+
+// A chirpRow is a row of a CHIRP CSV file.
+type chirpRow struct {
+	columns map[string]int
+	row     []string
+}
+
+// value returns the row's value in the named column, or "" if the
+// file has no such column.
+func (c chirpRow) value(column string) string {
+	i, ok := c.columns[column]
+	if !ok || i >= len(c.row) {
+		return ""
+	}
+
+	return strings.TrimSpace(c.row[i])
+}
+
+// setChannel sets the fields of the analog channel r from the row.
+func (c chirpRow) setChannel(r *Record) error {
+	rxFreq, err := stringToFrequency(c.value("Frequency"))
+	if err != nil {
+		return err
+	}
+
+	txFreq := rxFreq
+	rxOnly := "Off"
+	switch c.value("Duplex") {
+	case "":
+	case "+", "-", "split":
+		offset, err := stringToFrequency(c.value("Offset"))
+		if err != nil {
+			return fmt.Errorf("bad offset: %s", c.value("Offset"))
+		}
+		switch c.value("Duplex") {
+		case "+":
+			txFreq = rxFreq + offset
+		case "-":
+			txFreq = rxFreq - offset
+		case "split":
+			txFreq = offset
+		}
+	case "off":
+		rxOnly = "On"
+	default:
+		return fmt.Errorf("unsupported duplex: %s", c.value("Duplex"))
+	}
+
+	var bandwidth string
+	switch c.value("Mode") {
+	case "", "FM":
+		bandwidth = "25"
+	case "NFM":
+		bandwidth = "12.5"
+	default:
+		return fmt.Errorf("unsupported mode: %s", c.value("Mode"))
+	}
+
+	encode, decode, err := c.tones()
+	if err != nil {
+		return err
+	}
+
+	name := c.value("Name")
+	if name == "" {
+		name = frequencyToString(rxFreq)
+	}
+	name = truncateName(r.NameField(), name)
+
+	values := []struct {
+		fType FieldType
+		str   string
+	}{
+		{FtChannelMode, "Analog"},
+		{FtChannelName, name},
+		{FtRxFrequency, frequencyToString(rxFreq)},
+		{FtTxFrequency, frequencyToString(txFreq)},
+		{FtRxOnly, rxOnly},
+		{FtBandwidth, bandwidth},
+		{FtCtcssEncode, encode},
+		{FtCtcssDecode, decode},
+	}
+
+	for _, v := range values {
+		f := r.Field(v.fType)
+		err := f.SetString(v.str)
+		if err != nil {
+			return fmt.Errorf("bad %s: %s: %s", f.typeName, v.str, err)
+		}
+	}
+
+	return nil
+}
+
+// tones returns the ctcssDcs strings of the row's transmit and receive
+// tones.
+func (c chirpRow) tones() (encode string, decode string, err error) {
+	encode = "None"
+	decode = "None"
+
+	ctcss := func(column string) (string, error) {
+		s := c.value(column)
+		freq, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return "", fmt.Errorf("bad %s: %s", column, s)
+		}
+		s = strconv.FormatFloat(freq, 'f', 1, 64)
+		if ctcssDcsStringToBinary(s) < 0 {
+			return "", fmt.Errorf("unsupported CTCSS tone: %s", s)
+		}
+		return s, nil
+	}
+
+	polarity := c.value("DtcsPolarity")
+	if polarity == "" {
+		polarity = "NN"
+	}
+	if len(polarity) != 2 {
+		return "", "", fmt.Errorf("bad DtcsPolarity: %s", polarity)
+	}
+
+	dcs := func(column string, polarity byte) (string, error) {
+		s := c.value(column)
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return "", fmt.Errorf("bad %s: %s", column, s)
+		}
+		suffix := "N"
+		if polarity == 'R' {
+			suffix = "I"
+		}
+		s = fmt.Sprintf("D%03d%s", code, suffix)
+		if ctcssDcsStringToBinary(s) < 0 {
+			return "", fmt.Errorf("unsupported DCS code: %s", s)
+		}
+		return s, nil
+	}
+
+	switch c.value("Tone") {
+	case "":
+
+	case "Tone":
+		encode, err = ctcss("rToneFreq")
+
+	case "TSQL":
+		encode, err = ctcss("cToneFreq")
+		decode = encode
+
+	case "DTCS":
+		encode, err = dcs("DtcsCode", polarity[0])
+		if err == nil {
+			decode, err = dcs("DtcsCode", polarity[1])
+		}
+
+	case "Cross":
+		rxDcsColumn := "RxDtcsCode"
+		if c.value(rxDcsColumn) == "" {
+			rxDcsColumn = "DtcsCode"
+		}
+
+		crossMode := c.value("CrossMode")
+		if crossMode == "" {
+			crossMode = "Tone->Tone"
+		}
+		modes := strings.Split(crossMode, "->")
+		if len(modes) != 2 {
+			return "", "", fmt.Errorf("unsupported cross mode: %s", crossMode)
+		}
+
+		switch modes[0] {
+		case "":
+		case "Tone":
+			encode, err = ctcss("rToneFreq")
+		case "DTCS":
+			encode, err = dcs("DtcsCode", polarity[0])
+		default:
+			err = fmt.Errorf("unsupported cross mode: %s", crossMode)
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		switch modes[1] {
+		case "":
+		case "Tone":
+			decode, err = ctcss("cToneFreq")
+		case "DTCS":
+			decode, err = dcs(rxDcsColumn, polarity[1])
+		default:
+			err = fmt.Errorf("unsupported cross mode: %s", crossMode)
+		}
+
+	default:
+		err = fmt.Errorf("unsupported tone mode: %s", c.value("Tone"))
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return encode, decode, nil
+}
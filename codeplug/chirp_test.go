@@ -0,0 +1,140 @@
+package codeplug
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const chirpCsv = `Location,Name,Frequency,Duplex,Offset,Tone,rToneFreq,cToneFreq,DtcsCode,DtcsPolarity,RxDtcsCode,CrossMode,Mode
+1,Simplex,146.520000,,0.000000,,88.5,88.5,023,NN,023,Tone->Tone,FM
+2,Plus,146.940000,+,0.600000,Tone,100.0,88.5,023,NN,023,Tone->Tone,FM
+3,Minus,147.240000,-,0.600000,TSQL,88.5,123.0,023,NN,023,Tone->Tone,NFM
+4,Split,145.500000,split,146.100000,,88.5,88.5,023,NN,023,Tone->Tone,FM
+5,Listen,162.550000,off,0.000000,,88.5,88.5,023,NN,023,Tone->Tone,FM
+6,Dcs,146.460000,,0.000000,DTCS,88.5,88.5,023,NR,023,Tone->Tone,FM
+7,Cross,146.480000,,0.000000,Cross,88.5,88.5,025,NN,031,DTCS->DTCS,FM
+8,ToneDcs,146.500000,,0.000000,Cross,67.0,88.5,023,RN,754,Tone->DTCS,FM
+9,BadDuplex,146.540000,x,0.000000,,88.5,88.5,023,NN,023,Tone->Tone,FM
+10,BadTone,146.560000,,0.000000,Tone,12.3,88.5,023,NN,023,Tone->Tone,FM
+11,BadMode,146.580000,,0.000000,,88.5,88.5,023,NN,023,Tone->Tone,AM
+12,,,,,,,,,,,,
+`
+
+func TestImportChirpCsv(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	skipped, err := cp.ImportChirpCsv(strings.NewReader(chirpCsv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSkipped := []SkippedRow{
+		{10, "9", "unsupported duplex: x"},
+		{11, "10", "unsupported CTCSS tone: 12.3"},
+		{12, "11", "unsupported mode: AM"},
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped %v, not %v", skipped, wantSkipped)
+	}
+
+	fTypes := []FieldType{
+		FtRxFrequency, FtTxFrequency, FtRxOnly, FtBandwidth,
+		FtCtcssEncode, FtCtcssDecode,
+	}
+	want := map[string][]string{
+		"Simplex": {"146.52000", "146.52000", "Off", "25", "None", "None"},
+		"Plus":    {"146.94000", "147.54000", "Off", "25", "100.0", "None"},
+		"Minus":   {"147.24000", "146.64000", "Off", "12.5", "123.0", "123.0"},
+		"Split":   {"145.50000", "146.10000", "Off", "25", "None", "None"},
+		"Listen":  {"162.55000", "162.55000", "On", "25", "None", "None"},
+		"Dcs":     {"146.46000", "146.46000", "Off", "25", "D023N", "D023I"},
+		"Cross":   {"146.48000", "146.48000", "Off", "25", "D025N", "D031N"},
+		"ToneDcs": {"146.50000", "146.50000", "Off", "25", "67.0", "D754N"},
+	}
+
+	channels := cp.Records(RtChannelInformation)
+	if len(channels) != 1+len(want) {
+		t.Fatalf("%d channels, not %d", len(channels), 1+len(want))
+	}
+	for name, strs := range want {
+		r := cp.FindRecordByName(RtChannelInformation, name)
+		if r == nil {
+			t.Errorf("no channel %s", name)
+			continue
+		}
+		for i, fType := range fTypes {
+			if s := r.Field(fType).String(); s != strs[i] {
+				t.Errorf("%s %s is %s, not %s", name, fType, s, strs[i])
+			}
+		}
+		if mode := r.Field(FtChannelMode).String(); mode != "Analog" {
+			t.Errorf("%s %s is %s", name, FtChannelMode, mode)
+		}
+	}
+
+	cp.UndoChange()
+	if n := len(cp.Records(RtChannelInformation)); n != 1 {
+		t.Errorf("%d channels after undo", n)
+	}
+}
+
+func TestImportChirpCsvTooMany(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	max := cp.MaxRecords(RtChannelInformation)
+	for i := 2; i <= max-3; i++ {
+		addRecords(t, cp, RtChannelInformation, fmt.Sprint("Channel ", i))
+	}
+
+	csv := "Location,Frequency\n"
+	for i := 0; i < 5; i++ {
+		csv += fmt.Sprintf("%d,146.520000\n", i+1)
+	}
+	skipped, err := cp.ImportChirpCsv(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skipped) != 2 || skipped[0].Reason != "too many channels" {
+		t.Errorf("skipped %v", skipped)
+	}
+	if n := len(cp.Records(RtChannelInformation)); n != max {
+		t.Errorf("%d channels", n)
+	}
+}
+
+func TestImportChirpCsvRollback(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	// Only 53 channels can share a name, as Same, Same.a ... Same.Z.
+	csv := "Location,Name,Frequency\n"
+	for i := 0; i < 54; i++ {
+		csv += fmt.Sprintf("%d,Same,146.520000\n", i+1)
+	}
+	before := exportString(t, cp)
+	changes := len(cp.changeList)
+
+	_, err = cp.ImportChirpCsv(strings.NewReader(csv))
+	if err == nil {
+		t.Fatal("import succeeded")
+	}
+	if after := exportString(t, cp); after != before {
+		t.Errorf("codeplug changed by failed import")
+	}
+	if len(cp.changeList) != changes {
+		t.Errorf("failed import was recorded as a change")
+	}
+}
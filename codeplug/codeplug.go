@@ -25,8 +25,11 @@
 // It can read/update/write both .rdt files and .bin files.
 package codeplug
 
+//go:generate genCodeplugInfo -markdown fields.md -html fields.html codeplugs.json
+
 import (
 	"bufio"
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"io"
@@ -36,11 +39,13 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
 	"unicode"
 )
 
-const fileSizeRdt = 262709
-const fileSizeBin = 262144
+// A bin file holds the contents of an rdt file, less its header and
+// trailer.  File sizes vary with the codeplug type and are found in
+// cpInfos.
 const fileOffsetRdt = 0
 const fileOffsetBin = 549
 
@@ -53,8 +58,9 @@ const (
 	FileTypeBin
 )
 
-// A CodeplugType contain the type of codeplug. Currently only MD380-style
-// codeplugs are supported.
+// A CodeplugType contain the type of codeplug, that is, the radio model.
+// MD380-style codeplugs, MD390-style codeplugs with GPS records, and
+// dual-band MD-UV380-style codeplugs are supported.
 type CodeplugType string
 
 // A Codeplug represents a codeplug file.
@@ -70,28 +76,51 @@ type Codeplug struct {
 	codeplugType  CodeplugType
 	changed       bool
 	lowFrequency  float64
+	bandPlan      *BandPlan
 	highFrequency float64
 	connectChange func(*Change)
+	subscribers   []*changeSubscriber
 	changeList    []*Change
 	changeIndex   int
+	nameToRt      map[string]RecordType
+	nameToFt      map[RecordType]map[string]FieldType
+	deferredValid []*Field
+	byteEdits     map[int]byte
 }
 
 // NewCodeplug returns a Codeplug, given a filename and codeplug type.
 func NewCodeplug(filename string, cpType CodeplugType) (*Codeplug, error) {
-	var err error
-	cp := new(Codeplug)
-	cp.filename = filename
-	cp.codeplugType = cpType
-	cp.rDesc = make(map[RecordType]*rDesc)
-	cp.changeList = []*Change{&Change{}}
-	cp.changeIndex = 0
+	cp, err := newCodeplug(cpType)
+	if err != nil {
+		return nil, err
+	}
 
-	cp.id, err = randomString(64)
+	cp.bytes, err = cp.Open(filename, cpType)
 	if err != nil {
 		return nil, err
 	}
 
-	cp.bytes, err = cp.Open(cp.filename, cp.codeplugType)
+	if err = cp.Revert(); err != nil {
+		return nil, err
+	}
+
+	codeplugsMutex.Lock()
+	codeplugs = append(codeplugs, cp)
+	codeplugsMutex.Unlock()
+
+	return cp, nil
+}
+
+// NewCodeplugFromReader returns a Codeplug of the given codeplug type,
+// reading the contents of an rdt or bin file from rdr.  The file type
+// is determined from the contents read.
+func NewCodeplugFromReader(rdr io.Reader, cpType CodeplugType) (*Codeplug, error) {
+	cp, err := newCodeplug(cpType)
+	if err != nil {
+		return nil, err
+	}
+
+	cp.bytes, err = cp.OpenReader(rdr, cpType)
 	if err != nil {
 		return nil, err
 	}
@@ -100,18 +129,65 @@ func NewCodeplug(filename string, cpType CodeplugType) (*Codeplug, error) {
 		return nil, err
 	}
 
+	codeplugsMutex.Lock()
 	codeplugs = append(codeplugs, cp)
+	codeplugsMutex.Unlock()
+
+	return cp, nil
+}
+
+// NewCodeplugFromBytes returns a Codeplug of the given codeplug type,
+// given the contents of an rdt or bin file.  The file type is determined
+// from the contents.
+func NewCodeplugFromBytes(fileBytes []byte, cpType CodeplugType) (*Codeplug, error) {
+	return NewCodeplugFromReader(bytes.NewReader(fileBytes), cpType)
+}
+
+// newCodeplug returns a new, empty, Codeplug of the given type.
+func newCodeplug(cpType CodeplugType) (*Codeplug, error) {
+	if _, ok := cpInfos[cpType]; !ok {
+		return nil, fmt.Errorf("unknown codeplug type: %s", cpType)
+	}
+
+	var err error
+	cp := new(Codeplug)
+	cp.codeplugType = cpType
+	cp.rDesc = make(map[RecordType]*rDesc)
+	cp.changeList = []*Change{&Change{}}
+	cp.changeIndex = 0
+
+	cp.nameToRt = make(map[string]RecordType)
+	cp.nameToFt = make(map[RecordType]map[string]FieldType)
+	for _, ri := range cpTypes[cpType] {
+		cp.nameToRt[string(ri.rType)] = ri.rType
+		m := make(map[string]FieldType)
+		for _, fi := range ri.fInfos {
+			m[string(fi.fType)] = fi.fType
+		}
+		cp.nameToFt[ri.rType] = m
+	}
+
+	cp.id, err = randomString(64)
+	if err != nil {
+		return nil, err
+	}
 
 	return cp, nil
 }
 
 // Codeplugs return a slice containing all currently open codeplugs.
 func Codeplugs() []*Codeplug {
-	return codeplugs
+	codeplugsMutex.Lock()
+	defer codeplugsMutex.Unlock()
+
+	return append([]*Codeplug(nil), codeplugs...)
 }
 
 // Free frees a codeplug
 func (cp *Codeplug) Free() {
+	codeplugsMutex.Lock()
+	defer codeplugsMutex.Unlock()
+
 	for i, codeplug := range codeplugs {
 		if cp == codeplug {
 			codeplugs = append(codeplugs[:i], codeplugs[i+1:]...)
@@ -128,44 +204,60 @@ func (cp *Codeplug) Free() {
 // that type.
 func (cp *Codeplug) Open(filename string, cpType CodeplugType) ([]byte, error) {
 	cp.filename = filename
+
+	file, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("%s: does not exist", filename)
+		}
+		return []byte{}, err
+	}
+	defer file.Close()
+
+	cpBytes, err := cp.OpenReader(file, cpType)
+	switch err {
+	case nil:
+	case errBadFileType:
+		err = fmt.Errorf("%s is not a valid rdt or bin file", filename)
+	default:
+		err = fmt.Errorf("Failed to read all of %s: %s", filename, err)
+	}
+
+	return cpBytes, err
+}
+
+// OpenReader reads the contents of an rdt or bin file of the given
+// codeplug type from rdr.  It returns the contents of the codeplug,
+// in rdt form.  An error is returned if the contents read do not
+// represent a valid codeplug of that type.
+func (cp *Codeplug) OpenReader(rdr io.Reader, cpType CodeplugType) ([]byte, error) {
 	cp.codeplugType = cpType
+	cp.fileType = FileTypeNone
+	info := cpInfos[cpType]
 
-	fType, err := getFileType(filename)
-	cp.fileType = fType
+	// Read one extra byte, so that oversized contents are detected.
+	fileBytes, err := ioutil.ReadAll(io.LimitReader(rdr, int64(info.rdtSize+1)))
 	if err != nil {
 		return []byte{}, err
 	}
 
-	file, err := os.Open(filename)
+	fType, err := getFileType(cpType, len(fileBytes))
 	if err != nil {
 		return []byte{}, err
 	}
-	defer file.Close()
 
 	switch fType {
 	case FileTypeRdt:
-		cp.fileSize = fileSizeRdt
+		cp.fileSize = info.rdtSize
 		cp.fileOffset = fileOffsetRdt
 
 	case FileTypeBin:
-		cp.fileSize = fileSizeBin
+		cp.fileSize = info.binSize
 		cp.fileOffset = fileOffsetBin
 	}
 
-	cpBytes := make([]byte, fileSizeRdt)
-	bytes := cpBytes[cp.fileOffset : cp.fileOffset+cp.fileSize]
-
-	bytesRead, err := file.Read(bytes)
-	if err != nil {
-		cp.fileType = FileTypeNone
-		return []byte{}, err
-	}
-
-	if bytesRead != cp.fileSize {
-		cp.fileType = FileTypeNone
-		err = fmt.Errorf("Failed to read all of %s", cp.filename)
-		return []byte{}, err
-	}
+	cpBytes := make([]byte, info.rdtSize)
+	copy(cpBytes[cp.fileOffset:cp.fileOffset+cp.fileSize], fileBytes)
 
 	cp.fileType = fType
 
@@ -173,10 +265,11 @@ func (cp *Codeplug) Open(filename string, cpType CodeplugType) ([]byte, error) {
 }
 
 // Revert reverts the codeplug to its state after the most recent open or
-// save operation.  An error is returned if the new codeplug state is
-// invalid.
+// save operation.  If the new codeplug state is invalid, a
+// *ValidationError describing the invalid fields is returned.
 func (cp *Codeplug) Revert() error {
 	cp.clearCachedListNames()
+	cp.byteEdits = nil
 
 	cp.load(cp.bytes)
 
@@ -216,7 +309,7 @@ func (cp *Codeplug) SaveAs(filename string) error {
 }
 
 // SaveToFile saves the state of the Codeplug into a named file.
-// An error will be returned if the codeplug state is invalid.
+// If the codeplug state is invalid, a *ValidationError is returned.
 // The state of the codeplug is not changed, so this
 // is useful for use by an autosave function.
 func (cp *Codeplug) SaveToFile(filename string) error {
@@ -224,17 +317,13 @@ func (cp *Codeplug) SaveToFile(filename string) error {
 		return err
 	}
 
-	cpBytes := make([]byte, fileSizeRdt)
-	copy(cpBytes, cp.bytes)
-	cp.store(cpBytes)
-
 	dir, base := filepath.Split(filename)
 	tmpFile, err := ioutil.TempFile(dir, base)
 	if err != nil {
 		return err
 	}
 
-	if err = cp.write(tmpFile, cpBytes); err != nil {
+	if err = cp.write(tmpFile); err != nil {
 		os.Remove(tmpFile.Name())
 		return err
 	}
@@ -246,6 +335,35 @@ func (cp *Codeplug) SaveToFile(filename string) error {
 	return nil
 }
 
+// WriteTo writes the state of the Codeplug to w, in the form of the
+// codeplug's file type (rdt or bin).  An error will be returned if
+// the codeplug state is invalid.  The state of the codeplug is not
+// changed.
+func (cp *Codeplug) WriteTo(w io.Writer) (int64, error) {
+	if err := cp.valid(); err != nil {
+		return 0, err
+	}
+
+	cpBytes := cp.fileBytes()
+	n, err := w.Write(cpBytes)
+	if err == nil && n != len(cpBytes) {
+		err = io.ErrShortWrite
+	}
+
+	return int64(n), err
+}
+
+// Bytes returns the state of the Codeplug in the form of the codeplug's
+// file type (rdt or bin).  An error will be returned if the codeplug
+// state is invalid.
+func (cp *Codeplug) Bytes() ([]byte, error) {
+	if err := cp.valid(); err != nil {
+		return nil, err
+	}
+
+	return cp.fileBytes(), nil
+}
+
 // Filename returns the path name of the file associated with the codeplug.
 // This is the file named in the most recent Open or SaveAs function.
 func (cp *Codeplug) Filename() string {
@@ -258,11 +376,7 @@ func (cp *Codeplug) CurrentHash() [sha256.Size]byte {
 		return cp.hash
 	}
 
-	bytes := make([]byte, fileSizeRdt)
-	copy(bytes, cp.bytes)
-	cp.store(bytes)
-
-	return sha256.Sum256(bytes)
+	return sha256.Sum256(cp.imageBytes())
 }
 
 // Changed returns false if the codeplug state is the same as that at
@@ -275,11 +389,60 @@ func (cp *Codeplug) Changed() bool {
 	return false
 }
 
+// Type returns the codeplug's type, that is, the radio model.
+func (cp *Codeplug) Type() CodeplugType {
+	return cp.codeplugType
+}
+
 // FileType returns the type of codeplug file (rdt or bin).
 func (cp *Codeplug) FileType() FileType {
 	return cp.fileType
 }
 
+// ConvertTo changes the type of file (rdt or bin) in which the codeplug
+// will subsequently be saved.  A bin file lacks the header of an rdt
+// file, so when converting from bin to rdt, the header is copied from
+// rdtTemplate, which must be an rdt codeplug of the same type.
+func (cp *Codeplug) ConvertTo(fType FileType, rdtTemplate *Codeplug) error {
+	info := cpInfos[cp.codeplugType]
+
+	switch fType {
+	case FileTypeBin:
+		cp.fileSize = info.binSize
+		cp.fileOffset = fileOffsetBin
+
+	case FileTypeRdt:
+		if cp.fileType != FileTypeRdt {
+			if rdtTemplate == nil || rdtTemplate.fileType != FileTypeRdt {
+				return fmt.Errorf("an rdt template is needed to convert to rdt")
+			}
+			if rdtTemplate.codeplugType != cp.codeplugType {
+				return fmt.Errorf("rdt template is not a %s codeplug", cp.codeplugType)
+			}
+			tBytes := rdtTemplate.imageBytes()
+			copy(cp.bytes[:fileOffsetBin], tBytes[:fileOffsetBin])
+			end := fileOffsetBin + info.binSize
+			copy(cp.bytes[end:], tBytes[end:])
+
+			cp.rDesc[RtRdtHeader].loadRecords(cp.bytes)
+			cp.lowFrequency = 0
+			cp.highFrequency = 0
+		}
+		cp.fileSize = info.rdtSize
+		cp.fileOffset = fileOffsetRdt
+
+	default:
+		return fmt.Errorf("unknown file type")
+	}
+
+	if cp.fileType != fType {
+		cp.fileType = fType
+		cp.changed = true
+	}
+
+	return nil
+}
+
 // Records returns all of a codeplug's records of the given RecordType.
 func (cp *Codeplug) Records(rType RecordType) []*Record {
 	return cp.rDesc[rType].records
@@ -290,12 +453,37 @@ func (cp *Codeplug) Record(rType RecordType) *Record {
 	return cp.Records(rType)[0]
 }
 
+// RecordTypeName returns the name of the given record type.  Unlike
+// the TypeName of a record, it may be used when the codeplug has no
+// records of the type.
+func (cp *Codeplug) RecordTypeName(rType RecordType) string {
+	return cp.rDesc[rType].typeName
+}
+
+// FieldTypeName returns the name of the given field type of records of
+// the given type, or "" if they have no such field.
+func (cp *Codeplug) FieldTypeName(rType RecordType, fType FieldType) string {
+	for _, fi := range cp.rDesc[rType].fInfos {
+		if fi.fType == fType {
+			return fi.typeName
+		}
+	}
+
+	return ""
+}
+
 // MaxRecords returns a codeplug's maximum number of records of the given
 // Recordtype.
 func (cp *Codeplug) MaxRecords(rType RecordType) int {
 	return cp.rDesc[rType].max
 }
 
+// HasRecordType returns true if the codeplug's type includes records of
+// the given RecordType.
+func (cp *Codeplug) HasRecordType(rType RecordType) bool {
+	return cp.rDesc[rType] != nil
+}
+
 // RecordTypes returns all of the record types of the codeplug.
 func (cp *Codeplug) RecordTypes() []RecordType {
 	strs := make([]string, 0, len(cp.rDesc)-1)
@@ -315,6 +503,38 @@ func (cp *Codeplug) RecordTypes() []RecordType {
 	return rTypes
 }
 
+// CodeplugTypesForSize returns the codeplug types whose rdt or bin
+// files have the given size, in sorted order.  Several types may share
+// a file size, as do the MD380 and MD390.
+func CodeplugTypesForSize(size int) []CodeplugType {
+	var cpTypes []CodeplugType
+
+	for _, cpType := range CodeplugTypes() {
+		if _, err := getFileType(cpType, size); err == nil {
+			cpTypes = append(cpTypes, cpType)
+		}
+	}
+
+	return cpTypes
+}
+
+// CodeplugTypes returns all of the supported codeplug types.
+func CodeplugTypes() []CodeplugType {
+	strs := make([]string, 0, len(cpTypes))
+
+	for cpType := range cpTypes {
+		strs = append(strs, string(cpType))
+	}
+	sort.Strings(strs)
+
+	cpTypes := make([]CodeplugType, len(strs))
+	for i, str := range strs {
+		cpTypes[i] = CodeplugType(str)
+	}
+
+	return cpTypes
+}
+
 // ID returns a string unique to the codeplug.
 func (cp *Codeplug) ID() string {
 	return cp.id
@@ -343,9 +563,11 @@ func (cp *Codeplug) InsertRecord(r *Record) error {
 		return fmt.Errorf("too many records")
 	}
 
-	err := r.makeNameUnique(*r.ListNames())
-	if err != nil {
-		return err
+	if r.NameField() != nil {
+		err := r.makeNameUnique(*r.ListNames())
+		if err != nil {
+			return err
+		}
 	}
 
 	i := r.rIndex
@@ -360,6 +582,28 @@ func (cp *Codeplug) InsertRecord(r *Record) error {
 	return nil
 }
 
+// appendRecords appends the given records, which must be of the same
+// type and have names unique among the records of that type, to the
+// codeplug.  Unlike InsertRecord, it doesn't compare each record's name
+// with those of all of the others, so it is suitable for appending
+// thousands of records.  An error will be returned, and no records
+// appended, if the codeplug's maximum records of that type would be
+// exceeded.
+func (cp *Codeplug) appendRecords(records []*Record) error {
+	rd := cp.rDesc[records[0].rType]
+	if len(rd.records)+len(records) > cp.MaxRecords(rd.rType) {
+		return fmt.Errorf("too many records")
+	}
+
+	rd.records = append(rd.records, records...)
+	for i, r := range rd.records {
+		r.rIndex = i
+	}
+
+	rd.cachedListNames = nil
+	return nil
+}
+
 // RemoveRecord removes the given record from the codeplug.
 func (cp *Codeplug) RemoveRecord(r *Record) {
 	rType := r.rType
@@ -391,6 +635,36 @@ func (cp *Codeplug) ConnectChange(fn func(*Change)) {
 	cp.connectChange = fn
 }
 
+// A changeSubscriber holds a function registered by SubscribeChanges.
+type changeSubscriber struct {
+	fn func(*Change)
+}
+
+// SubscribeChanges will cause the given function, like that registered
+// by ConnectChange, to be called passing each change made to the
+// codeplug, including changes made by undo, redo and ReplayChange.
+// Any number of functions may be subscribed.  The returned function
+// cancels the subscription.
+func (cp *Codeplug) SubscribeChanges(fn func(*Change)) (cancel func()) {
+	s := &changeSubscriber{fn}
+	cp.subscribers = append(cp.subscribers, s)
+
+	return func() {
+		for i, subscriber := range cp.subscribers {
+			if subscriber == s {
+				cp.subscribers = append(cp.subscribers[:i:i], cp.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// deferValid records a field whose validity can't be determined until
+// all of the codeplug's records have been loaded.
+func (cp *Codeplug) deferValid(f *Field) {
+	cp.deferredValid = append(cp.deferredValid, f)
+}
+
 // bytesToRecord creates a record from the given byte slice.
 func (cp *Codeplug) bytesToRecord(rType RecordType, rIndex int, rBytes []byte) *Record {
 	r := cp.newRecord(rType, rIndex)
@@ -405,10 +679,6 @@ func (cp *Codeplug) load(cpBytes []byte) {
 
 	for i := range rInfos {
 		ri := &rInfos[i]
-		if ri.max == 0 {
-			ri.max = 1
-		}
-
 		rd := &rDesc{rInfo: ri}
 		cp.rDesc[ri.rType] = rd
 		rd.codeplug = cp
@@ -427,50 +697,24 @@ func (cp *Codeplug) newRecord(rType RecordType, rIndex int) *Record {
 	return r
 }
 
-// valid returns nil if all fields in the codeplug are valid.
-func (cp *Codeplug) valid() error {
-	errStr := ""
-	for _, rType := range cp.RecordTypes() {
-		for _, r := range cp.Records(rType) {
-			if err := r.valid(); err != nil {
-				errStr += err.Error()
-			}
-		}
-	}
-
-	for _, f := range deferredValidFields {
-		if err := f.valid(); err != nil {
-			errStr += fmt.Sprintf("%s %s\n", f.FullTypeName(), err.Error())
-		}
-	}
-
-	if errStr != "" {
-		return fmt.Errorf("%s", errStr)
-	}
+// errBadFileType is returned when codeplug file contents are neither
+// of rdt nor of bin size for the codeplug type.
+var errBadFileType = fmt.Errorf("not a valid rdt or bin file")
 
-	return nil
-}
-
-// getFileType return the type of the file corresponding to the given filename.
-func getFileType(filename string) (FileType, error) {
-	fileInfo, err := os.Stat(filename)
-	if err != nil {
-		if os.IsNotExist(err) {
-			err = fmt.Errorf("%s: does not exist", filename)
-		}
-		return FileTypeNone, err
-	}
+// getFileType returns the type of a codeplug file of the given codeplug
+// type, given its size.
+func getFileType(cpType CodeplugType, size int) (FileType, error) {
+	info := cpInfos[cpType]
 
-	switch fileInfo.Size() {
-	case fileSizeRdt:
+	switch size {
+	case info.rdtSize:
 		return FileTypeRdt, nil
 
-	case fileSizeBin:
+	case info.binSize:
 		return FileTypeBin, nil
 	}
 
-	err = fmt.Errorf("%s is not a valid rdt or bin file", filename)
-	return FileTypeNone, err
+	return FileTypeNone, errBadFileType
 }
 
 // store stores all all fields of the codeplug into its byte slice.
@@ -488,14 +732,38 @@ func (cp *Codeplug) store(cpBytes []byte) {
 	}
 }
 
-// write writes the codeplug's byte slice into the given file.
-func (cp *Codeplug) write(file *os.File, cpBytes []byte) (err error) {
+// imageBytes returns a copy of the codeplug's rdt contents, updated
+// with any bytes set by SetBytes and the current state of all of its
+// fields.
+func (cp *Codeplug) imageBytes() []byte {
+	cpBytes := make([]byte, cpInfos[cp.codeplugType].rdtSize)
+	copy(cpBytes, cp.bytes)
+	for offset, b := range cp.byteEdits {
+		cpBytes[offset] = b
+	}
+	cp.store(cpBytes)
+
+	return cpBytes
+}
+
+// fileBytes returns the current contents of the codeplug in the form
+// of its file type.
+func (cp *Codeplug) fileBytes() []byte {
+	cpBytes := cp.imageBytes()
+
+	return cpBytes[cp.fileOffset : cp.fileOffset+cp.fileSize]
+}
+
+// write writes the codeplug's file contents into the given file.
+func (cp *Codeplug) write(file *os.File) (err error) {
 	defer func() {
-		err = file.Close()
-		return
+		cerr := file.Close()
+		if err == nil {
+			err = cerr
+		}
 	}()
 
-	bytes := cpBytes[cp.fileOffset : cp.fileOffset+cp.fileSize]
+	bytes := cp.fileBytes()
 	bytesWritten, err := file.Write(bytes)
 	if err != nil {
 		return err
@@ -509,8 +777,20 @@ func (cp *Codeplug) write(file *os.File, cpBytes []byte) (err error) {
 }
 
 // frequencyValid returns nil if the given frequency is valid for the
-// codeplug.
+// codeplug.  Frequencies of multi-band codeplugs must lie within one
+// of the bands of the codeplug's type.  Otherwise, the frequency range
+// is taken from the rdt header, or inferred from the channels.
 func (cp *Codeplug) frequencyValid(freq float64) error {
+	bands := cpInfos[cp.codeplugType].bands
+	if bands != nil {
+		for _, r := range bands {
+			if freq >= r.Low && freq <= r.High {
+				return nil
+			}
+		}
+		return fmt.Errorf("frequency out of range %+v", freq)
+	}
+
 	if cp.lowFrequency == 0 {
 		fDescs := cp.rDesc[RtRdtHeader].records[0].fDesc
 		s := (*fDescs)[FtLowFrequency].fields[0].String()
@@ -528,15 +808,15 @@ func (cp *Codeplug) frequencyValid(freq float64) error {
 	}
 
 	r := frequencyRanges[len(frequencyRanges)-1]
-	if cp.lowFrequency != r.low || cp.highFrequency != r.high {
+	if cp.lowFrequency != r.Low || cp.highFrequency != r.High {
 		return fmt.Errorf("frequency out of range %+v", freq)
 	}
 
 	for i := 2; i <= 3; i++ {
 		r := frequencyRanges[i]
-		if freq >= r.low && freq <= r.high {
-			cp.lowFrequency = r.low
-			cp.highFrequency = r.high
+		if freq >= r.Low && freq <= r.High {
+			cp.lowFrequency = r.Low
+			cp.highFrequency = r.High
 			return nil
 		}
 	}
@@ -547,7 +827,7 @@ func (cp *Codeplug) frequencyValid(freq float64) error {
 // inferFrequencyRange guesses the frequency range of the codeplug
 // based on the current and previous values given.
 func (cp *Codeplug) inferFrequencyRange() (low float64, high float64) {
-	var rang frequencyRange
+	var rang FrequencyRange
 
 	for _, record := range cp.rDesc[RtChannelInformation].records {
 		f := (*record.fDesc)[FtRxFrequency].fields[0]
@@ -556,7 +836,7 @@ func (cp *Codeplug) inferFrequencyRange() (low float64, high float64) {
 
 		for i := len(frequencyRanges) - 1; i >= 0; i-- {
 			rang = frequencyRanges[i]
-			if rxFreq >= rang.low && rxFreq <= rang.high {
+			if rxFreq >= rang.Low && rxFreq <= rang.High {
 				index = i
 				break
 			}
@@ -567,22 +847,35 @@ func (cp *Codeplug) inferFrequencyRange() (low float64, high float64) {
 		}
 
 		if index != len(frequencyRanges)-1 {
-			return rang.low, rang.high
+			return rang.Low, rang.High
 		}
 	}
 
-	return rang.low, rang.high
+	return rang.Low, rang.High
+}
+
+// A FrequencyRange delineates a range of frequencies, in MHz.
+type FrequencyRange struct {
+	Low  float64
+	High float64
+}
+
+// String returns the frequency range in the form "400-480 MHz".
+func (r FrequencyRange) String() string {
+	return fmt.Sprintf("%g-%g MHz", r.Low, r.High)
 }
 
-// frequencyRange delineates a range of frequencies
-type frequencyRange struct {
-	low  float64
-	high float64
+// cpInfo describes the files of a codeplug type.  bands is nil for
+// single-band types, whose frequency range varies from radio to radio.
+type cpInfo struct {
+	rdtSize int
+	binSize int
+	bands   []FrequencyRange
 }
 
 // frequencyRanges contains a list of the frequency ranges
 // for the various supported codeplugs.
-var frequencyRanges = []frequencyRange{
+var frequencyRanges = []FrequencyRange{
 	{136.0, 174.0},
 	{350.0, 400.0},
 	{400.0, 480.0},
@@ -591,15 +884,22 @@ var frequencyRanges = []frequencyRange{
 }
 
 // publishChange passes the given change (with any additional generated
-// changes resulting from that change) to a registered function.
+// changes resulting from that change) to a registered function and
+// to each subscriber.
 func (cp *Codeplug) publishChange(change *Change) {
 	if cp.connectChange != nil {
 		cp.connectChange(change)
 	}
+
+	subscribers := append([]*changeSubscriber{}, cp.subscribers...)
+	for _, s := range subscribers {
+		s.fn(change)
+	}
 }
 
 // codeplugs contains the list of open codeplugs.
 var codeplugs []*Codeplug
+var codeplugsMutex sync.Mutex
 
 func PrintRecord(w io.Writer, r *Record) {
 	rType := r.Type()
@@ -620,6 +920,9 @@ func PrintRecord(w io.Writer, r *Record) {
 			fmt.Fprintf(w, "\t%s%s: %s\n", name, ind, value)
 		}
 	}
+	if r.hasUnknownBits() {
+		fmt.Fprintf(w, "\t%s: %s\n", unknownBytesName, r.unknownString())
+	}
 }
 
 func PrintRecordWithIndex(w io.Writer, r *Record) {
@@ -641,6 +944,9 @@ func PrintRecordWithIndex(w io.Writer, r *Record) {
 			fmt.Fprintf(w, " %s%s:%s", name, ind, value)
 		}
 	}
+	if r.hasUnknownBits() {
+		fmt.Fprintf(w, " %s:%s", unknownBytesName, r.unknownString())
+	}
 	fmt.Fprintln(w)
 }
 
@@ -699,9 +1005,6 @@ func printRecord(w io.Writer, r *Record, rFmt string, fFmt string) {
 	}
 }
 
-var nameToRt map[string]RecordType
-var nameToFt map[RecordType]map[string]FieldType
-
 var bareQuoteError = fmt.Errorf("bare '\"' not allowed in field value")
 var noRecordNameError = fmt.Errorf("no record name")
 var noFieldNameError = fmt.Errorf("no field name")
@@ -835,29 +1138,20 @@ func (e positionError) Error() string {
 	return fmt.Sprintf("line %d column %d: %s", line, column, str)
 }
 
+// ParseRecords returns the records read, in text form, from iRdr.  An
+// ImageBytes section is ignored.
 func (cp *Codeplug) ParseRecords(iRdr io.Reader) ([]*Record, error) {
+	return cp.parseRecords(iRdr, nil)
+}
+
+// parseRecords returns the records read, in text form, from iRdr.  The
+// regions of an ImageBytes section are passed to setRegions, unless it
+// is nil.
+func (cp *Codeplug) parseRecords(iRdr io.Reader, setRegions func([]imageRegion)) ([]*Record, error) {
 	var err error
 	rdr := NewReader(iRdr)
 	records := []*Record{}
 
-	if len(nameToRt) == 0 {
-		nameToRt = make(map[string]RecordType)
-		for _, rType := range cp.RecordTypes() {
-			nameToRt[string(rType)] = rType
-		}
-
-		nameToFt = make(map[RecordType]map[string]FieldType)
-		for _, rType := range cp.RecordTypes() {
-			m := make(map[string]FieldType)
-			for _, fi := range cp.rDesc[rType].fInfos {
-				fType := fi.fType
-				name := string(fType)
-				m[name] = fType
-			}
-			nameToFt[rType] = m
-		}
-	}
-
 	for {
 		var name string
 		var index int
@@ -873,6 +1167,17 @@ func (cp *Codeplug) ParseRecords(iRdr io.Reader) ([]*Record, error) {
 			err = noRecordNameError
 			break
 		}
+		if name == imageBytesName {
+			var regions []imageRegion
+			regions, err = cp.parseImageRegions(rdr)
+			if err != nil {
+				break
+			}
+			if setRegions != nil {
+				setRegions(regions)
+			}
+			continue
+		}
 		r, err = cp.nameToRecord(name, index)
 		if err != nil {
 			break
@@ -889,7 +1194,18 @@ func (cp *Codeplug) ParseRecords(iRdr io.Reader) ([]*Record, error) {
 				err = noFieldNameError
 				break
 			}
-			fType, ok := nameToFt[r.rType][name]
+			if name == unknownBytesName {
+				var str string
+				str, err = parseValue(rdr)
+				if err == nil {
+					err = r.setUnknownString(str)
+				}
+				if err != nil {
+					break
+				}
+				continue
+			}
+			fType, ok := cp.nameToFt[r.rType][name]
 			if !ok {
 				err = fmt.Errorf("bad field name: %s", name)
 				return nil, err
@@ -929,6 +1245,36 @@ func (cp *Codeplug) ParseRecords(iRdr io.Reader) ([]*Record, error) {
 	return records, err
 }
 
+// parseImageRegions returns the regions of an ImageBytes section, read
+// from rdr.
+func (cp *Codeplug) parseImageRegions(rdr *reader) ([]imageRegion, error) {
+	var regions []imageRegion
+	for rdr.pos.column != 0 {
+		pos := rdr.pos
+		name, _, err := parseName(rdr)
+		if err != nil {
+			return regions, err
+		}
+		if name != imageRegionName {
+			err := fmt.Errorf("bad field name: %s", name)
+			return regions, positionError{err, pos}
+		}
+
+		pos = rdr.pos
+		str, err := parseValue(rdr)
+		if err != nil {
+			return regions, err
+		}
+		ir, err := cp.parseImageRegion(str)
+		if err != nil {
+			return regions, positionError{err, pos}
+		}
+		regions = append(regions, ir)
+	}
+
+	return regions, nil
+}
+
 func parseName(rdr *reader) (string, int, error) {
 	var err error
 	pos := rdr.pos
@@ -1014,7 +1360,7 @@ func parseValue(rdr *reader) (string, error) {
 }
 
 func (cp *Codeplug) nameToRecord(name string, index int) (*Record, error) {
-	rType, ok := nameToRt[name]
+	rType, ok := cp.nameToRt[name]
 	if !ok {
 		return nil, fmt.Errorf("unknown record type: %s", name)
 	}
@@ -1031,31 +1377,46 @@ func (cp *Codeplug) nameToRecord(name string, index int) (*Record, error) {
 		return nil, fmt.Errorf("codeplug has no record: %s", name)
 	}
 
-	return cp.newRecord(rType, index), nil
+	r := cp.newRecord(rType, index)
+	r.clearUnknown()
+
+	return r, nil
 }
 
+// ExportTo writes the codeplug's records, in text form, to the named file.
 func (cp *Codeplug) ExportTo(filename string) (err error) {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer func() {
-		err = file.Close()
-		return
+		cerr := file.Close()
+		if err == nil {
+			err = cerr
+		}
 	}()
 
-	w := bufio.NewWriter(file)
-	for i, rType := range cp.RecordTypes() {
-		for j, r := range cp.Records(rType) {
-			if i != 0 || j != 0 {
-				fmt.Fprintln(w)
-			}
-			PrintRecord(w, r)
+	return cp.Export(file)
+}
+
+// Export writes the codeplug's records, in text form, to w.  The
+// records are preceded by an ImageBytes section holding the image bytes
+// that none of them hold, so that Import restores the image exactly.
+func (cp *Codeplug) Export(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s:\n", imageBytesName)
+	for i, ir := range cp.imageRegions() {
+		fmt.Fprintf(bw, "\t%s[%d]: %s\n", imageRegionName, i+1, ir)
+	}
+
+	for _, rType := range cp.RecordTypes() {
+		for _, r := range cp.Records(rType) {
+			fmt.Fprintln(bw)
+			PrintRecord(bw, r)
 		}
 	}
-	w.Flush()
 
-	return nil
+	return bw.Flush()
 }
 
 func (cp *Codeplug) clearCachedListNames() {
@@ -1064,6 +1425,8 @@ func (cp *Codeplug) clearCachedListNames() {
 	}
 }
 
+// ImportFrom replaces the codeplug's records with those read, in text
+// form, from the named file.
 func (cp *Codeplug) ImportFrom(filename string) error {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -1071,9 +1434,41 @@ func (cp *Codeplug) ImportFrom(filename string) error {
 	}
 	defer file.Close()
 
-	cpBytes := make([]byte, fileSizeRdt)
-	copy(cpBytes, cp.bytes)
-	cp.store(cpBytes)
+	return cp.Import(file)
+}
+
+// Import replaces the codeplug's records with those read, in text
+// form, from rdr.  If an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) Import(rdr io.Reader) error {
+	parse := func(setRegions func([]imageRegion)) ([]*Record, error) {
+		return cp.parseRecords(rdr, setRegions)
+	}
+
+	deferredError := func(f *Field) error {
+		dValue := f.value.(deferredValue)
+		err := fmt.Errorf("no %s: %s", f.typeName, dValue.str)
+		return positionError{err, dValue.pos}
+	}
+
+	return cp.importRecords(parse, deferredError)
+}
+
+// importRecords replaces the codeplug's records with those returned by
+// parse.  References to other records are resolved once all of the
+// records have been inserted.  deferredError returns the error to be
+// returned for a field whose reference could not be resolved.  parse
+// sets the image regions it reads with setRegions before reading the
+// records, since the rdt header determines which frequencies are valid.
+// If an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) importRecords(parse func(setRegions func([]imageRegion)) ([]*Record, error), deferredError func(*Field) error) error {
+	cpBytes := cp.imageBytes()
+	byteEdits := cp.byteEdits
+	rollback := func() {
+		cp.byteEdits = byteEdits
+		cp.lowFrequency = 0
+		cp.highFrequency = 0
+		cp.load(cpBytes)
+	}
 
 	for _, rType := range cp.RecordTypes() {
 		records := cp.Records(rType)
@@ -1082,9 +1477,9 @@ func (cp *Codeplug) ImportFrom(filename string) error {
 		}
 	}
 
-	records, err := cp.ParseRecords(file)
+	records, err := parse(cp.setImageRegions)
 	if err != nil {
-		cp.load(cpBytes)
+		rollback()
 		return err
 	}
 
@@ -1095,15 +1490,13 @@ func (cp *Codeplug) ImportFrom(filename string) error {
 
 	err, f := updateDeferredFields(records)
 	if err != nil {
-		cp.load(cpBytes)
-		dValue := f.value.(deferredValue)
-		err = fmt.Errorf("no %s: %s", f.typeName, dValue.str)
-		return positionError{err, dValue.pos}
+		rollback()
+		return deferredError(f)
 	}
 
 	for _, rd := range cp.rDesc {
 		if len(rd.records) == 0 {
-			cp.load(cpBytes)
+			rollback()
 			rtName := string(rd.rType)
 			err := fmt.Errorf("no %s records found", rtName)
 			return err
@@ -0,0 +1,171 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// blankImage returns the rdt contents of a new blank codeplug of the
+// given type, covering its first frequency range.
+func blankImage(t *testing.T, cpType CodeplugType) []byte {
+	t.Helper()
+
+	cp, err := NewBlankCodeplug(cpType, FrequencyRanges(cpType)[0])
+	if err != nil {
+		t.Fatalf("%s: NewBlankCodeplug: %s", cpType, err)
+	}
+	defer cp.Free()
+
+	cpBytes, err := cp.Bytes()
+	if err != nil {
+		t.Fatalf("%s: Bytes: %s", cpType, err)
+	}
+
+	return cpBytes
+}
+
+// editCodeplug opens a codeplug from cpBytes, edits, checks and saves
+// it, and returns its export.
+func editCodeplug(cpType CodeplugType, cpBytes []byte, filename string) (string, error) {
+	cp, err := NewCodeplugFromBytes(cpBytes, cpType)
+	if err != nil {
+		return "", err
+	}
+	defer cp.Free()
+
+	records := cp.Records(RtChannelInformation)
+	name := "Concurrent"
+	err = cp.SetFields(records[:1], FtChannelName, name)
+	if err != nil {
+		return "", err
+	}
+	err = cp.SetFields(records, FtTot, "60")
+	if err != nil {
+		return "", err
+	}
+
+	for _, issue := range cp.Validate() {
+		if issue.Severity == SeverityError {
+			return "", fmt.Errorf("validate: %s", issue.String())
+		}
+	}
+	cp.Lint()
+
+	var export bytes.Buffer
+	if err := cp.Export(&export); err != nil {
+		return "", err
+	}
+
+	if err := cp.SaveAs(filename); err != nil {
+		return "", err
+	}
+
+	saved, err := NewCodeplug(filename, cpType)
+	if err != nil {
+		return "", err
+	}
+	defer saved.Free()
+
+	if got := saved.Records(RtChannelInformation)[0].Name(); got != name {
+		return "", fmt.Errorf("saved channel name is %q, not %q", got, name)
+	}
+
+	return export.String(), nil
+}
+
+// TestConcurrentCodeplugs opens, edits and saves codeplugs of all
+// types at the same time.  It is meant to be run with -race.
+func TestConcurrentCodeplugs(t *testing.T) {
+	const perType = 4
+
+	dir := t.TempDir()
+	images := make(map[CodeplugType][]byte)
+	for _, cpType := range CodeplugTypes() {
+		images[cpType] = blankImage(t, cpType)
+	}
+
+	var wg sync.WaitGroup
+	exports := make(map[CodeplugType][]string)
+	var mutex sync.Mutex
+	for _, cpType := range CodeplugTypes() {
+		for i := 0; i < perType; i++ {
+			cpType, i := cpType, i
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				filename := filepath.Join(dir, fmt.Sprintf("%s-%d.rdt", cpType, i))
+				export, err := editCodeplug(cpType, images[cpType], filename)
+				if err != nil {
+					t.Errorf("%s %d: %s", cpType, i, err)
+					return
+				}
+				mutex.Lock()
+				exports[cpType] = append(exports[cpType], export)
+				mutex.Unlock()
+			}()
+		}
+	}
+	wg.Wait()
+
+	// The same edits to the same image must give the same result,
+	// whatever else was going on at the time.
+	for cpType, strs := range exports {
+		for _, str := range strs[1:] {
+			if str != strs[0] {
+				t.Errorf("%s: concurrent edits gave different results", cpType)
+				break
+			}
+		}
+	}
+}
+
+func TestTypeNamesWithoutRecords(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	for _, r := range cp.Records(RtScanList) {
+		cp.RemoveRecord(r)
+	}
+	if n := len(cp.Records(RtScanList)); n != 0 {
+		t.Fatalf("%d scan lists", n)
+	}
+
+	if name := cp.RecordTypeName(RtScanList); name != "Scan List" {
+		t.Errorf("RecordTypeName is %q", name)
+	}
+	if name := cp.FieldTypeName(RtScanList, FtTxDesignatedChannel); name == "" {
+		t.Errorf("FieldTypeName of %s is empty", FtTxDesignatedChannel)
+	}
+	if name := cp.FieldTypeName(RtScanList, FtRxFrequency); name != "" {
+		t.Errorf("FieldTypeName of %s is %q", FtRxFrequency, name)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A ContactFilter selects the users imported from a user database.
+// A user is imported if it matches one of the values of each non-empty
+// list.  Countries and states are matched without regard to case.
+type ContactFilter struct {
+	Countries  []string
+	States     []string
+	IDPrefixes []string
+}
+
+// match returns true if the filter selects the given user.
+func (filter ContactFilter) match(id, state, country string) bool {
+	matchAny := func(values []string, match func(string) bool) bool {
+		if len(values) == 0 {
+			return true
+		}
+		for _, v := range values {
+			if match(strings.TrimSpace(v)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matchAny(filter.Countries, func(v string) bool {
+		return strings.EqualFold(v, country)
+	}) && matchAny(filter.States, func(v string) bool {
+		return strings.EqualFold(v, state)
+	}) && matchAny(filter.IDPrefixes, func(v string) bool {
+		return strings.HasPrefix(id, v)
+	})
+}
+
+// ImportContactsCsvFrom appends the users read from the named user
+// database CSV file to the codeplug.  See ImportContactsCsv.
+func (cp *Codeplug) ImportContactsCsvFrom(filename string, filter ContactFilter) ([]SkippedRow, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return cp.ImportContactsCsv(file, filter)
+}
+
+// ImportContactsCsv appends the users selected by filter from a user
+// database CSV file, such as that of RadioID.net, read from rdr, to the
+// codeplug's private call contacts, as a single change that may be
+// undone.  Rows not imported, other than those not selected by the
+// filter, are returned.  If an error is returned, the codeplug is
+// unchanged.
+func (cp *Codeplug) ImportContactsCsv(rdr io.Reader, filter ContactFilter) ([]SkippedRow, error) {
+	records, skipped, err := cp.ParseContactsCsv(rdr, filter)
+	if err != nil || len(records) == 0 {
+		return skipped, err
+	}
+
+	// ParseContactsCsv has made the names unique.
+	change := cp.InsertRecordsChange(records)
+	err = cp.appendRecords(records)
+	if err != nil {
+		return skipped, err
+	}
+	change.Complete()
+
+	return skipped, nil
+}
+
+// ParseContactsCsv reads users from a user database CSV file read from
+// rdr and returns those selected by filter as DigitalContacts records
+// that have not yet been inserted into the codeplug.  The file must
+// have a header naming its columns, which include the radio ID and
+// callsign, and may include the name (or first and last names), city,
+// state and country.  A contact is named by the user's callsign and
+// name, truncated to fit and made unique as by InsertRecord.  Rows
+// whose radio ID is already that of a
+// contact, and rows that would exceed the codeplug's maximum number of
+// contacts, are skipped and returned.
+func (cp *Codeplug) ParseContactsCsv(rdr io.Reader, filter ContactFilter) ([]*Record, []SkippedRow, error) {
+	rd := cp.rDesc[RtDigitalContacts]
+	template := make([]byte, rd.size)
+
+	csvRdr := csv.NewReader(rdr)
+	csvRdr.FieldsPerRecord = -1
+	csvRdr.TrimLeadingSpace = true
+
+	header, err := csvRdr.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = fmt.Errorf("no CSV header found")
+		}
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[contactColumnName(name)] = i
+	}
+	for _, name := range []string{"radioid", "callsign"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("no %s column found", name)
+		}
+	}
+
+	callIDs := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, r := range rd.records {
+		callIDs[r.Field(FtCallID).String()] = true
+		names[r.Name()] = true
+	}
+
+	var records []*Record
+	var skipped []SkippedRow
+	room := rd.max - len(rd.records)
+
+	for line := 2; ; line++ {
+		row, err := csvRdr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		c := chirpRow{columns, row}
+		id := c.value("radioid")
+		if id == "" {
+			continue
+		}
+		if !filter.match(id, c.value("state"), c.value("country")) {
+			continue
+		}
+
+		skip := func(reason string) {
+			skipped = append(skipped, SkippedRow{
+				Line:   line,
+				Reason: reason,
+			})
+		}
+
+		callID, err := strconv.ParseUint(id, 10, 24)
+		if err != nil {
+			skip(fmt.Sprintf("bad radio ID: %s", id))
+			continue
+		}
+		id = strconv.FormatUint(callID, 10)
+
+		callsign := c.value("callsign")
+		if callIDs[id] {
+			skip(fmt.Sprintf("duplicate Call ID: %s (%s)", id, callsign))
+			continue
+		}
+
+		if len(records) >= room {
+			reason := fmt.Sprintf("contact limit of %d reached: %s (%s)", rd.max, id, callsign)
+			skip(reason)
+			continue
+		}
+
+		name := c.value("name")
+		if name == "" {
+			name = strings.TrimSpace(c.value("firstname") + " " + c.value("lastname"))
+		}
+		name = strings.TrimSpace(callsign + " " + name)
+		if name == "" {
+			name = id
+		}
+
+		r := cp.bytesToRecord(RtDigitalContacts, 0, template)
+		maxLen := r.NameField().size()/2 - 1
+		name = truncateName(r.NameField(), name)
+		if names[name] {
+			name = uniqueName(name, names, maxLen)
+			if names[name] {
+				skip(fmt.Sprintf("too many contacts named %s", name))
+				continue
+			}
+		}
+
+		values := []struct {
+			fType FieldType
+			str   string
+		}{
+			{FtCallID, id},
+			{FtCallType, "Private"},
+			{FtCallReceiveTone, "No"},
+			{FtContactName, name},
+		}
+
+		for _, v := range values {
+			f := r.Field(v.fType)
+			err = f.SetString(v.str)
+			if err != nil {
+				err = fmt.Errorf("bad %s: %s: %s", f.typeName, v.str, err)
+				break
+			}
+		}
+		if err != nil {
+			skip(err.Error())
+			continue
+		}
+
+		callIDs[id] = true
+		names[name] = true
+		records = append(records, r)
+	}
+
+	return records, skipped, nil
+}
+
+// contactColumnName returns the given user database column name in
+// lower case, without spaces or punctuation, so that "RADIO_ID" and
+// "Radio ID" are both "radioid".
+func contactColumnName(name string) string {
+	var runes []rune
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, unicode.ToLower(r))
+		}
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,140 @@
+package codeplug
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// contactStrings returns the call ID and name of each of the codeplug's
+// contacts, following the first.
+func contactStrings(cp *Codeplug) []string {
+	var strs []string
+	for _, r := range cp.Records(RtDigitalContacts)[1:] {
+		strs = append(strs, r.Field(FtCallID).String()+" "+r.Name())
+	}
+
+	return strs
+}
+
+func TestImportContactsCsv(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		filter   ContactFilter
+		contacts []string
+		skipped  []SkippedRow
+	}{
+		{
+			name: "column names",
+			csv: "RADIO_ID,CALLSIGN,FIRST_NAME,LAST_NAME,CITY,STATE,COUNTRY\n" +
+				"3100001,N0AAA,Ann,Smith,Denver,Colorado,United States\n",
+			contacts: []string{"3100001 N0AAA Ann Smith"},
+		},
+		{
+			name: "column names with spaces",
+			csv: "Radio ID, Callsign, Name, City, State, Country\n" +
+				"3100001,N0AAA,Ann,Denver,Colorado,United States\n",
+			contacts: []string{"3100001 N0AAA Ann"},
+		},
+		{
+			name: "filter",
+			csv: "radio_id,callsign,first_name,state,country\n" +
+				"3100001,N0AAA,Ann,Colorado,United States\n" +
+				"3100002,N0BBB,Bob,Kansas,United States\n" +
+				"3020001,VE3CCC,Cat,Ontario,Canada\n" +
+				"3200001,N0DDD,Dan,Colorado,United States\n",
+			filter: ContactFilter{
+				Countries:  []string{"united states"},
+				States:     []string{" colorado", "Ontario"},
+				IDPrefixes: []string{"31"},
+			},
+			contacts: []string{"3100001 N0AAA Ann"},
+		},
+		{
+			name: "duplicate call IDs",
+			csv: "radio_id,callsign\n" +
+				"3100001,N0AAA\n" +
+				"03100001,N0BBB\n" +
+				"bogus,N0CCC\n",
+			contacts: []string{"3100001 N0AAA"},
+			skipped: []SkippedRow{
+				{Line: 3, Reason: "duplicate Call ID: 3100001 (N0BBB)"},
+				{Line: 4, Reason: "bad radio ID: bogus"},
+			},
+		},
+		{
+			name: "duplicate names",
+			csv: "radio_id,callsign,name\n" +
+				"3100001,N0AAA,A Very Long Name\n" +
+				"3100002,N0AAA,A Very Long Name\n",
+			contacts: []string{
+				"3100001 N0AAA A Very Lo",
+				"3100002 N0AAA A Very .a",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		skipped, err := cp.ImportContactsCsv(strings.NewReader(test.csv), test.filter)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+		if !reflect.DeepEqual(skipped, test.skipped) {
+			t.Errorf("%s: skipped %v, not %v", test.name, skipped, test.skipped)
+		}
+		if strs := contactStrings(cp); !reflect.DeepEqual(strs, test.contacts) {
+			t.Errorf("%s: contacts are %q, not %q", test.name, strs, test.contacts)
+		}
+
+		cp.UndoChange()
+		if strs := contactStrings(cp); len(strs) != 0 {
+			t.Errorf("%s: contacts after undo are %q", test.name, strs)
+		}
+
+		cp.Free()
+	}
+}
+
+func TestImportContactsCsvLimit(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	max := cp.MaxRecords(RtDigitalContacts)
+	csv := "radio_id,callsign\n"
+	for i := 0; i < max+1; i++ {
+		csv += fmt.Sprintf("%d,N%d\n", 3100001+i, i)
+	}
+
+	skipped, err := cp.ImportContactsCsv(strings.NewReader(csv), ContactFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(cp.Records(RtDigitalContacts)); n != max {
+		t.Errorf("%d contacts, not %d", n, max)
+	}
+	want := []SkippedRow{
+		{max + 1, "", fmt.Sprintf("contact limit of %d reached: %d (N%d)", max, 3100000+max, max-1)},
+		{max + 2, "", fmt.Sprintf("contact limit of %d reached: %d (N%d)", max, 3100001+max, max)},
+	}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped %v, not %v", skipped, want)
+	}
+
+	names := make(map[string]bool)
+	for _, r := range cp.Records(RtDigitalContacts) {
+		if names[r.Name()] {
+			t.Errorf("duplicate name %s", r.Name())
+		}
+		names[r.Name()] = true
+	}
+}
@@ -0,0 +1,591 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A DiffKind is a set of the ways in which a record or field differs
+// between two codeplugs.
+type DiffKind uint
+
+const (
+	DiffAdded DiffKind = 1 << iota
+	DiffRemoved
+	DiffRenamed
+	DiffMoved
+	DiffModified
+)
+
+var diffKindNames = []string{"added", "removed", "renamed", "moved", "modified"}
+
+// Has returns true if kind includes all of the kinds in k.
+func (kind DiffKind) Has(k DiffKind) bool {
+	return kind&k == k
+}
+
+// String returns the names of the kinds in the set, separated by commas.
+func (kind DiffKind) String() string {
+	strs := []string{}
+	for i, name := range diffKindNames {
+		if kind.Has(1 << uint(i)) {
+			strs = append(strs, name)
+		}
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+// A FieldDiff describes a difference in a field between two records.
+// For member list fields, such as ZoneInformation's ChannelMember,
+// each added, removed or moved member is a separate FieldDiff.
+// OldIndex is -1 for an added field and NewIndex is -1 for a removed
+// field.
+type FieldDiff struct {
+	FieldType FieldType
+	Kind      DiffKind
+	OldIndex  int
+	NewIndex  int
+	OldValue  string
+	NewValue  string
+}
+
+// A RecordDiff describes a difference in a record between two
+// codeplugs.  Records are matched by name.  OldIndex is -1 for an
+// added record and NewIndex is -1 for a removed record.
+type RecordDiff struct {
+	RecordType RecordType
+	Kind       DiffKind
+	OldIndex   int
+	NewIndex   int
+	OldName    string
+	NewName    string
+	Fields     []FieldDiff
+}
+
+// A CodeplugDiff contains the differences between two codeplugs.
+type CodeplugDiff struct {
+	Records []RecordDiff
+}
+
+// Diff returns the differences between codeplugs a and b.  Records are
+// matched by name, rather than by index, so a record that is renamed,
+// moved or modified is reported as such, rather than as removed and
+// added.  Records without names are matched by index.
+func Diff(a, b *Codeplug) *CodeplugDiff {
+	d := &CodeplugDiff{}
+
+	// Renames must be known before fields referring to renamed
+	// records are compared.
+	matches := make(map[RecordType][]recordMatch)
+	renames := make(map[RecordType]map[string]string)
+	for _, rType := range b.RecordTypes() {
+		if a.rDesc[rType] == nil {
+			continue
+		}
+		ms := matchRecords(a.Records(rType), b.Records(rType))
+		matches[rType] = ms
+		for _, m := range ms {
+			if m.a != nil && m.b != nil && m.a.Name() != m.b.Name() {
+				if renames[rType] == nil {
+					renames[rType] = make(map[string]string)
+				}
+				renames[rType][m.a.Name()] = m.b.Name()
+			}
+		}
+	}
+
+	for _, rType := range unionRecordTypes(a, b) {
+		ms, ok := matches[rType]
+		if !ok {
+			var aRecords, bRecords []*Record
+			if a.rDesc[rType] != nil {
+				aRecords = a.Records(rType)
+			}
+			if b.rDesc[rType] != nil {
+				bRecords = b.Records(rType)
+			}
+			ms = unmatchedRecords(aRecords, bRecords)
+		}
+
+		for _, m := range ms {
+			rd := m.diff(renames)
+			if rd.Kind != 0 {
+				d.Records = append(d.Records, rd)
+			}
+		}
+	}
+
+	return d
+}
+
+// unionRecordTypes returns the record types of either codeplug.
+func unionRecordTypes(a, b *Codeplug) []RecordType {
+	rTypes := b.RecordTypes()
+	for _, rType := range a.RecordTypes() {
+		if b.rDesc[rType] == nil {
+			rTypes = append(rTypes, rType)
+		}
+	}
+
+	return rTypes
+}
+
+// A recordMatch pairs a record of one codeplug with the corresponding
+// record of the other.  Either may be nil.
+type recordMatch struct {
+	a     *Record
+	b     *Record
+	moved bool
+}
+
+// unmatchedRecords returns recordMatches reporting all of aRecords as
+// removed and all of bRecords as added.
+func unmatchedRecords(aRecords, bRecords []*Record) []recordMatch {
+	ms := make([]recordMatch, 0, len(aRecords)+len(bRecords))
+	for _, r := range aRecords {
+		ms = append(ms, recordMatch{a: r})
+	}
+	for _, r := range bRecords {
+		ms = append(ms, recordMatch{b: r})
+	}
+
+	return ms
+}
+
+// matchRecords pairs the records of aRecords with those of bRecords.
+// Records are paired first by name, then unpaired records whose
+// contents are identical are paired as renamed, and finally unpaired
+// records at the same index are paired if most of their fields match.
+// The result is in order of bRecords, followed by removed records.
+func matchRecords(aRecords, bRecords []*Record) []recordMatch {
+	aMatch := make([]*Record, len(aRecords))
+	bMatch := make([]*Record, len(bRecords))
+
+	named := len(aRecords) > 0 && aRecords[0].NameField() != nil
+	if named {
+		aByName := make(map[string]int)
+		for i, r := range aRecords {
+			aByName[r.Name()] = i
+		}
+		for j, r := range bRecords {
+			if i, ok := aByName[r.Name()]; ok {
+				aMatch[i] = r
+				bMatch[j] = aRecords[i]
+			}
+		}
+
+		for j, br := range bRecords {
+			if bMatch[j] != nil {
+				continue
+			}
+			for i, ar := range aRecords {
+				if aMatch[i] == nil && sameContents(ar, br) {
+					aMatch[i] = br
+					bMatch[j] = ar
+					break
+				}
+			}
+		}
+	}
+
+	for j, br := range bRecords {
+		if bMatch[j] != nil || j >= len(aRecords) || aMatch[j] != nil {
+			continue
+		}
+		ar := aRecords[j]
+		if !named || similarContents(ar, br) {
+			aMatch[j] = br
+			bMatch[j] = ar
+		}
+	}
+
+	// Matched records not in the longest common subsequence of
+	// the two orderings have moved.
+	aOrder := []int{}
+	for _, ar := range bMatch {
+		if ar != nil {
+			aOrder = append(aOrder, ar.rIndex)
+		}
+	}
+	inOrder := longestIncreasing(aOrder)
+
+	ms := make([]recordMatch, 0, len(bRecords))
+	k := 0
+	for j, br := range bRecords {
+		m := recordMatch{a: bMatch[j], b: br}
+		if m.a != nil {
+			m.moved = !inOrder[k]
+			k++
+		}
+		ms = append(ms, m)
+	}
+	for i, ar := range aRecords {
+		if aMatch[i] == nil {
+			ms = append(ms, recordMatch{a: ar})
+		}
+	}
+
+	return ms
+}
+
+// valueStrings returns the string values of all of the record's fields,
+// other than its name, keyed by field type and index.
+func valueStrings(r *Record) map[string]string {
+	m := make(map[string]string)
+	for _, fType := range r.FieldTypes() {
+		if fType == r.nameFieldType {
+			continue
+		}
+		for _, f := range r.Fields(fType) {
+			m[fmt.Sprintf("%s[%d]", fType, f.fIndex)] = f.String()
+		}
+	}
+
+	return m
+}
+
+// sameContents returns true if all fields, other than the names, of the
+// two records are identical.
+func sameContents(a, b *Record) bool {
+	aStrs := valueStrings(a)
+	bStrs := valueStrings(b)
+	if len(aStrs) != len(bStrs) {
+		return false
+	}
+	for k, v := range aStrs {
+		if bStrs[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// similarContents returns true if most of the fields, other than the
+// names, of the two records are identical.
+func similarContents(a, b *Record) bool {
+	aStrs := valueStrings(a)
+	bStrs := valueStrings(b)
+	same := 0
+	for k, v := range aStrs {
+		if s, ok := bStrs[k]; ok && s == v {
+			same++
+		}
+	}
+	total := len(aStrs)
+	if len(bStrs) > total {
+		total = len(bStrs)
+	}
+
+	return same*2 > total
+}
+
+// longestIncreasing returns, for each element of seq, whether it is part
+// of a longest strictly increasing subsequence of seq.
+func longestIncreasing(seq []int) []bool {
+	n := len(seq)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i := range seq {
+		length[i] = 1
+		prev[i] = -1
+		for j := 0; j < i; j++ {
+			if seq[j] < seq[i] && length[j]+1 > length[i] {
+				length[i] = length[j] + 1
+				prev[i] = j
+			}
+		}
+		if best < 0 || length[i] > length[best] {
+			best = i
+		}
+	}
+
+	in := make([]bool, n)
+	for i := best; i >= 0; i = prev[i] {
+		in[i] = true
+	}
+
+	return in
+}
+
+// diff returns the differences between the matched records.
+func (m recordMatch) diff(renames map[RecordType]map[string]string) RecordDiff {
+	rd := RecordDiff{OldIndex: -1, NewIndex: -1}
+
+	if m.a != nil {
+		rd.RecordType = m.a.rType
+		rd.OldIndex = m.a.rIndex
+		rd.OldName = m.a.Name()
+	}
+	if m.b != nil {
+		rd.RecordType = m.b.rType
+		rd.NewIndex = m.b.rIndex
+		rd.NewName = m.b.Name()
+	}
+
+	switch {
+	case m.a == nil:
+		rd.Kind = DiffAdded
+		return rd
+
+	case m.b == nil:
+		rd.Kind = DiffRemoved
+		return rd
+	}
+
+	if rd.OldName != rd.NewName {
+		rd.Kind |= DiffRenamed
+	}
+	if m.moved {
+		rd.Kind |= DiffMoved
+	}
+
+	for _, fType := range unionFieldTypes(m.a, m.b) {
+		if fType == m.b.nameFieldType {
+			continue
+		}
+		var fds []FieldDiff
+		if isMemberList(m.a, m.b, fType) {
+			fds = diffMembers(m.a, m.b, fType, renames)
+		} else {
+			fds = diffFields(m.a, m.b, fType, renames)
+		}
+		rd.Fields = append(rd.Fields, fds...)
+	}
+	if len(rd.Fields) > 0 {
+		rd.Kind |= DiffModified
+	}
+
+	return rd
+}
+
+// unionFieldTypes returns the field types of either record.
+func unionFieldTypes(a, b *Record) []FieldType {
+	fTypes := b.FieldTypes()
+	for _, fType := range a.FieldTypes() {
+		if (*b.fDesc)[fType] == nil {
+			fTypes = append(fTypes, fType)
+		}
+	}
+
+	return fTypes
+}
+
+// fieldsOfType returns the record's fields of the given type, if any.
+func fieldsOfType(r *Record, fType FieldType) []*Field {
+	fd := (*r.fDesc)[fType]
+	if fd == nil {
+		return nil
+	}
+
+	return fd.fields
+}
+
+// isMemberList returns true if fields of fType are a list of references
+// to other records.
+func isMemberList(a, b *Record, fType FieldType) bool {
+	fd := (*b.fDesc)[fType]
+	if fd == nil {
+		fd = (*a.fDesc)[fType]
+	}
+
+	return fd.max > 1 && fd.listRecordType != ""
+}
+
+// mappedValue returns the value of f, as it would be named in the other
+// codeplug, taking renamed records into account.
+func mappedValue(f *Field, renames map[RecordType]map[string]string) string {
+	s := f.String()
+	if f.listRecordType == "" {
+		return s
+	}
+	if newName, ok := renames[f.listRecordType][s]; ok {
+		return newName
+	}
+
+	return s
+}
+
+// diffFields returns the differences between the fields of the given
+// type of two records, compared by index.
+func diffFields(a, b *Record, fType FieldType, renames map[RecordType]map[string]string) []FieldDiff {
+	var fds []FieldDiff
+
+	aFields := fieldsOfType(a, fType)
+	bFields := fieldsOfType(b, fType)
+	for i := 0; i < len(aFields) || i < len(bFields); i++ {
+		fd := FieldDiff{FieldType: fType, OldIndex: -1, NewIndex: -1}
+		switch {
+		case i >= len(aFields):
+			fd.Kind = DiffAdded
+			fd.NewIndex = i
+			fd.NewValue = bFields[i].String()
+
+		case i >= len(bFields):
+			fd.Kind = DiffRemoved
+			fd.OldIndex = i
+			fd.OldValue = aFields[i].String()
+
+		default:
+			if mappedValue(aFields[i], renames) == bFields[i].String() {
+				continue
+			}
+			fd.Kind = DiffModified
+			fd.OldIndex = i
+			fd.NewIndex = i
+			fd.OldValue = aFields[i].String()
+			fd.NewValue = bFields[i].String()
+		}
+		fds = append(fds, fd)
+	}
+
+	return fds
+}
+
+// diffMembers returns the differences between the member lists of the
+// given type of two records.  Members are matched by name, so that a
+// member moved within the list is reported as moved.
+func diffMembers(a, b *Record, fType FieldType, renames map[RecordType]map[string]string) []FieldDiff {
+	var fds []FieldDiff
+
+	aFields := fieldsOfType(a, fType)
+	bFields := fieldsOfType(b, fType)
+
+	bIndex := make(map[string]int)
+	for j, f := range bFields {
+		bIndex[f.String()] = j
+	}
+
+	aIndex := make(map[string]int)
+	for i, f := range aFields {
+		name := mappedValue(f, renames)
+		aIndex[name] = i
+		if _, ok := bIndex[name]; !ok {
+			fds = append(fds, FieldDiff{
+				FieldType: fType,
+				Kind:      DiffRemoved,
+				OldIndex:  i,
+				NewIndex:  -1,
+				OldValue:  f.String(),
+			})
+		}
+	}
+
+	aOrder := []int{}
+	for _, f := range bFields {
+		if i, ok := aIndex[f.String()]; ok {
+			aOrder = append(aOrder, i)
+		}
+	}
+	inOrder := longestIncreasing(aOrder)
+
+	k := 0
+	for j, f := range bFields {
+		name := f.String()
+		i, ok := aIndex[name]
+		if !ok {
+			fds = append(fds, FieldDiff{
+				FieldType: fType,
+				Kind:      DiffAdded,
+				OldIndex:  -1,
+				NewIndex:  j,
+				NewValue:  name,
+			})
+			continue
+		}
+		if !inOrder[k] {
+			fds = append(fds, FieldDiff{
+				FieldType: fType,
+				Kind:      DiffMoved,
+				OldIndex:  i,
+				NewIndex:  j,
+				OldValue:  aFields[i].String(),
+				NewValue:  name,
+			})
+		}
+		k++
+	}
+
+	return fds
+}
+
+// recordLabel returns a name by which to identify a record in a diff.
+func recordLabel(name string, index int) string {
+	if name != "" {
+		return quoteString(name)
+	}
+
+	return fmt.Sprintf("[%d]", index+1)
+}
+
+// WriteText writes a human-readable description of the differences.
+func (d *CodeplugDiff) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, rd := range d.Records {
+		label := recordLabel(rd.NewName, rd.NewIndex)
+		if rd.Kind.Has(DiffRemoved) {
+			label = recordLabel(rd.OldName, rd.OldIndex)
+		}
+		if rd.Kind.Has(DiffRenamed) {
+			label = recordLabel(rd.OldName, rd.OldIndex) + " -> " + label
+		}
+		fmt.Fprintf(bw, "%s %s: %s", rd.RecordType, label, rd.Kind)
+		if rd.Kind.Has(DiffMoved) {
+			fmt.Fprintf(bw, " (%d -> %d)", rd.OldIndex+1, rd.NewIndex+1)
+		}
+		fmt.Fprintln(bw)
+
+		for _, fd := range rd.Fields {
+			fmt.Fprintf(bw, "\t%s: ", fd.FieldType)
+			switch fd.Kind {
+			case DiffAdded:
+				fmt.Fprintf(bw, "+ %s at %d\n", quoteString(fd.NewValue), fd.NewIndex+1)
+			case DiffRemoved:
+				fmt.Fprintf(bw, "- %s from %d\n", quoteString(fd.OldValue), fd.OldIndex+1)
+			case DiffMoved:
+				fmt.Fprintf(bw, "~ %s moved %d -> %d\n", quoteString(fd.NewValue), fd.OldIndex+1, fd.NewIndex+1)
+			default:
+				fmt.Fprintf(bw, "%s -> %s\n", quoteString(fd.OldValue), quoteString(fd.NewValue))
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// String returns the text form of the differences.
+func (d *CodeplugDiff) String() string {
+	var buf bytes.Buffer
+	d.WriteText(&buf)
+
+	return buf.String()
+}
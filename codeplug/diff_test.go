@@ -0,0 +1,205 @@
+package codeplug
+
+import (
+	"reflect"
+	"testing"
+)
+
+// diffCodeplug returns a blank md380 codeplug with the channels
+// "Channel 1", "Two", "Three" and "Four", and a zone holding the first
+// three.
+func diffCodeplug(t *testing.T) *Codeplug {
+	t.Helper()
+
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := cp.Records(RtChannelInformation)[0].Name(); name != "Channel 1" {
+		t.Fatalf("blank codeplug's channel is %q", name)
+	}
+	addRecords(t, cp, RtChannelInformation, "Two", "Three", "Four")
+	zone := cp.Records(RtZoneInformation)[0]
+	setListFields(zone, FtChannelMember, []string{"Channel 1", "Two", "Three"})
+
+	return cp
+}
+
+// setField sets the named record's field of the given type to str.
+func setField(t *testing.T, cp *Codeplug, rType RecordType, name string, fType FieldType, str string) {
+	t.Helper()
+
+	r := cp.FindRecordByName(rType, name)
+	if r == nil {
+		t.Fatalf("no %s named %s", rType, name)
+	}
+	if err := r.Field(fType).SetString(str); err != nil {
+		t.Fatalf("%s %s: %s", name, fType, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(t *testing.T, cp *Codeplug)
+		want []RecordDiff
+	}{
+		{
+			"unchanged",
+			func(t *testing.T, cp *Codeplug) {},
+			nil,
+		},
+		{
+			"renamed",
+			func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtChannelName, "Deux")
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffRenamed,
+				OldIndex:   1,
+				NewIndex:   1,
+				OldName:    "Two",
+				NewName:    "Deux",
+			}},
+		},
+		{
+			"renamed and modified",
+			func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Three", FtColorCode, "5")
+				setField(t, cp, RtChannelInformation, "Three", FtChannelName, "Trois")
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffRenamed | DiffModified,
+				OldIndex:   2,
+				NewIndex:   2,
+				OldName:    "Three",
+				NewName:    "Trois",
+				Fields: []FieldDiff{{
+					FieldType: FtColorCode,
+					Kind:      DiffModified,
+					OldValue:  "0",
+					NewValue:  "5",
+				}},
+			}},
+		},
+		{
+			"moved",
+			func(t *testing.T, cp *Codeplug) {
+				r := cp.FindRecordByName(RtChannelInformation, "Four")
+				change := cp.MoveRecordsChange([]*Record{r})
+				cp.MoveRecord(0, r)
+				change.Complete()
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffMoved,
+				OldIndex:   3,
+				NewIndex:   0,
+				OldName:    "Four",
+				NewName:    "Four",
+			}},
+		},
+		{
+			"added",
+			func(t *testing.T, cp *Codeplug) {
+				addRecords(t, cp, RtChannelInformation, "Five")
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffAdded,
+				OldIndex:   -1,
+				NewIndex:   4,
+				NewName:    "Five",
+			}},
+		},
+		{
+			"removed",
+			func(t *testing.T, cp *Codeplug) {
+				cp.RemoveRecord(cp.FindRecordByName(RtChannelInformation, "Four"))
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffRemoved,
+				OldIndex:   3,
+				NewIndex:   -1,
+				OldName:    "Four",
+			}},
+		},
+		{
+			"member list",
+			func(t *testing.T, cp *Codeplug) {
+				zone := cp.Records(RtZoneInformation)[0]
+				setListFields(zone, FtChannelMember,
+					[]string{"Three", "Channel 1", "Four"})
+			},
+			[]RecordDiff{{
+				RecordType: RtZoneInformation,
+				Kind:       DiffModified,
+				Fields: []FieldDiff{
+					{
+						FieldType: FtChannelMember,
+						Kind:      DiffRemoved,
+						OldIndex:  1,
+						NewIndex:  -1,
+						OldValue:  "Two",
+					},
+					{
+						FieldType: FtChannelMember,
+						Kind:      DiffMoved,
+						OldIndex:  0,
+						NewIndex:  1,
+						OldValue:  "Channel 1",
+						NewValue:  "Channel 1",
+					},
+					{
+						FieldType: FtChannelMember,
+						Kind:      DiffAdded,
+						OldIndex:  -1,
+						NewIndex:  2,
+						NewValue:  "Four",
+					},
+				},
+			}},
+		},
+		{
+			"renamed member",
+			func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtChannelName, "Deux")
+				zone := cp.Records(RtZoneInformation)[0]
+				setListFields(zone, FtChannelMember,
+					[]string{"Channel 1", "Deux", "Three"})
+			},
+			[]RecordDiff{{
+				RecordType: RtChannelInformation,
+				Kind:       DiffRenamed,
+				OldIndex:   1,
+				NewIndex:   1,
+				OldName:    "Two",
+				NewName:    "Deux",
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		a := diffCodeplug(t)
+		b := copyCodeplug(t, a)
+		test.edit(t, b)
+
+		d := Diff(a, b)
+		for i := range test.want {
+			rd := &test.want[i]
+			if rd.RecordType == RtZoneInformation {
+				rd.OldName = a.Records(RtZoneInformation)[0].Name()
+				rd.NewName = rd.OldName
+			}
+		}
+		if !reflect.DeepEqual(d.Records, test.want) {
+			t.Errorf("%s: diff is\n%+v\nnot\n%+v", test.name, d.Records, test.want)
+		}
+
+		a.Free()
+		b.Free()
+	}
+}
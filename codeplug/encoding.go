@@ -0,0 +1,372 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// An encodedCodeplug is the form in which a codeplug's records are
+// encoded in JSON and YAML.  Records maps each record type's name to
+// its record, or, if the codeplug may have more than one record of the
+// type, to a list of its records.  Each record maps its field types'
+// names to the field's value, or, if the record may have more than one
+// field of the type, to a list of the fields' values.  Values are
+// strings, in the form shown by Export.  References to other records
+// are by name.  A record's unknown bytes, unless all zero, are given in
+// hexadecimal by its UnknownBytes key.  ImageBytes holds the image
+// bytes that none of the records hold, in the form of the text form's
+// ImageBytes section.
+type encodedCodeplug struct {
+	Model      string                 `json:"model" yaml:"model"`
+	Records    map[string]interface{} `json:"records" yaml:"records"`
+	ImageBytes []string               `json:"imageBytes,omitempty" yaml:"imageBytes,omitempty"`
+}
+
+// ExportJson writes the codeplug's records, in JSON form, to w.
+func (cp *Codeplug) ExportJson(w io.Writer) error {
+	bytes, err := json.MarshalIndent(cp.encode(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+// ImportJson replaces the codeplug's records with those read, in JSON
+// form, from rdr.  If an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) ImportJson(rdr io.Reader) error {
+	var ec encodedCodeplug
+	decoder := json.NewDecoder(rdr)
+	decoder.UseNumber()
+	err := decoder.Decode(&ec)
+	if err != nil {
+		return err
+	}
+
+	return cp.importEncoded(ec)
+}
+
+// ExportYaml writes the codeplug's records, in YAML form, to w.
+func (cp *Codeplug) ExportYaml(w io.Writer) error {
+	bytes, err := yaml.Marshal(cp.encode())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bytes)
+	return err
+}
+
+// ImportYaml replaces the codeplug's records with those read, in YAML
+// form, from rdr.  If an error is returned, the codeplug is unchanged.
+func (cp *Codeplug) ImportYaml(rdr io.Reader) error {
+	bytes, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return err
+	}
+
+	var ec encodedCodeplug
+	err = yaml.Unmarshal(bytes, &ec)
+	if err != nil {
+		return err
+	}
+
+	return cp.importEncoded(ec)
+}
+
+// encode returns the codeplug's records in encodable form.
+func (cp *Codeplug) encode() encodedCodeplug {
+	ec := encodedCodeplug{
+		Model:   string(cp.codeplugType),
+		Records: make(map[string]interface{}),
+	}
+
+	for _, rType := range cp.RecordTypes() {
+		records := cp.Records(rType)
+		encodedRecords := make([]interface{}, len(records))
+		for i, r := range records {
+			encodedRecords[i] = r.encode()
+		}
+
+		if cp.MaxRecords(rType) == 1 {
+			if len(encodedRecords) > 0 {
+				ec.Records[string(rType)] = encodedRecords[0]
+			}
+			continue
+		}
+		ec.Records[string(rType)] = encodedRecords
+	}
+
+	for _, ir := range cp.imageRegions() {
+		ec.ImageBytes = append(ec.ImageBytes, ir.String())
+	}
+
+	return ec
+}
+
+// encode returns the record's fields in encodable form.
+func (r *Record) encode() map[string]interface{} {
+	er := make(map[string]interface{})
+
+	for _, fType := range r.FieldTypes() {
+		fields := r.Fields(fType)
+		if r.MaxFields(fType) == 1 {
+			if len(fields) > 0 {
+				er[string(fType)] = fields[0].String()
+			}
+			continue
+		}
+
+		strs := make([]string, len(fields))
+		for i, f := range fields {
+			strs[i] = f.String()
+		}
+		er[string(fType)] = strs
+	}
+
+	if r.hasUnknownBits() {
+		er[unknownBytesName] = r.unknownString()
+	}
+
+	return er
+}
+
+// importEncoded replaces the codeplug's records with those of ec.
+func (cp *Codeplug) importEncoded(ec encodedCodeplug) error {
+	if ec.Model != "" && CodeplugType(ec.Model) != cp.codeplugType {
+		return fmt.Errorf("not a %s codeplug: %s", cp.codeplugType, ec.Model)
+	}
+
+	parse := func(setRegions func([]imageRegion)) ([]*Record, error) {
+		var regions []imageRegion
+		for _, str := range ec.ImageBytes {
+			ir, err := cp.parseImageRegion(str)
+			if err != nil {
+				return nil, err
+			}
+			regions = append(regions, ir)
+		}
+		setRegions(regions)
+
+		return cp.decodeRecords(ec.Records)
+	}
+
+	deferredError := func(f *Field) error {
+		dValue := f.value.(deferredValue)
+		return fmt.Errorf("%s: no %s: %s", f.location(), f.typeName, dValue.str)
+	}
+
+	return cp.importRecords(parse, deferredError)
+}
+
+// decodeRecords returns the records of an encodedCodeplug's Records.
+func (cp *Codeplug) decodeRecords(encodedRecords map[string]interface{}) ([]*Record, error) {
+	var names []string
+	for name := range encodedRecords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var records []*Record
+	for _, name := range names {
+		rType, ok := cp.nameToRt[name]
+		if !ok || cp.rDesc[rType] == nil {
+			return nil, fmt.Errorf("unknown record type: %s", name)
+		}
+
+		value := encodedRecords[name]
+		list := []interface{}{value}
+		if cp.MaxRecords(rType) > 1 {
+			list, ok = value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: not a list of records", name)
+			}
+		}
+
+		for i, value := range list {
+			r, err := cp.nameToRecord(name, i)
+			if err != nil {
+				return nil, err
+			}
+
+			location := name
+			if r.max > 1 {
+				location += fmt.Sprintf("[%d]", i+1)
+			}
+			er, ok := decodedMap(value)
+			if !ok {
+				return nil, fmt.Errorf("%s: not a record", location)
+			}
+
+			err = r.decode(er, location)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
+
+// decode adds the fields of an encoded record to the record.  Each
+// field's value is set by its value type's SetString.
+func (r *Record) decode(er map[string]interface{}, location string) error {
+	var names []string
+	for name := range er {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == unknownBytesName {
+			str, ok := er[name].(string)
+			if !ok {
+				return fmt.Errorf("%s.%s: not a string", location, name)
+			}
+			err := r.setUnknownString(str)
+			if err != nil {
+				return fmt.Errorf("%s: %s", location, err)
+			}
+			continue
+		}
+
+		fType, ok := r.codeplug.nameToFt[r.rType][name]
+		if !ok {
+			return fmt.Errorf("%s: bad field name: %s", location, name)
+		}
+
+		max := r.fieldMax(fType)
+		value := er[name]
+		list := []interface{}{value}
+		if max > 1 {
+			// An empty list may be decoded as nil.
+			list, ok = value.([]interface{})
+			if !ok && value != nil {
+				return fmt.Errorf("%s.%s: not a list", location, name)
+			}
+		}
+
+		for i, value := range list {
+			fLocation := location + "." + name
+			if max > 1 {
+				fLocation += fmt.Sprintf("[%d]", i+1)
+			}
+
+			f, err := r.decodeField(fType, i, value)
+			if err != nil {
+				return fmt.Errorf("%s: %s", fLocation, err)
+			}
+
+			err = r.addField(f)
+			if err != nil {
+				return fmt.Errorf("%s: %s", fLocation, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fieldMax returns the maximum number of fields of the given type for
+// the record, which may not yet have any fields.
+func (r *Record) fieldMax(fType FieldType) int {
+	for _, fi := range r.fInfos {
+		if fi.fType == fType {
+			return fi.max
+		}
+	}
+
+	return 0
+}
+
+// decodeField returns a new field of the record having the given
+// encoded value.  Numbers and booleans, which templating tools may
+// produce in place of strings, are accepted.
+func (r *Record) decodeField(fType FieldType, index int, value interface{}) (*Field, error) {
+	var strs []string
+	switch v := value.(type) {
+	case string:
+		strs = []string{v}
+	case json.Number:
+		strs = []string{v.String()}
+	case int:
+		strs = []string{strconv.Itoa(v)}
+	case float64:
+		strs = []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		strs = []string{"Off", "No", "False"}
+		if v {
+			strs = []string{"On", "Yes", "True"}
+		}
+	default:
+		return nil, fmt.Errorf("not a value: %v", value)
+	}
+
+	var f *Field
+	var err error
+	for _, str := range strs {
+		f, err = r.NewFieldWithValue(fType, index, str)
+		if err == nil {
+			if dValue, ok := f.value.(deferredValue); ok {
+				dValue.str = str
+				f.value = dValue
+			}
+			return f, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bad value: %s: %s", strs[0], err)
+}
+
+// decodedMap returns the given decoded JSON or YAML mapping as a map
+// with string keys.
+func decodedMap(value interface{}) (map[string]interface{}, bool) {
+	switch m := value.(type) {
+	case map[string]interface{}:
+		return m, true
+
+	case map[interface{}]interface{}:
+		sm := make(map[string]interface{})
+		for k, v := range m {
+			str, ok := k.(string)
+			if !ok {
+				return nil, false
+			}
+			sm[str] = v
+		}
+		return sm, true
+	}
+
+	return nil, false
+}
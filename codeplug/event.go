@@ -0,0 +1,626 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A ChangeEvent is the wire form of a change, suitable for encoding
+// with encoding/json.  It refers to records by name and gives values
+// as strings, in the form shown by Export, so it may be sent to, and
+// replayed against, another instance of the codeplug.  An event
+// describes the change as it was made: the event of an undone change
+// is that of the opposite change.
+//
+// The meaning of the event's fields depends on its type:
+//
+//	FieldChange          Records[0]'s FieldType field at Indexes[0] is
+//	                     set from Before[0] to After[0]
+//	BatchFieldChange     each record's FieldType field, or if FieldTypes
+//	                     is given, its field of the corresponding type,
+//	                     is set from its Before value to its After value
+//	MoveRecordsChange    the records are moved to Indexes
+//	InsertRecordsChange  records having Contents are inserted at Indexes
+//	RemoveRecordsChange  the records are removed
+//	MoveFieldsChange     Records[0]'s FieldType fields having the Before
+//	                     values are moved to Indexes
+//	InsertFieldsChange   FieldType fields having the After values are
+//	                     inserted into Records[0] at Indexes
+//	RemoveFieldsChange   Records[0]'s FieldType fields having the Before
+//	                     values are removed
+//	BytesChange          the rdt bytes at offset Indexes[0] are set from
+//	                     Before[0] to After[0], given in hex
+//
+// References changed as a consequence, such as the members of a zone
+// when a channel is removed, are given by ListIndexChange events in
+// Changes: Records[0]'s FieldType fields are set to the After values.
+type ChangeEvent struct {
+	Type       ChangeType            `json:"type"`
+	RecordType RecordType            `json:"recordType"`
+	Records    []EventRecord         `json:"records"`
+	FieldType  FieldType             `json:"fieldType,omitempty"`
+	FieldTypes []FieldType           `json:"fieldTypes,omitempty"`
+	Indexes    []int                 `json:"indexes,omitempty"`
+	Before     []string              `json:"before,omitempty"`
+	After      []string              `json:"after,omitempty"`
+	Contents   []map[string][]string `json:"contents,omitempty"`
+	Changes    []*ChangeEvent        `json:"changes,omitempty"`
+}
+
+// An EventRecord refers to a record in a ChangeEvent.  A record is
+// found by its name or, if its type has no name field, by its index.
+type EventRecord struct {
+	Name  string `json:"name,omitempty"`
+	Index int    `json:"index"`
+}
+
+// Event returns the wire form of a published change.
+func (change *Change) Event() *ChangeEvent {
+	ev := &ChangeEvent{
+		Type:       change.cType,
+		RecordType: change.RecordType(),
+	}
+
+	// A redone insertion or removal is published as the opposite
+	// change, so which was made is determined from the codeplug.
+	switch change.cType {
+	case InsertRecordsChange, RemoveRecordsChange:
+		ev.Type = RemoveRecordsChange
+		if change.records[0].inCodeplug() {
+			ev.Type = InsertRecordsChange
+		}
+	case InsertFieldsChange, RemoveFieldsChange:
+		ev.Type = RemoveFieldsChange
+		if change.fields[0].inRecord() {
+			ev.Type = InsertFieldsChange
+		}
+	}
+
+	switch ev.Type {
+	case FieldChange:
+		f := change.Field()
+		ev.Records = []EventRecord{eventRecord(f.record, f, change.previousValue())}
+		ev.FieldType = f.fType
+		ev.Indexes = []int{f.fIndex}
+		ev.Before = []string{change.previousValue()}
+		ev.After = []string{f.String()}
+
+	case BatchFieldChange:
+		ev.FieldType = change.fields[0].fType
+		if change.mixedFieldTypes() {
+			ev.FieldType = ""
+			for _, f := range change.fields {
+				ev.FieldTypes = append(ev.FieldTypes, f.fType)
+			}
+		}
+		befores := make([]string, len(change.fields))
+		nameFields := make(map[*Record]int)
+		for i, f := range change.fields {
+			// The change holds the values set by redo, and
+			// the fields hold the values now in effect.
+			befores[i] = change.strings[i]
+			if f.String() == befores[i] {
+				befores[i] = change.batchValue(i)
+			}
+			if f.fType == f.record.nameFieldType {
+				nameFields[f.record] = i
+			}
+		}
+		for i, f := range change.fields {
+			// A record whose name is changed is referred to by
+			// its previous name, even for its other fields.
+			nf, before := f, befores[i]
+			if j, ok := nameFields[f.record]; ok {
+				nf, before = change.fields[j], befores[j]
+			}
+			ev.Records = append(ev.Records, eventRecord(f.record, nf, before))
+			ev.Before = append(ev.Before, befores[i])
+			ev.After = append(ev.After, f.String())
+		}
+
+	case MoveRecordsChange, InsertRecordsChange, RemoveRecordsChange:
+		for _, r := range change.records {
+			ev.Records = append(ev.Records, eventRecord(r, nil, ""))
+			if ev.Type == RemoveRecordsChange {
+				continue
+			}
+			ev.Indexes = append(ev.Indexes, r.rIndex)
+			if ev.Type == InsertRecordsChange {
+				ev.Contents = append(ev.Contents, eventContents(r))
+			}
+		}
+
+	case MoveFieldsChange, InsertFieldsChange, RemoveFieldsChange:
+		ev.Records = []EventRecord{eventRecord(change.Record(), nil, "")}
+		ev.FieldType = change.FieldType()
+		for _, f := range change.fields {
+			switch ev.Type {
+			case MoveFieldsChange:
+				ev.Before = append(ev.Before, f.String())
+				ev.Indexes = append(ev.Indexes, f.fIndex)
+			case InsertFieldsChange:
+				ev.After = append(ev.After, f.String())
+				ev.Indexes = append(ev.Indexes, f.fIndex)
+			case RemoveFieldsChange:
+				ev.Before = append(ev.Before, f.String())
+			}
+		}
+
+	case BytesChange:
+		ev.Indexes = []int{change.offset}
+		ev.Before = []string{change.strings[0]}
+		ev.After = []string{change.afterStrings[0]}
+
+	case ListIndexChange:
+		r := change.Record()
+		ev.Records = []EventRecord{eventRecord(r, nil, "")}
+		ev.FieldType = change.FieldType()
+		for _, f := range r.Fields(ev.FieldType) {
+			ev.After = append(ev.After, f.String())
+		}
+	}
+
+	for _, subChange := range change.changes {
+		if subChange.cType == ListIndexChange {
+			ev.Changes = append(ev.Changes, subChange.Event())
+		}
+	}
+
+	return ev
+}
+
+// eventRecord returns the reference to a record in a ChangeEvent.  If f,
+// the changed field, is the record's name field, the record is referred
+// to by the name it had before the change.
+func eventRecord(r *Record, f *Field, before string) EventRecord {
+	er := EventRecord{
+		Name:  r.Name(),
+		Index: r.rIndex,
+	}
+	if f != nil && f.fType == r.nameFieldType {
+		er.Name = before
+	}
+
+	return er
+}
+
+// eventContents returns the values of the record's fields, by field
+// type, and its unknown bytes, if any.
+func eventContents(r *Record) map[string][]string {
+	contents := make(map[string][]string)
+	for _, fType := range r.FieldTypes() {
+		var strs []string
+		for _, f := range r.Fields(fType) {
+			strs = append(strs, f.String())
+		}
+		contents[string(fType)] = strs
+	}
+	if r.hasUnknownBits() {
+		contents[unknownBytesName] = []string{r.unknownString()}
+	}
+
+	return contents
+}
+
+// ReplayChange makes the change described by ev, as a single change
+// that may be undone, and publishes it.  The published change's Remote
+// method returns true.  An error is returned, and the codeplug is
+// unchanged, if the records or fields referred to by ev don't exist,
+// or if a field's value is neither that before nor that after the
+// change.  Replaying a change that has already been made has no
+// effect.
+func (cp *Codeplug) ReplayChange(ev *ChangeEvent) error {
+	if ev.Type == BytesChange {
+		return cp.replayBytesChange(ev)
+	}
+
+	rd := cp.rDesc[ev.RecordType]
+	if rd == nil {
+		return fmt.Errorf("unknown record type: %s", ev.RecordType)
+	}
+
+	var records []*Record
+	if ev.Type != InsertRecordsChange {
+		for _, er := range ev.Records {
+			r := cp.eventRecord(ev.RecordType, er)
+			if r == nil && ev.Type != RemoveRecordsChange {
+				return fmt.Errorf("%s: no record %s", rd.typeName, er.Name)
+			}
+			if r != nil {
+				records = append(records, r)
+			}
+		}
+	}
+
+	switch ev.Type {
+	case FieldChange, BatchFieldChange, MoveFieldsChange,
+		InsertFieldsChange, RemoveFieldsChange:
+		if len(records) == 0 {
+			return fmt.Errorf("%s has no records", ev.Type)
+		}
+		if len(ev.FieldTypes) > 0 && ev.Type == BatchFieldChange {
+			break
+		}
+		if records[0].fieldMax(ev.FieldType) == 0 {
+			return fmt.Errorf("%s has no %s field", ev.RecordType, ev.FieldType)
+		}
+	}
+
+	var change *Change
+	var err error
+	switch ev.Type {
+	case FieldChange:
+		change, err = replayFieldChange(records[0], ev)
+
+	case BatchFieldChange:
+		change, err = cp.replayBatchFieldChange(records, ev)
+
+	case MoveRecordsChange:
+		change, err = cp.replayMoveRecords(records, ev)
+
+	case InsertRecordsChange:
+		change, err = cp.replayInsertRecords(ev)
+
+	case RemoveRecordsChange:
+		if len(records) == 0 {
+			return nil
+		}
+		change = cp.RemoveRecordsChange(records)
+		for _, r := range records {
+			cp.RemoveRecord(r)
+		}
+
+	case MoveFieldsChange, InsertFieldsChange, RemoveFieldsChange:
+		change, err = replayFieldsChange(records[0], ev)
+
+	default:
+		return fmt.Errorf("can't replay %s", ev.Type)
+	}
+
+	if err != nil || change == nil {
+		return err
+	}
+
+	change.remote = true
+	change.add()
+	cp.replayReferences(change, ev.Changes)
+	cp.publishChange(change)
+
+	return nil
+}
+
+// replayReferences sets the references given by the ListIndexChange
+// events of a replayed change, as part of the change.  References to
+// records that don't exist are skipped.
+func (cp *Codeplug) replayReferences(change *Change, events []*ChangeEvent) {
+	for _, ev := range events {
+		if ev.Type != ListIndexChange || len(ev.Records) == 0 ||
+			cp.rDesc[ev.RecordType] == nil {
+			continue
+		}
+		r := cp.eventRecord(ev.RecordType, ev.Records[0])
+		if r == nil || (*r.fDesc)[ev.FieldType] == nil {
+			continue
+		}
+
+		var subChange *Change
+		for _, c := range change.changes {
+			if c.cType == ListIndexChange && c.Record() == r &&
+				c.FieldType() == ev.FieldType {
+				subChange = c
+				break
+			}
+		}
+		if subChange == nil {
+			fields := r.Fields(ev.FieldType)
+			if len(fields) == 0 {
+				fields = []*Field{r.NewField(ev.FieldType)}
+			}
+			subChange = listIndexChange(r, fields)
+			change.changes = append(change.changes, subChange)
+		}
+
+		subChange.afterStrings = setListFields(r, ev.FieldType, ev.After)
+	}
+}
+
+// eventRecord returns the record of the given type referred to by er,
+// or nil if there is none.
+func (cp *Codeplug) eventRecord(rType RecordType, er EventRecord) *Record {
+	records := cp.rDesc[rType].records
+	if len(records) > 0 && records[0].NameField() != nil {
+		return cp.FindRecordByName(rType, er.Name)
+	}
+
+	if er.Index < 0 || er.Index >= len(records) {
+		return nil
+	}
+
+	return records[er.Index]
+}
+
+// replayFieldChange sets the field of a FieldChange event.
+func replayFieldChange(r *Record, ev *ChangeEvent) (*Change, error) {
+	if len(ev.Indexes) == 0 || len(ev.Before) == 0 || len(ev.After) == 0 {
+		return nil, fmt.Errorf("%s has too few values", ev.Type)
+	}
+
+	fields := r.Fields(ev.FieldType)
+	index := ev.Indexes[0]
+	if index < 0 || index >= len(fields) {
+		return nil, fmt.Errorf("%s: no %s field %d", r.Name(), ev.FieldType, index+1)
+	}
+
+	f := fields[index]
+	previousValue := f.String()
+	switch previousValue {
+	case ev.After[0]:
+		return nil, nil
+	case ev.Before[0]:
+	default:
+		return nil, fmt.Errorf("%s: %s: %s was changed to %s",
+			r.Name(), f.typeName, ev.Before[0], previousValue)
+	}
+
+	err := f.SetString(ev.After[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %s", r.Name(), f.typeName, err)
+	}
+
+	return fieldChange(f, previousValue), nil
+}
+
+// replayBatchFieldChange sets the fields of a BatchFieldChange event.
+// Like SetFieldStrings, it may set the fields to different values.
+func (cp *Codeplug) replayBatchFieldChange(records []*Record, ev *ChangeEvent) (*Change, error) {
+	if len(records) != len(ev.Before) || len(records) != len(ev.After) {
+		return nil, fmt.Errorf("%s has too few values", ev.Type)
+	}
+	if len(ev.FieldTypes) > 0 && len(ev.FieldTypes) != len(records) {
+		return nil, fmt.Errorf("%s has too few field types", ev.Type)
+	}
+
+	change := &Change{
+		cType: BatchFieldChange,
+	}
+	var afters []string
+	inChange := make(map[*Record]bool)
+	for i, r := range records {
+		fType := ev.FieldType
+		if len(ev.FieldTypes) > 0 {
+			fType = ev.FieldTypes[i]
+		}
+		f := r.Field(fType)
+		if f == nil {
+			return nil, fmt.Errorf("%s has no %s field", r.Name(), fType)
+		}
+		switch f.String() {
+		case ev.After[i]:
+			continue
+		case ev.Before[i]:
+		default:
+			return nil, fmt.Errorf("%s: %s: %s was changed to %s",
+				r.Name(), f.typeName, ev.Before[i], f.String())
+		}
+		_, err := r.NewFieldWithValue(fType, f.fIndex, ev.After[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %s", r.Name(), f.typeName, err)
+		}
+		if !inChange[r] {
+			inChange[r] = true
+			change.records = append(change.records, r)
+		}
+		change.fields = append(change.fields, f)
+		afters = append(afters, ev.After[i])
+	}
+	if len(change.records) == 0 {
+		return nil, nil
+	}
+
+	for i, f := range change.fields {
+		previousValue := f.String()
+		err := f.SetString(afters[i])
+		if err != nil {
+			cp.undoListIndexChanges(change)
+			return nil, fmt.Errorf("%s: %s: %s", f.record.Name(), f.typeName, err)
+		}
+		change.strings = append(change.strings, previousValue)
+		change.changes = append(change.changes, fieldChange(f, previousValue))
+	}
+	change.afterStrings = afters
+
+	return change, nil
+}
+
+// replayMoveRecords moves the records of a MoveRecordsChange event to
+// their indexes.
+func (cp *Codeplug) replayMoveRecords(records []*Record, ev *ChangeEvent) (*Change, error) {
+	if len(records) != len(ev.Indexes) {
+		return nil, fmt.Errorf("%s has too few indexes", ev.Type)
+	}
+
+	change := cp.MoveRecordsChange(records)
+	for _, r := range records {
+		cp.RemoveRecord(r)
+	}
+	for _, i := range sortedIndexes(ev.Indexes) {
+		r := records[i]
+		r.rIndex = clampIndex(ev.Indexes[i], len(cp.rDesc[r.rType].records))
+		cp.InsertRecord(r)
+	}
+
+	return change, nil
+}
+
+// replayInsertRecords inserts the records of an InsertRecordsChange
+// event.
+func (cp *Codeplug) replayInsertRecords(ev *ChangeEvent) (*Change, error) {
+	if len(ev.Contents) != len(ev.Records) || len(ev.Indexes) != len(ev.Records) {
+		return nil, fmt.Errorf("%s has too few records", ev.Type)
+	}
+
+	rType := ev.RecordType
+	if len(cp.Records(rType))+len(ev.Records) > cp.MaxRecords(rType) {
+		return nil, fmt.Errorf("too many records")
+	}
+
+	records := make([]*Record, len(ev.Records))
+	for i, contents := range ev.Contents {
+		er := ev.Records[i]
+		if er.Name != "" && cp.FindRecordByName(rType, er.Name) != nil {
+			return nil, fmt.Errorf("%s: %s already exists", cp.rDesc[rType].typeName, er.Name)
+		}
+
+		for name := range contents {
+			if _, ok := cp.nameToFt[rType][name]; !ok && name != unknownBytesName {
+				return nil, fmt.Errorf("%s: bad field name: %s", er.Name, name)
+			}
+		}
+
+		// Some fields' values depend on those of earlier
+		// fields, so the fields are added in record order.
+		r := cp.newRecord(rType, clampIndex(ev.Indexes[i], len(cp.Records(rType))))
+		r.clearUnknown()
+		for _, fi := range r.fInfos {
+			for j, str := range contents[string(fi.fType)] {
+				f, err := r.NewFieldWithValue(fi.fType, j, str)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %s", er.Name, fi.typeName, err)
+				}
+				r.addField(f)
+			}
+		}
+		if strs := contents[unknownBytesName]; len(strs) == 1 {
+			err := r.setUnknownString(strs[0])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", er.Name, err)
+			}
+		}
+		records[i] = r
+	}
+
+	change := cp.InsertRecordsChange(records)
+	for _, i := range sortedIndexes(ev.Indexes) {
+		r := records[i]
+		r.rIndex = clampIndex(ev.Indexes[i], len(cp.rDesc[rType].records))
+		cp.InsertRecord(r)
+	}
+
+	return change, nil
+}
+
+// replayFieldsChange moves, inserts or removes the fields of a
+// MoveFieldsChange, InsertFieldsChange or RemoveFieldsChange event.
+func replayFieldsChange(r *Record, ev *ChangeEvent) (*Change, error) {
+	fType := ev.FieldType
+	var fields []*Field
+
+	switch ev.Type {
+	case MoveFieldsChange, RemoveFieldsChange:
+		if ev.Type == MoveFieldsChange && len(ev.Indexes) != len(ev.Before) {
+			return nil, fmt.Errorf("%s has too few indexes", ev.Type)
+		}
+		for _, str := range ev.Before {
+			f := r.FindFieldByName(fType, str)
+			if f == nil {
+				return nil, fmt.Errorf("%s: no %s %s", r.Name(), fType, str)
+			}
+			fields = append(fields, f)
+		}
+
+	case InsertFieldsChange:
+		if len(ev.Indexes) != len(ev.After) {
+			return nil, fmt.Errorf("%s has too few indexes", ev.Type)
+		}
+		if len(r.Fields(fType))+len(ev.After) > r.MaxFields(fType) {
+			return nil, fmt.Errorf("%s: too many %s fields", r.Name(), fType)
+		}
+		for i, str := range ev.After {
+			f, err := r.NewFieldWithValue(fType, ev.Indexes[i], str)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %s", r.Name(), fType, err)
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	var change *Change
+	switch ev.Type {
+	case MoveFieldsChange:
+		change = r.MoveFieldsChange(fields)
+		for _, f := range fields {
+			r.RemoveField(f)
+		}
+		for _, i := range sortedIndexes(ev.Indexes) {
+			f := fields[i]
+			f.fIndex = clampIndex(ev.Indexes[i], len(r.Fields(fType)))
+			r.InsertField(f)
+		}
+
+	case InsertFieldsChange:
+		change = r.InsertFieldsChange(fields)
+		for _, i := range sortedIndexes(ev.Indexes) {
+			f := fields[i]
+			f.fIndex = clampIndex(f.fIndex, len(r.Fields(fType)))
+			r.InsertField(f)
+		}
+
+	case RemoveFieldsChange:
+		change = r.RemoveFieldsChange(fields)
+		for _, f := range fields {
+			r.RemoveField(f)
+		}
+	}
+
+	return change, nil
+}
+
+// sortedIndexes returns the positions within indexes of its elements,
+// in increasing order of the elements.  Inserting items at their
+// indexes in this order places each at its index.
+func sortedIndexes(indexes []int) []int {
+	order := make([]int, len(indexes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return indexes[order[i]] < indexes[order[j]]
+	})
+
+	return order
+}
+
+// clampIndex returns index, limited to the range 0 through max.
+func clampIndex(index int, max int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > max {
+		return max
+	}
+
+	return index
+}
@@ -0,0 +1,186 @@
+package codeplug
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// A memoryTransport carries the changes made to one codeplug to
+// another, in their JSON wire form, as a network transport would.
+type memoryTransport struct {
+	t      *testing.T
+	events [][]byte
+	cancel func()
+}
+
+// newMemoryTransport returns a transport queueing the changes made to
+// the codeplug, other than those it replays.
+func newMemoryTransport(t *testing.T, from *Codeplug) *memoryTransport {
+	mt := &memoryTransport{t: t}
+	mt.cancel = from.SubscribeChanges(func(change *Change) {
+		if change.Remote() {
+			return
+		}
+		data, err := json.Marshal(change.Event())
+		if err != nil {
+			t.Fatal(err)
+		}
+		mt.events = append(mt.events, data)
+	})
+
+	return mt
+}
+
+// deliver replays the queued changes against the codeplug.
+func (mt *memoryTransport) deliver(to *Codeplug) {
+	mt.t.Helper()
+
+	for _, data := range mt.events {
+		var ev ChangeEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			mt.t.Fatal(err)
+		}
+		if err := to.ReplayChange(&ev); err != nil {
+			mt.t.Fatalf("replaying %s: %s", data, err)
+		}
+	}
+	mt.events = nil
+}
+
+func TestReplayChanges(t *testing.T) {
+	src := historyCodeplug(t)
+	defer src.Free()
+	dst := copyCodeplug(t, src)
+	defer dst.Free()
+
+	mt := newMemoryTransport(t, src)
+	defer mt.cancel()
+
+	channels := src.Records(RtChannelInformation)
+	rd := src.rDesc[RtChannelInformation]
+
+	steps := []struct {
+		name string
+		edit func() error
+	}{
+		{"field", func() error {
+			f := channels[0].Field(FtTot)
+			previousValue := f.String()
+			if err := f.SetString("90"); err != nil {
+				return err
+			}
+			f.Change(previousValue).Complete()
+			return nil
+		}},
+		{"batch", func() error {
+			fields := []*Field{
+				channels[0].Field(FtColorCode),
+				channels[1].Field(FtTot),
+			}
+			return src.SetFieldStrings(fields, []string{"5", "120"})
+		}},
+		{"insert", func() error {
+			r := src.bytesToRecord(RtChannelInformation, 1, channels[0].recordBytes())
+			if err := r.NameField().SetString("Inserted"); err != nil {
+				return err
+			}
+			change := src.InsertRecordsChange([]*Record{r})
+			if err := src.InsertRecord(r); err != nil {
+				return err
+			}
+			change.Complete()
+			return nil
+		}},
+		{"move", func() error {
+			r := src.FindRecordByName(RtChannelInformation, "Inserted")
+			change := src.MoveRecordsChange([]*Record{r})
+			src.MoveRecord(len(src.Records(RtChannelInformation)), r)
+			change.Complete()
+			return nil
+		}},
+		{"remove", func() error {
+			r := src.Records(RtChannelInformation)[0]
+			return src.RemoveRecords([]*Record{r}, DeleteCascade)
+		}},
+		{"bytes", func() error {
+			r := src.bytesToRecord(RtChannelInformation, 0, rd.records[0].recordBytes())
+			if err := r.NameField().SetString("Bytes"); err != nil {
+				return err
+			}
+			return src.SetBytes(rd.offset, r.recordBytes())
+		}},
+		{"undo", func() error {
+			src.UndoChange()
+			return nil
+		}},
+		{"redo", func() error {
+			src.RedoChange()
+			return nil
+		}},
+	}
+
+	for _, step := range steps {
+		if err := step.edit(); err != nil {
+			t.Fatalf("%s: %s", step.name, err)
+		}
+		if len(mt.events) == 0 {
+			t.Fatalf("%s: no change was published", step.name)
+		}
+		mt.deliver(dst)
+		if !bytes.Equal(dst.ImageBytes(), src.ImageBytes()) {
+			t.Fatalf("%s: codeplugs differ after replay", step.name)
+		}
+	}
+
+	if dst.Records(RtChannelInformation)[0].Name() != "Bytes" {
+		t.Errorf("replayed bytes didn't set the first channel's name")
+	}
+
+	// Replaying a change already made has no effect.
+	f := channels[1].Field(FtTot)
+	previousValue := f.String()
+	if err := f.SetString("30"); err != nil {
+		t.Fatal(err)
+	}
+	f.Change(previousValue).Complete()
+	events := mt.events
+	mt.deliver(dst)
+	mt.events = events
+	mt.deliver(dst)
+	if !bytes.Equal(dst.ImageBytes(), src.ImageBytes()) {
+		t.Error("codeplugs differ after replaying a change twice")
+	}
+}
+
+func TestReplayConflict(t *testing.T) {
+	src := historyCodeplug(t)
+	defer src.Free()
+	dst := copyCodeplug(t, src)
+	defer dst.Free()
+
+	if err := dst.SetFields(dst.Records(RtChannelInformation)[:1], FtTot, "30"); err != nil {
+		t.Fatal(err)
+	}
+	before := dst.ImageBytes()
+
+	var events []*ChangeEvent
+	cancel := src.SubscribeChanges(func(change *Change) {
+		events = append(events, change.Event())
+	})
+	defer cancel()
+	channels := src.Records(RtChannelInformation)
+	fields := []*Field{channels[1].Field(FtTot), channels[0].Field(FtTot)}
+	if err := src.SetFieldStrings(fields, []string{"90", "120"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first channel's value was changed in the destination, so
+	// the batch must not be applied, even in part.
+	if err := dst.ReplayChange(events[0]); err == nil {
+		t.Error("a conflicting batch was replayed")
+	}
+	if !bytes.Equal(dst.ImageBytes(), before) {
+		t.Error("a conflicting batch changed the codeplug")
+	}
+}
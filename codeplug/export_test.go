@@ -0,0 +1,188 @@
+package codeplug
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// scribble sets bytes that no exported field describes: unknown bits
+// of the first channel, and a byte each of the rdt header, of a gap
+// between record slots and of the last, empty, channel slot.
+func scribble(t *testing.T, cp *Codeplug, value byte) {
+	t.Helper()
+
+	setUnknownBits(t, cp.Records(RtChannelInformation)[0])
+
+	gap := -1
+	for offset := range cp.ImageBytes() {
+		l, err := cp.Locate(offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.RecordType == "" {
+			gap = offset
+			break
+		}
+	}
+	if gap < 0 {
+		t.Fatalf("%s has no gap between record slots", cp.Type())
+	}
+
+	rd := cp.rDesc[RtChannelInformation]
+	slot := rd.offset + rd.max*rd.size - 1
+	channels := len(cp.Records(RtChannelInformation))
+
+	for _, offset := range []int{0x10, gap, slot} {
+		if err := cp.SetBytes(offset, []byte{value}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(cp.Records(RtChannelInformation)) != channels {
+		t.Fatalf("%s: setting an empty slot's byte added a channel", cp.Type())
+	}
+}
+
+// roundTripCodeplug returns a new blank codeplug of the given type and
+// frequency range.
+func roundTripCodeplug(t *testing.T, cpType CodeplugType, fRange FrequencyRange) *Codeplug {
+	t.Helper()
+
+	cp, err := NewBlankCodeplug(cpType, fRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cp
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	formats := []struct {
+		name       string
+		exportFunc func(*Codeplug, *bytes.Buffer) error
+		importFunc func(*Codeplug, *bytes.Buffer) error
+	}{
+		{
+			"text",
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.Export(buf) },
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.Import(buf) },
+		},
+		{
+			"json",
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.ExportJson(buf) },
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.ImportJson(buf) },
+		},
+		{
+			"yaml",
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.ExportYaml(buf) },
+			func(cp *Codeplug, buf *bytes.Buffer) error { return cp.ImportYaml(buf) },
+		},
+	}
+
+	for _, cpType := range []CodeplugType{CtMd380, CtMd390, CtUv380} {
+		ranges := FrequencyRanges(cpType)
+		src := roundTripCodeplug(t, cpType, ranges[0])
+		defer src.Free()
+		channels := src.Records(RtChannelInformation)[:1]
+		if err := src.SetFields(channels, FtChannelName, "Round Trip"); err != nil {
+			t.Fatal(err)
+		}
+		scribble(t, src, 0x5a)
+		want := src.ImageBytes()
+
+		for _, format := range formats {
+			var buf bytes.Buffer
+			if err := format.exportFunc(src, &buf); err != nil {
+				t.Fatalf("%s %s: %s", cpType, format.name, err)
+			}
+
+			// The template differs from the source in its
+			// frequency range, where it may, and in its bytes.
+			dst := roundTripCodeplug(t, cpType, ranges[len(ranges)-1])
+			defer dst.Free()
+			scribble(t, dst, 0xa5)
+
+			if err := format.importFunc(dst, &buf); err != nil {
+				t.Fatalf("%s %s: %s", cpType, format.name, err)
+			}
+			got := dst.ImageBytes()
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("%s %s: byte 0x%05x is 0x%02x, not 0x%02x",
+						cpType, format.name, i, got[i], want[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestExportUnknownBytes(t *testing.T) {
+	cp, err := NewCodeplugFromBytes(blankImage(t, CtMd380), CtMd380)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	for _, r := range cp.Records(RtChannelInformation) {
+		r.clearUnknown()
+	}
+	if text := exportString(t, cp); strings.Contains(text, unknownBytesName) {
+		t.Errorf("export of all-zero unknown bytes includes %s", unknownBytesName)
+	}
+
+	r := cp.Records(RtChannelInformation)[0]
+	setUnknownBits(t, r)
+	want := r.UnknownBytes()
+	text := exportString(t, cp)
+	if strings.Count(text, unknownBytesName) != 1 {
+		t.Fatalf("export doesn't include one channel's %s", unknownBytesName)
+	}
+
+	// Importing restores the set bits and zeroes the omitted ones.
+	other, err := NewCodeplugFromBytes(blankImage(t, CtMd380), CtMd380)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Free()
+	for _, r := range other.Records(RtChannelInformation) {
+		setUnknownBits(t, r)
+	}
+	if err := other.Import(strings.NewReader(text)); err != nil {
+		t.Fatal(err)
+	}
+	records := other.Records(RtChannelInformation)
+	if !bytes.Equal(records[0].UnknownBytes(), want) {
+		t.Errorf("imported channel's unknown bytes differ")
+	}
+	for _, r := range records[1:] {
+		if r.hasUnknownBits() {
+			t.Errorf("%s kept unknown bits omitted from the import", r.Name())
+		}
+	}
+}
+
+func TestImportImageBytesRollback(t *testing.T) {
+	ranges := FrequencyRanges(CtMd380)
+	src := roundTripCodeplug(t, CtMd380, ranges[0])
+	defer src.Free()
+	scribble(t, src, 0x5a)
+	text := exportString(t, src)
+
+	dst := roundTripCodeplug(t, CtMd380, ranges[len(ranges)-1])
+	defer dst.Free()
+	scribble(t, dst, 0xa5)
+	want := dst.ImageBytes()
+
+	for _, bad := range []string{
+		strings.Replace(text, "\tBytes[1]: 0x00000:", "\tBytes[1]: 0x00000:zz", 1),
+		text + "\nBogus:\n",
+	} {
+		if err := dst.Import(strings.NewReader(bad)); err == nil {
+			t.Fatal("import of bad text succeeded")
+		}
+		if !bytes.Equal(dst.ImageBytes(), want) {
+			t.Errorf("failed import changed the codeplug's image")
+		}
+	}
+}
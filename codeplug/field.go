@@ -31,6 +31,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode/utf8"
 )
 
@@ -75,7 +76,6 @@ type fInfo struct {
 	enabler        FieldType
 	disabler       FieldType
 	listRecordType RecordType
-	rInfo          *rInfo
 }
 
 // A FieldType represents a field's type
@@ -278,20 +278,6 @@ func (f *Field) FullTypeName() string {
 	return s
 }
 
-// valid returns nil if the field's value is valid.
-func (f *Field) valid() error {
-	err := f.value.valid(f)
-	if err != nil {
-		if _, invalid := f.value.(invalidValue); !invalid {
-			f.value = invalidValue{value: f.value}
-		}
-	}
-	if !f.IsEnabled() {
-		return nil
-	}
-	return err
-}
-
 // IsValid returns false if the field has previously been determined
 // to be invalid. The field can only be invalid if the value read from
 // the codeplug file was invalid.
@@ -424,10 +410,7 @@ func (fd *fDesc) fieldDeleted(fIndex int, recordBytes []byte) bool {
 
 // deleteField marks the field at fIndex as deleted.
 func (fd *fDesc) deleteField(fIndex int, recordBytes []byte) {
-	bytes := fd.bytes(fIndex, recordBytes)
-	for i := range bytes {
-		bytes[i] = 0
-	}
+	fd.storeBytes(make([]byte, fd.size()), fIndex, recordBytes)
 }
 
 // bytes returns the bytes of the field from recordBytes.
@@ -493,11 +476,6 @@ func (fi *fInfo) size() (fSize int) {
 	return (fi.bitSize + 7) / 8
 }
 
-// deferredValidFields contains a list fields whose values could not be
-// checked for validity because they have not yet been loaded. They will
-// be checked after all fields are loaded.
-var deferredValidFields []*Field
-
 // frequency is a field value representing a frequency in Hertz.
 type frequency float64
 
@@ -728,8 +706,24 @@ func (v *span) validValue(f *Field, value int) error {
 		return fmt.Errorf("is not a multiple of %d", sp.interval)
 	}
 
+	if value >= 1<<uint(f.bitSize) {
+		return fmt.Errorf("must be at most %d",
+			(1<<uint(f.bitSize)-1)*int(sp.scale))
+	}
+
+	return nil
+}
+
+// warning returns an error if the span's value lies outside its range.
+// Such values are kept, since radios accept them, but Validate reports
+// them as warnings.
+func (v *span) warning(f *Field) error {
+	sp := *f.span
+
+	value := int(*v)
 	if value < int(sp.min) || value > int(sp.max) {
-		fmt.Errorf("must be between %d and %d", sp.min, sp.max)
+		return fmt.Errorf("should be between %d and %d",
+			int(sp.min)*int(sp.scale), int(sp.max)*int(sp.scale))
 	}
 
 	return nil
@@ -1140,6 +1134,17 @@ func (v *name) SetString(f *Field, s string) error {
 	return nil
 }
 
+// truncateName returns s, shortened if necessary to fit in the name
+// field f.
+func truncateName(f *Field, s string) string {
+	maxLen := f.size()/2 - 1
+	if runes := []rune(s); len(runes) > maxLen {
+		s = string(runes[:maxLen])
+	}
+
+	return s
+}
+
 // valid returns nil if the name's value is valid.
 func (v *name) valid(f *Field) error {
 	return nil
@@ -1191,7 +1196,7 @@ func (v *privacyNumber) SetString(f *Field, s string) error {
 func (v *privacyNumber) valid(f *Field) error {
 	sibling := f.sibling(FtPrivacy)
 	if sibling == nil {
-		deferredValidFields = append(deferredValidFields, f)
+		f.record.codeplug.deferValid(f)
 		return nil
 	}
 	ss := sibling.String()
@@ -1336,7 +1341,7 @@ func (v *listIndex) SetString(f *Field, s string) error {
 			return nil
 		}
 	}
-	return fmt.Errorf("listIndex.SetString: bad list entry name", s)
+	return fmt.Errorf("listIndex.SetString: bad list entry name: %s", s)
 }
 
 // valid returns nil if the listIndex's value is valid.
@@ -1353,7 +1358,7 @@ func (v *listIndex) valid(f *Field) error {
 
 	listNames := fd.record.codeplug.rDesc[fd.listRecordType].ListNames()
 	if listNames == nil {
-		deferredValidFields = append(deferredValidFields, f)
+		f.record.codeplug.deferValid(f)
 		return nil
 	}
 
@@ -1385,34 +1390,35 @@ func (v *listIndex) store(f *Field, recordBytes []byte) {
 }
 
 var cachedCtcssDcsStrings []string
+var ctcssDcsStringsOnce sync.Once
 
 func ctcssDcsStrings() []string {
-	if cachedCtcssDcsStrings != nil {
-		return cachedCtcssDcsStrings
-	}
+	ctcssDcsStringsOnce.Do(func() {
+		count := len(ctcssFrequencies) + 2*len(dcsCodes) + 1
+		strs := make([]string, count)
 
-	count := len(ctcssFrequencies) + 2*len(dcsCodes) + 1
-	cachedCtcssDcsStrings = make([]string, count)
+		i := 0
 
-	i := 0
+		strs[i] = "None"
+		i++
 
-	cachedCtcssDcsStrings[i] = "None"
-	i++
+		for _, f := range ctcssFrequencies {
+			strs[i] = fmt.Sprintf("%d.%d", f/10, f%10)
+			i++
+		}
 
-	for _, f := range ctcssFrequencies {
-		cachedCtcssDcsStrings[i] = fmt.Sprintf("%d.%d", f/10, f%10)
-		i++
-	}
+		for _, c := range dcsCodes {
+			strs[i] = fmt.Sprintf("D%03dN", c)
+			i++
+		}
 
-	for _, c := range dcsCodes {
-		cachedCtcssDcsStrings[i] = fmt.Sprintf("D%03dN", c)
-		i++
-	}
+		for _, c := range dcsCodes {
+			strs[i] = fmt.Sprintf("D%03dI", c)
+			i++
+		}
 
-	for _, c := range dcsCodes {
-		cachedCtcssDcsStrings[i] = fmt.Sprintf("D%03dI", c)
-		i++
-	}
+		cachedCtcssDcsStrings = strs
+	})
 
 	return cachedCtcssDcsStrings
 }
@@ -1521,7 +1527,7 @@ func ctcssDcsStringToBinary(s string) int {
 			return -1
 		}
 
-		v, err := strconv.ParseInt(s, 10, 16)
+		v, err := strconv.ParseInt(s[1:4], 10, 16)
 		value = int(v)
 		if err != nil || !goodDcsCode(value) {
 			return -1
@@ -30,107 +30,212 @@ package codeplug
 // Codeplug types
 const (
 	CtMd380 CodeplugType = "md380"
+	CtMd390 CodeplugType = "md390"
+	CtUv380 CodeplugType = "uv380"
 )
 
 // Record types
 const (
+	// RtChannelInformation is the type of Channel Information records.
 	RtChannelInformation RecordType = "ChannelInformation"
-	RtDigitalContacts    RecordType = "DigitalContacts"
-	RtGeneralSettings    RecordType = "GeneralSettings"
-	RtGroupList          RecordType = "GroupList"
-	RtRdtHeader          RecordType = "RdtHeader"
-	RtScanList           RecordType = "ScanList"
-	RtTextMessage        RecordType = "TextMessage"
-	RtZoneInformation    RecordType = "ZoneInformation"
+	// RtDigitalContacts is the type of Digital Contacts records.
+	RtDigitalContacts RecordType = "DigitalContacts"
+	// RtGeneralSettings is the type of General Settings records.
+	RtGeneralSettings RecordType = "GeneralSettings"
+	// RtGpsSystem is the type of GPS System records.
+	RtGpsSystem RecordType = "GpsSystem"
+	// RtGroupList is the type of Digital Rx Group List records.
+	RtGroupList RecordType = "GroupList"
+	// RtRdtHeader is the type of Rdt Header records.
+	RtRdtHeader RecordType = "RdtHeader"
+	// RtScanList is the type of Scan List records.
+	RtScanList RecordType = "ScanList"
+	// RtTextMessage is the type of Text Message records.
+	RtTextMessage RecordType = "TextMessage"
+	// RtZoneInformation is the type of Zone Information records.
+	RtZoneInformation RecordType = "ZoneInformation"
 )
 
 // Field types
 const (
-	FtAdmitCriteria           FieldType = "AdmitCriteria"
-	FtAllowTalkaround         FieldType = "AllowTalkaround"
-	FtAutoscan                FieldType = "Autoscan"
-	FtBandwidth               FieldType = "Bandwidth"
-	FtCallAlertToneDuration   FieldType = "CallAlertToneDuration"
-	FtCallID                  FieldType = "CallID"
-	FtCallReceiveTone         FieldType = "CallReceiveTone"
-	FtCallType                FieldType = "CallType"
-	FtChFreeIndicationTone    FieldType = "ChFreeIndicationTone"
-	FtChannelMember           FieldType = "ChannelMember"
-	FtChannelMode             FieldType = "ChannelMode"
-	FtChannelName             FieldType = "ChannelName"
-	FtColorCode               FieldType = "ColorCode"
+	// FtAdmitCriteria is the type of the Admit Criteria field of Channel Information records.
+	FtAdmitCriteria FieldType = "AdmitCriteria"
+	// FtAllowTalkaround is the type of the Allow Talkaround field of Channel Information records.
+	FtAllowTalkaround FieldType = "AllowTalkaround"
+	// FtAutoscan is the type of the Autoscan field of Channel Information records.
+	FtAutoscan FieldType = "Autoscan"
+	// FtBandwidth is the type of the Bandwidth field of Channel Information records.
+	FtBandwidth FieldType = "Bandwidth"
+	// FtCallAlertToneDuration is the type of the Call Alert Tone Duration (S) field of General Settings records.
+	FtCallAlertToneDuration FieldType = "CallAlertToneDuration"
+	// FtCallID is the type of the Call ID field of Digital Contacts records.
+	FtCallID FieldType = "CallID"
+	// FtCallReceiveTone is the type of the Call Receive Tone field of Digital Contacts records.
+	FtCallReceiveTone FieldType = "CallReceiveTone"
+	// FtCallType is the type of the Call Type field of Digital Contacts records.
+	FtCallType FieldType = "CallType"
+	// FtChFreeIndicationTone is the type of the Channel Free Indication Tone field of General Settings records.
+	FtChFreeIndicationTone FieldType = "ChFreeIndicationTone"
+	// FtChannelMember is the type of the Channel Member field of Zone Information and Scan List records.
+	FtChannelMember FieldType = "ChannelMember"
+	// FtChannelMode is the type of the Channel Mode field of Channel Information records.
+	FtChannelMode FieldType = "ChannelMode"
+	// FtChannelName is the type of the Channel Name field of Channel Information records.
+	FtChannelName FieldType = "ChannelName"
+	// FtColorCode is the type of the Color Code field of Channel Information records.
+	FtColorCode FieldType = "ColorCode"
+	// FtCompressedUdpDataHeader is the type of the Compressed UDP Data Header field of Channel Information records.
 	FtCompressedUdpDataHeader FieldType = "CompressedUdpDataHeader"
-	FtContactMember           FieldType = "ContactMember"
-	FtContactName             FieldType = "ContactName"
-	FtCtcssDecode             FieldType = "CtcssDecode"
-	FtCtcssEncode             FieldType = "CtcssEncode"
-	FtDataCallConfirmed       FieldType = "DataCallConfirmed"
-	FtDecode1                 FieldType = "Decode1"
-	FtDecode2                 FieldType = "Decode2"
-	FtDecode3                 FieldType = "Decode3"
-	FtDecode4                 FieldType = "Decode4"
-	FtDecode5                 FieldType = "Decode5"
-	FtDecode6                 FieldType = "Decode6"
-	FtDecode7                 FieldType = "Decode7"
-	FtDecode8                 FieldType = "Decode8"
-	FtDisableAllLeds          FieldType = "DisableAllLeds"
-	FtDisableAllTones         FieldType = "DisableAllTones"
-	FtDisplayPTTID            FieldType = "DisplayPTTID"
-	FtEmergencyAlarmAck       FieldType = "EmergencyAlarmAck"
-	FtGroupCallHangTime       FieldType = "GroupCallHangTime"
-	FtGroupList               FieldType = "GroupList"
-	FtHighFrequency           FieldType = "HighFrequency"
-	FtIntroScreen             FieldType = "IntroScreen"
-	FtIntroScreenLine1        FieldType = "IntroScreenLine1"
-	FtIntroScreenLine2        FieldType = "IntroScreenLine2"
-	FtLoneWorker              FieldType = "LoneWorker"
-	FtLoneWorkerReminderTime  FieldType = "LoneWorkerReminderTime"
-	FtLoneWorkerResponseTime  FieldType = "LoneWorkerResponseTime"
-	FtLowFrequency            FieldType = "LowFrequency"
-	FtMode                    FieldType = "Mode"
-	FtMonitorType             FieldType = "MonitorType"
-	FtName                    FieldType = "Name"
-	FtPcProgPw                FieldType = "PcProgPw"
-	FtPower                   FieldType = "Power"
-	FtPowerOnPassword         FieldType = "PowerOnPassword"
-	FtPriorityChannel1        FieldType = "PriorityChannel1"
-	FtPriorityChannel2        FieldType = "PriorityChannel2"
-	FtPrioritySampleTime      FieldType = "PrioritySampleTime"
-	FtPrivacy                 FieldType = "Privacy"
-	FtPrivacyNumber           FieldType = "PrivacyNumber"
-	FtPrivateCallConfirmed    FieldType = "PrivateCallConfirmed"
-	FtPrivateCallHangTime     FieldType = "PrivateCallHangTime"
-	FtPwAndLockEnable         FieldType = "PwAndLockEnable"
-	FtQtReverse               FieldType = "QtReverse"
-	FtRadioID                 FieldType = "RadioID"
-	FtRadioName               FieldType = "RadioName"
-	FtRadioProgPw             FieldType = "RadioProgPw"
-	FtRepeaterSlot            FieldType = "RepeaterSlot"
-	FtReverseBurst            FieldType = "ReverseBurst"
-	FtRxFrequency             FieldType = "RxFrequency"
-	FtRxLowBatteryInterval    FieldType = "RxLowBatteryInterval"
-	FtRxOnly                  FieldType = "RxOnly"
-	FtRxRefFrequency          FieldType = "RxRefFrequency"
-	FtRxSignallingSystem      FieldType = "RxSignallingSystem"
-	FtSaveModeReceive         FieldType = "SaveModeReceive"
-	FtSavePreamble            FieldType = "SavePreamble"
-	FtScanAnalogHangTime      FieldType = "ScanAnalogHangTime"
-	FtScanDigitalHangTime     FieldType = "ScanDigitalHangTime"
-	FtScanList                FieldType = "ScanList"
-	FtSetKeypadLockTime       FieldType = "SetKeypadLockTime"
-	FtSignallingHoldTime      FieldType = "SignallingHoldTime"
-	FtSquelch                 FieldType = "Squelch"
-	FtTalkPermitTone          FieldType = "TalkPermitTone"
-	FtTextMessage             FieldType = "TextMessage"
-	FtTot                     FieldType = "Tot"
-	FtTotRekeyDelay           FieldType = "TotRekeyDelay"
-	FtTxDesignatedChannel     FieldType = "TxDesignatedChannel"
-	FtTxFrequency             FieldType = "TxFrequency"
-	FtTxPreambleDuration      FieldType = "TxPreambleDuration"
-	FtTxRefFrequency          FieldType = "TxRefFrequency"
-	FtTxSignallingSystem      FieldType = "TxSignallingSystem"
-	FtVox                     FieldType = "Vox"
-	FtVoxSensitivity          FieldType = "VoxSensitivity"
+	// FtContactMember is the type of the Contact Member field of Digital Rx Group List records.
+	FtContactMember FieldType = "ContactMember"
+	// FtContactName is the type of the Contact Name field of Digital Contacts and Channel Information records.
+	FtContactName FieldType = "ContactName"
+	// FtCtcssDecode is the type of the CTCSS/DCS Decode field of Channel Information records.
+	FtCtcssDecode FieldType = "CtcssDecode"
+	// FtCtcssEncode is the type of the CTCSS/DCS Encode field of Channel Information records.
+	FtCtcssEncode FieldType = "CtcssEncode"
+	// FtDataCallConfirmed is the type of the Data Call Confirmed field of Channel Information records.
+	FtDataCallConfirmed FieldType = "DataCallConfirmed"
+	// FtDecode1 is the type of the Decode 1 field of Channel Information records.
+	FtDecode1 FieldType = "Decode1"
+	// FtDecode2 is the type of the Decode 2 field of Channel Information records.
+	FtDecode2 FieldType = "Decode2"
+	// FtDecode3 is the type of the Decode 3 field of Channel Information records.
+	FtDecode3 FieldType = "Decode3"
+	// FtDecode4 is the type of the Decode 4 field of Channel Information records.
+	FtDecode4 FieldType = "Decode4"
+	// FtDecode5 is the type of the Decode 5 field of Channel Information records.
+	FtDecode5 FieldType = "Decode5"
+	// FtDecode6 is the type of the Decode 6 field of Channel Information records.
+	FtDecode6 FieldType = "Decode6"
+	// FtDecode7 is the type of the Decode 7 field of Channel Information records.
+	FtDecode7 FieldType = "Decode7"
+	// FtDecode8 is the type of the Decode 8 field of Channel Information records.
+	FtDecode8 FieldType = "Decode8"
+	// FtDestinationContact is the type of the Destination Contact field of GPS System records.
+	FtDestinationContact FieldType = "DestinationContact"
+	// FtDisableAllLeds is the type of the Disable All LEDS field of General Settings records.
+	FtDisableAllLeds FieldType = "DisableAllLeds"
+	// FtDisableAllTones is the type of the Disable All Tones field of General Settings records.
+	FtDisableAllTones FieldType = "DisableAllTones"
+	// FtDisplayPTTID is the type of the Display PTT ID field of Channel Information records.
+	FtDisplayPTTID FieldType = "DisplayPTTID"
+	// FtEmergencyAlarmAck is the type of the Emergency Alarm Ack field of Channel Information records.
+	FtEmergencyAlarmAck FieldType = "EmergencyAlarmAck"
+	// FtGpsSystem is the type of the GPS System field of Channel Information records.
+	FtGpsSystem FieldType = "GpsSystem"
+	// FtGroupCallHangTime is the type of the Group Call Hang Time (mS) field of General Settings records.
+	FtGroupCallHangTime FieldType = "GroupCallHangTime"
+	// FtGroupList is the type of the Group List field of Channel Information records.
+	FtGroupList FieldType = "GroupList"
+	// FtHighFrequency is the type of the High Frequency field of Rdt Header records.
+	FtHighFrequency FieldType = "HighFrequency"
+	// FtIntroScreen is the type of the Intro Screen field of General Settings records.
+	FtIntroScreen FieldType = "IntroScreen"
+	// FtIntroScreenLine1 is the type of the Intro Screen Line 1 field of General Settings records.
+	FtIntroScreenLine1 FieldType = "IntroScreenLine1"
+	// FtIntroScreenLine2 is the type of the Intro Screen Line 2 field of General Settings records.
+	FtIntroScreenLine2 FieldType = "IntroScreenLine2"
+	// FtLoneWorker is the type of the Lone Worker field of Channel Information records.
+	FtLoneWorker FieldType = "LoneWorker"
+	// FtLoneWorkerReminderTime is the type of the Lone Worker Reminder Time (S) field of General Settings records.
+	FtLoneWorkerReminderTime FieldType = "LoneWorkerReminderTime"
+	// FtLoneWorkerResponseTime is the type of the Lone Worker Response Time (min) field of General Settings records.
+	FtLoneWorkerResponseTime FieldType = "LoneWorkerResponseTime"
+	// FtLowFrequency is the type of the Low Frequency field of Rdt Header records.
+	FtLowFrequency FieldType = "LowFrequency"
+	// FtMode is the type of the Mode field of General Settings records.
+	FtMode FieldType = "Mode"
+	// FtMonitorType is the type of the Monitor Type field of General Settings records.
+	FtMonitorType FieldType = "MonitorType"
+	// FtName is the type of the Group List Name field of Digital Rx Group List, Zone Information and Scan List records.
+	FtName FieldType = "Name"
+	// FtPcProgPw is the type of the PC Programming Password field of General Settings records.
+	FtPcProgPw FieldType = "PcProgPw"
+	// FtPower is the type of the Power field of Channel Information records.
+	FtPower FieldType = "Power"
+	// FtPowerOnPassword is the type of the Power On Password field of General Settings records.
+	FtPowerOnPassword FieldType = "PowerOnPassword"
+	// FtPriorityChannel1 is the type of the Priority Channel 1 field of Scan List records.
+	FtPriorityChannel1 FieldType = "PriorityChannel1"
+	// FtPriorityChannel2 is the type of the Priority Channel 2 field of Scan List records.
+	FtPriorityChannel2 FieldType = "PriorityChannel2"
+	// FtPrioritySampleTime is the type of the Priority Sample Time (mS) field of Scan List records.
+	FtPrioritySampleTime FieldType = "PrioritySampleTime"
+	// FtPrivacy is the type of the Privacy field of Channel Information records.
+	FtPrivacy FieldType = "Privacy"
+	// FtPrivacyNumber is the type of the Privacy Number field of Channel Information records.
+	FtPrivacyNumber FieldType = "PrivacyNumber"
+	// FtPrivateCallConfirmed is the type of the Private Call Confimed field of Channel Information records.
+	FtPrivateCallConfirmed FieldType = "PrivateCallConfirmed"
+	// FtPrivateCallHangTime is the type of the Private Call Hang Time (mS) field of General Settings records.
+	FtPrivateCallHangTime FieldType = "PrivateCallHangTime"
+	// FtPwAndLockEnable is the type of the Password And Lock Enable field of General Settings records.
+	FtPwAndLockEnable FieldType = "PwAndLockEnable"
+	// FtQtReverse is the type of the QT Reverse field of Channel Information records.
+	FtQtReverse FieldType = "QtReverse"
+	// FtRadioID is the type of the Radio ID field of General Settings records.
+	FtRadioID FieldType = "RadioID"
+	// FtRadioName is the type of the Radio Name field of General Settings records.
+	FtRadioName FieldType = "RadioName"
+	// FtRadioProgPw is the type of the Radio Programming Password field of General Settings records.
+	FtRadioProgPw FieldType = "RadioProgPw"
+	// FtRepeaterSlot is the type of the Repeater Slot field of Channel Information records.
+	FtRepeaterSlot FieldType = "RepeaterSlot"
+	// FtReportInterval is the type of the Report Interval (S) field of GPS System records.
+	FtReportInterval FieldType = "ReportInterval"
+	// FtReverseBurst is the type of the Reverse Burst/Turn Off Code field of Channel Information records.
+	FtReverseBurst FieldType = "ReverseBurst"
+	// FtRevertChannel is the type of the Revert Channel field of GPS System records.
+	FtRevertChannel FieldType = "RevertChannel"
+	// FtRxFrequency is the type of the Rx Frequency (MHz) field of Channel Information records.
+	FtRxFrequency FieldType = "RxFrequency"
+	// FtRxLowBatteryInterval is the type of the Rx Low Battery Interval (S) field of General Settings records.
+	FtRxLowBatteryInterval FieldType = "RxLowBatteryInterval"
+	// FtRxOnly is the type of the Rx Only field of Channel Information records.
+	FtRxOnly FieldType = "RxOnly"
+	// FtRxRefFrequency is the type of the Rx Ref Frequency field of Channel Information records.
+	FtRxRefFrequency FieldType = "RxRefFrequency"
+	// FtRxSignallingSystem is the type of the Rx Signaling System field of Channel Information records.
+	FtRxSignallingSystem FieldType = "RxSignallingSystem"
+	// FtSaveModeReceive is the type of the Save Mode Receive field of General Settings records.
+	FtSaveModeReceive FieldType = "SaveModeReceive"
+	// FtSavePreamble is the type of the Save Preamble field of General Settings records.
+	FtSavePreamble FieldType = "SavePreamble"
+	// FtScanAnalogHangTime is the type of the Scan Analog Hang Time (mS) field of General Settings records.
+	FtScanAnalogHangTime FieldType = "ScanAnalogHangTime"
+	// FtScanDigitalHangTime is the type of the Scan Digital Hang Time (mS) field of General Settings records.
+	FtScanDigitalHangTime FieldType = "ScanDigitalHangTime"
+	// FtScanList is the type of the Scan List field of Channel Information records.
+	FtScanList FieldType = "ScanList"
+	// FtSetKeypadLockTime is the type of the Set Keypad Lock Time (S) field of General Settings records.
+	FtSetKeypadLockTime FieldType = "SetKeypadLockTime"
+	// FtSignallingHoldTime is the type of the Signalling Hold Time (mS) field of Scan List records.
+	FtSignallingHoldTime FieldType = "SignallingHoldTime"
+	// FtSquelch is the type of the Squelch field of Channel Information records.
+	FtSquelch FieldType = "Squelch"
+	// FtTalkPermitTone is the type of the Talk Permit Tone field of General Settings records.
+	FtTalkPermitTone FieldType = "TalkPermitTone"
+	// FtTextMessage is the type of the Message field of Text Message records.
+	FtTextMessage FieldType = "TextMessage"
+	// FtTot is the type of the TOT (S) field of Channel Information records.
+	FtTot FieldType = "Tot"
+	// FtTotRekeyDelay is the type of the TOT Rekey Delay (S) field of Channel Information records.
+	FtTotRekeyDelay FieldType = "TotRekeyDelay"
+	// FtTxDesignatedChannel is the type of the Tx Designated Channel field of Scan List records.
+	FtTxDesignatedChannel FieldType = "TxDesignatedChannel"
+	// FtTxFrequency is the type of the Tx Frequency (MHz) field of Channel Information records.
+	FtTxFrequency FieldType = "TxFrequency"
+	// FtTxPreambleDuration is the type of the Tx Preamble Duration (mS) field of General Settings records.
+	FtTxPreambleDuration FieldType = "TxPreambleDuration"
+	// FtTxRefFrequency is the type of the Tx Ref Frequency field of Channel Information records.
+	FtTxRefFrequency FieldType = "TxRefFrequency"
+	// FtTxSignallingSystem is the type of the Tx Signaling System field of Channel Information records.
+	FtTxSignallingSystem FieldType = "TxSignallingSystem"
+	// FtVox is the type of the VOX field of Channel Information records.
+	FtVox FieldType = "Vox"
+	// FtVoxSensitivity is the type of the VOX Sensitivity field of General Settings records.
+	FtVoxSensitivity FieldType = "VoxSensitivity"
 )
 
 // The value types a field may contain
@@ -199,6 +304,26 @@ func newValue(vt ValueType) value {
 	return nil
 }
 
+// Codeplug types and their file sizes and frequency bands.
+var cpInfos = map[CodeplugType]cpInfo{
+	CtMd380: cpInfo{
+		rdtSize: 262709,
+		binSize: 262144,
+	},
+	CtMd390: cpInfo{
+		rdtSize: 262709,
+		binSize: 262144,
+	},
+	CtUv380: cpInfo{
+		rdtSize: 852533,
+		binSize: 851968,
+		bands: []FrequencyRange{
+			{136, 174},
+			{400, 480},
+		},
+	},
+}
+
 // Codeplug types and their records, fields, with offsets, sizes, etc.
 var cpTypes = map[CodeplugType][]rInfo{
 	CtMd380: []rInfo{
@@ -208,6 +333,54 @@ var cpTypes = map[CodeplugType][]rInfo{
 			max:      1,
 			offset:   0,
 			size:     549,
+			unknownMask: []byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
 			fInfos: []fInfo{
 				fInfo{
 					fType:     FtLowFrequency,
@@ -233,6 +406,20 @@ var cpTypes = map[CodeplugType][]rInfo{
 			max:      1,
 			offset:   8805,
 			size:     144,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xeb, 0x08, 0xef, 0xff, 0x00, 0x00, 0x00, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
 			fInfos: []fInfo{
 				fInfo{
 					fType:     FtIntroScreenLine1,
@@ -251,32 +438,35 @@ var cpTypes = map[CodeplugType][]rInfo{
 					valueType: VtIntroLine,
 				},
 				fInfo{
-					fType:     FtMonitorType,
-					typeName:  "Monitor Type",
-					max:       1,
-					bitOffset: 515,
-					bitSize:   1,
-					valueType: VtIStrings,
+					fType:        FtMonitorType,
+					typeName:     "Monitor Type",
+					max:          1,
+					bitOffset:    515,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Open Squelch",
 					strings: &[]string{
 						"Silent",
 						"Open Squelch",
 					},
 				},
 				fInfo{
-					fType:     FtDisableAllLeds,
-					typeName:  "Disable All LEDS",
-					max:       1,
-					bitOffset: 517,
-					bitSize:   1,
-					valueType: VtOnOff,
+					fType:        FtDisableAllLeds,
+					typeName:     "Disable All LEDS",
+					max:          1,
+					bitOffset:    517,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
 				},
 				fInfo{
-					fType:     FtTalkPermitTone,
-					typeName:  "Talk Permit Tone",
-					max:       1,
-					bitOffset: 520,
-					bitSize:   2,
-					valueType: VtIStrings,
+					fType:        FtTalkPermitTone,
+					typeName:     "Talk Permit Tone",
+					max:          1,
+					bitOffset:    520,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "None",
 					strings: &[]string{
 						"None",
 						"Digital",
@@ -291,80 +481,90 @@ var cpTypes = map[CodeplugType][]rInfo{
 					bitOffset:     522,
 					bitSize:       1,
 					valueType:     VtOnOff,
+					defaultValue:  "Off",
 					enablingValue: "On",
 				},
 				fInfo{
-					fType:     FtChFreeIndicationTone,
-					typeName:  "Channel Free Indication Tone",
-					max:       1,
-					bitOffset: 523,
-					bitSize:   1,
-					valueType: VtOnOff,
+					fType:        FtChFreeIndicationTone,
+					typeName:     "Channel Free Indication Tone",
+					max:          1,
+					bitOffset:    523,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
 				},
 				fInfo{
-					fType:     FtDisableAllTones,
-					typeName:  "Disable All Tones",
-					max:       1,
-					bitOffset: 525,
-					bitSize:   1,
-					valueType: VtOnOff,
+					fType:        FtDisableAllTones,
+					typeName:     "Disable All Tones",
+					max:          1,
+					bitOffset:    525,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
 				},
 				fInfo{
-					fType:     FtSaveModeReceive,
-					typeName:  "Save Mode Receive",
-					max:       1,
-					bitOffset: 526,
-					bitSize:   1,
-					valueType: VtOffOn,
+					fType:        FtSaveModeReceive,
+					typeName:     "Save Mode Receive",
+					max:          1,
+					bitOffset:    526,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
 				},
 				fInfo{
-					fType:     FtSavePreamble,
-					typeName:  "Save Preamble",
-					max:       1,
-					bitOffset: 527,
-					bitSize:   1,
-					valueType: VtOffOn,
+					fType:        FtSavePreamble,
+					typeName:     "Save Preamble",
+					max:          1,
+					bitOffset:    527,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
 				},
 				fInfo{
-					fType:     FtIntroScreen,
-					typeName:  "Intro Screen",
-					max:       1,
-					bitOffset: 531,
-					bitSize:   1,
-					valueType: VtIStrings,
+					fType:        FtIntroScreen,
+					typeName:     "Intro Screen",
+					max:          1,
+					bitOffset:    531,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Character String",
 					strings: &[]string{
 						"Character String",
 						"Picture",
 					},
 				},
 				fInfo{
-					fType:     FtRadioID,
-					typeName:  "Radio ID",
-					max:       1,
-					bitOffset: 544,
-					bitSize:   24,
-					valueType: VtCallID,
+					fType:        FtRadioID,
+					typeName:     "Radio ID",
+					max:          1,
+					bitOffset:    544,
+					bitSize:      24,
+					valueType:    VtCallID,
+					defaultValue: "1",
 				},
 				fInfo{
-					fType:     FtTxPreambleDuration,
-					typeName:  "Tx Preamble Duration (mS)",
-					max:       1,
-					bitOffset: 576,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtTxPreambleDuration,
+					typeName:     "Tx Preamble Duration (mS)",
+					max:          1,
+					bitOffset:    576,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "600",
 					span: &Span{
-						min:   0,
-						max:   144,
-						scale: 60,
+						min:      0,
+						max:      144,
+						scale:    60,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtGroupCallHangTime,
-					typeName:  "Group Call Hang Time (mS)",
-					max:       1,
-					bitOffset: 584,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtGroupCallHangTime,
+					typeName:     "Group Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    584,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3000",
 					span: &Span{
 						min:      0,
 						max:      70,
@@ -373,12 +573,13 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtPrivateCallHangTime,
-					typeName:  "Private Call Hang Time (mS)",
-					max:       1,
-					bitOffset: 592,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtPrivateCallHangTime,
+					typeName:     "Private Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    592,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "4000",
 					span: &Span{
 						min:      0,
 						max:      70,
@@ -387,37 +588,43 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtVoxSensitivity,
-					typeName:  "VOX Sensitivity",
-					max:       1,
-					bitOffset: 600,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtVoxSensitivity,
+					typeName:     "VOX Sensitivity",
+					max:          1,
+					bitOffset:    600,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3",
 					span: &Span{
-						min: 1,
-						max: 10,
+						min:      1,
+						max:      10,
+						scale:    1,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtRxLowBatteryInterval,
-					typeName:  "Rx Low Battery Interval (S)",
-					max:       1,
-					bitOffset: 624,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtRxLowBatteryInterval,
+					typeName:     "Rx Low Battery Interval (S)",
+					max:          1,
+					bitOffset:    624,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "120",
 					span: &Span{
-						min:   0,
-						max:   127,
-						scale: 5,
+						min:      0,
+						max:      127,
+						scale:    5,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtCallAlertToneDuration,
-					typeName:  "Call Alert Tone Duration (S)",
-					max:       1,
-					bitOffset: 632,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtCallAlertToneDuration,
+					typeName:     "Call Alert Tone Duration (S)",
+					max:          1,
+					bitOffset:    632,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "Continue",
 					span: &Span{
 						min:       0,
 						max:       240,
@@ -427,36 +634,43 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtLoneWorkerResponseTime,
-					typeName:  "Lone Worker Response Time (min)",
-					max:       1,
-					bitOffset: 640,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtLoneWorkerResponseTime,
+					typeName:     "Lone Worker Response Time (min)",
+					max:          1,
+					bitOffset:    640,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1",
 					span: &Span{
-						min: 1,
-						max: 255,
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtLoneWorkerReminderTime,
-					typeName:  "Lone Worker Reminder Time (S)",
-					max:       1,
-					bitOffset: 648,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtLoneWorkerReminderTime,
+					typeName:     "Lone Worker Reminder Time (S)",
+					max:          1,
+					bitOffset:    648,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "10",
 					span: &Span{
-						min: 1,
-						max: 255,
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtScanDigitalHangTime,
-					typeName:  "Scan Digital Hang Time (mS)",
-					max:       1,
-					bitOffset: 664,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtScanDigitalHangTime,
+					typeName:     "Scan Digital Hang Time (mS)",
+					max:          1,
+					bitOffset:    664,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
 					span: &Span{
 						min:      5,
 						max:      100,
@@ -465,12 +679,13 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtScanAnalogHangTime,
-					typeName:  "Scan Analog Hang Time (mS)",
-					max:       1,
-					bitOffset: 672,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtScanAnalogHangTime,
+					typeName:     "Scan Analog Hang Time (mS)",
+					max:          1,
+					bitOffset:    672,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
 					span: &Span{
 						min:      5,
 						max:      100,
@@ -479,12 +694,13 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtSetKeypadLockTime,
-					typeName:  "Set Keypad Lock Time (S)",
-					max:       1,
-					bitOffset: 688,
-					bitSize:   8,
-					valueType: VtIndexedStrings,
+					fType:        FtSetKeypadLockTime,
+					typeName:     "Set Keypad Lock Time (S)",
+					max:          1,
+					bitOffset:    688,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Manual",
 					indexedStrings: &[]IndexedString{
 						IndexedString{255, "Manual"},
 						IndexedString{5, "5"},
@@ -493,12 +709,13 @@ var cpTypes = map[CodeplugType][]rInfo{
 					},
 				},
 				fInfo{
-					fType:     FtMode,
-					typeName:  "Mode",
-					max:       1,
-					bitOffset: 696,
-					bitSize:   8,
-					valueType: VtIndexedStrings,
+					fType:        FtMode,
+					typeName:     "Mode",
+					max:          1,
+					bitOffset:    696,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Channel",
 					indexedStrings: &[]IndexedString{
 						IndexedString{0, "Memory"},
 						IndexedString{255, "Channel"},
@@ -565,11 +782,17 @@ var cpTypes = map[CodeplugType][]rInfo{
 			},
 		},
 		rInfo{
-			rType:    RtDigitalContacts,
-			typeName: "Digital Contacts",
-			max:      1000,
-			offset:   24997,
-			size:     36,
+			rType:         RtDigitalContacts,
+			typeName:      "Digital Contacts",
+			max:           1000,
+			offset:        24997,
+			size:          36,
+			nameFieldType: FtContactName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0xdc, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
 			delDescs: []delDesc{
 				delDesc{
 					offset: 0,
@@ -633,11 +856,12 @@ var cpTypes = map[CodeplugType][]rInfo{
 			},
 		},
 		rInfo{
-			rType:    RtGroupList,
-			typeName: "Digital Rx Group List",
-			max:      250,
-			offset:   60997,
-			size:     96,
+			rType:         RtGroupList,
+			typeName:      "Digital Rx Group List",
+			max:           250,
+			offset:        60997,
+			size:          96,
+			nameFieldType: FtName,
 			delDescs: []delDesc{
 				delDesc{
 					offset: 0,
@@ -666,11 +890,12 @@ var cpTypes = map[CodeplugType][]rInfo{
 			},
 		},
 		rInfo{
-			rType:    RtZoneInformation,
-			typeName: "Zone Information",
-			max:      250,
-			offset:   84997,
-			size:     64,
+			rType:         RtZoneInformation,
+			typeName:      "Zone Information",
+			max:           250,
+			offset:        84997,
+			size:          64,
+			nameFieldType: FtName,
 			delDescs: []delDesc{
 				delDesc{
 					offset: 0,
@@ -699,11 +924,23 @@ var cpTypes = map[CodeplugType][]rInfo{
 			},
 		},
 		rInfo{
-			rType:    RtScanList,
-			typeName: "Scan List",
-			max:      250,
-			offset:   100997,
-			size:     104,
+			rType:         RtScanList,
+			typeName:      "Scan List",
+			max:           250,
+			offset:        100997,
+			size:          104,
+			nameFieldType: FtName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0xff, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
 			delDescs: []delDesc{
 				delDesc{
 					offset: 0,
@@ -763,29 +1000,33 @@ var cpTypes = map[CodeplugType][]rInfo{
 					listRecordType: RtChannelInformation,
 				},
 				fInfo{
-					fType:     FtSignallingHoldTime,
-					typeName:  "Signalling Hold Time (mS)",
-					max:       1,
-					bitOffset: 312,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtSignallingHoldTime,
+					typeName:     "Signalling Hold Time (mS)",
+					max:          1,
+					bitOffset:    312,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "500",
 					span: &Span{
-						min:   2,
-						max:   255,
-						scale: 25,
+						min:      2,
+						max:      255,
+						scale:    25,
+						interval: 1,
 					},
 				},
 				fInfo{
-					fType:     FtPrioritySampleTime,
-					typeName:  "Priority Sample Time (mS)",
-					max:       1,
-					bitOffset: 320,
-					bitSize:   8,
-					valueType: VtSpan,
+					fType:        FtPrioritySampleTime,
+					typeName:     "Priority Sample Time (mS)",
+					max:          1,
+					bitOffset:    320,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "2000",
 					span: &Span{
-						min:   3,
-						max:   31,
-						scale: 250,
+						min:      3,
+						max:      31,
+						scale:    250,
+						interval: 1,
 					},
 				},
 				fInfo{
@@ -800,11 +1041,20 @@ var cpTypes = map[CodeplugType][]rInfo{
 			},
 		},
 		rInfo{
-			rType:    RtChannelInformation,
-			typeName: "Channel Information",
-			max:      1000,
-			offset:   127013,
-			size:     64,
+			rType:         RtChannelInformation,
+			typeName:      "Channel Information",
+			max:           1000,
+			offset:        127013,
+			size:          64,
+			nameFieldType: FtChannelName,
+			unknownMask: []byte{
+				0x44, 0x00, 0x00, 0x34, 0x00, 0xff, 0x00, 0x00, 0xc0, 0x00, 0xff, 0x00,
+				0x00, 0xff, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xf8, 0xf8, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
 			delDescs: []delDesc{
 				delDesc{
 					offset: 16,
@@ -875,8 +1125,10 @@ var cpTypes = map[CodeplugType][]rInfo{
 					bitSize:   4,
 					valueType: VtSpan,
 					span: &Span{
-						min: 0,
-						max: 15,
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
 					},
 					enabler: FtChannelMode,
 				},
@@ -954,8 +1206,10 @@ var cpTypes = map[CodeplugType][]rInfo{
 					valueType:    VtPrivacyNumber,
 					defaultValue: "0",
 					span: &Span{
-						min: 0,
-						max: 15,
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
 					},
 					disabler: FtPrivacy,
 				},
@@ -1105,8 +1359,2521 @@ var cpTypes = map[CodeplugType][]rInfo{
 					bitSize:   8,
 					valueType: VtSpan,
 					span: &Span{
-						min: 0,
-						max: 255,
+						min:      0,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:     FtScanList,
+					typeName:  "Scan List",
+					max:       1,
+					bitOffset: 88,
+					bitSize:   8,
+					valueType: VtListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtScanList,
+				},
+				fInfo{
+					fType:        FtGroupList,
+					typeName:     "Group List",
+					max:          1,
+					bitOffset:    96,
+					bitSize:      8,
+					valueType:    VtListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtGroupList,
+					enabler:        FtChannelMode,
+				},
+				fInfo{
+					fType:     FtDecode1,
+					typeName:  "Decode 1",
+					max:       1,
+					bitOffset: 112,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode2,
+					typeName:  "Decode 2",
+					max:       1,
+					bitOffset: 113,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode3,
+					typeName:  "Decode 3",
+					max:       1,
+					bitOffset: 114,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode4,
+					typeName:  "Decode 4",
+					max:       1,
+					bitOffset: 115,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode5,
+					typeName:  "Decode 5",
+					max:       1,
+					bitOffset: 116,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode6,
+					typeName:  "Decode 6",
+					max:       1,
+					bitOffset: 117,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode7,
+					typeName:  "Decode 7",
+					max:       1,
+					bitOffset: 118,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode8,
+					typeName:  "Decode 8",
+					max:       1,
+					bitOffset: 119,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtRxFrequency,
+					typeName:  "Rx Frequency (MHz)",
+					max:       1,
+					bitOffset: 128,
+					bitSize:   32,
+					valueType: VtFrequency,
+				},
+				fInfo{
+					fType:     FtTxFrequency,
+					typeName:  "Tx Frequency (MHz)",
+					max:       1,
+					bitOffset: 160,
+					bitSize:   32,
+					valueType: VtFrequency,
+				},
+				fInfo{
+					fType:        FtCtcssDecode,
+					typeName:     "CTCSS/DCS Decode",
+					max:          1,
+					bitOffset:    192,
+					bitSize:      16,
+					valueType:    VtCtcssDcs,
+					defaultValue: "None",
+					disabler:     FtChannelMode,
+				},
+				fInfo{
+					fType:         FtCtcssEncode,
+					typeName:      "CTCSS/DCS Encode",
+					max:           1,
+					bitOffset:     208,
+					bitSize:       16,
+					valueType:     VtCtcssDcs,
+					defaultValue:  "None",
+					enablingValue: "None",
+					disabler:      FtChannelMode,
+				},
+				fInfo{
+					fType:        FtRxSignallingSystem,
+					typeName:     "Rx Signaling System",
+					max:          1,
+					bitOffset:    229,
+					bitSize:      3,
+					valueType:    VtIStrings,
+					defaultValue: "Off",
+					strings: &[]string{
+						"Off",
+						"DTMF-1",
+						"DTMF-2",
+						"DTMF-3",
+						"DTMF-4",
+					},
+					enablingValue: "Off",
+					disabler:      FtChannelMode,
+				},
+				fInfo{
+					fType:        FtTxSignallingSystem,
+					typeName:     "Tx Signaling System",
+					max:          1,
+					bitOffset:    237,
+					bitSize:      3,
+					valueType:    VtIStrings,
+					defaultValue: "Off",
+					strings: &[]string{
+						"Off",
+						"DTMF-1",
+						"DTMF-2",
+						"DTMF-3",
+						"DTMF-4",
+					},
+					disabler: FtChannelMode,
+				},
+				fInfo{
+					fType:     FtChannelName,
+					typeName:  "Channel Name",
+					max:       1,
+					bitOffset: 256,
+					bitSize:   256,
+					valueType: VtName,
+				},
+			},
+		},
+	},
+	CtMd390: []rInfo{
+		rInfo{
+			rType:    RtRdtHeader,
+			typeName: "Rdt Header",
+			max:      1,
+			offset:   0,
+			size:     549,
+			unknownMask: []byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtLowFrequency,
+					typeName:  "Low Frequency",
+					max:       1,
+					bitOffset: 2504,
+					bitSize:   16,
+					valueType: VtRhFrequency,
+				},
+				fInfo{
+					fType:     FtHighFrequency,
+					typeName:  "High Frequency",
+					max:       1,
+					bitOffset: 2520,
+					bitSize:   16,
+					valueType: VtRhFrequency,
+				},
+			},
+		},
+		rInfo{
+			rType:    RtGeneralSettings,
+			typeName: "General Settings",
+			max:      1,
+			offset:   8805,
+			size:     144,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xeb, 0x08, 0xef, 0xff, 0x00, 0x00, 0x00, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtIntroScreenLine1,
+					typeName:  "Intro Screen Line 1",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   160,
+					valueType: VtIntroLine,
+				},
+				fInfo{
+					fType:     FtIntroScreenLine2,
+					typeName:  "Intro Screen Line 2",
+					max:       1,
+					bitOffset: 160,
+					bitSize:   160,
+					valueType: VtIntroLine,
+				},
+				fInfo{
+					fType:        FtMonitorType,
+					typeName:     "Monitor Type",
+					max:          1,
+					bitOffset:    515,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Open Squelch",
+					strings: &[]string{
+						"Silent",
+						"Open Squelch",
+					},
+				},
+				fInfo{
+					fType:        FtDisableAllLeds,
+					typeName:     "Disable All LEDS",
+					max:          1,
+					bitOffset:    517,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtTalkPermitTone,
+					typeName:     "Talk Permit Tone",
+					max:          1,
+					bitOffset:    520,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "None",
+					strings: &[]string{
+						"None",
+						"Digital",
+						"Analog",
+						"Digital and Analog",
+					},
+				},
+				fInfo{
+					fType:         FtPwAndLockEnable,
+					typeName:      "Password And Lock Enable",
+					max:           1,
+					bitOffset:     522,
+					bitSize:       1,
+					valueType:     VtOnOff,
+					defaultValue:  "Off",
+					enablingValue: "On",
+				},
+				fInfo{
+					fType:        FtChFreeIndicationTone,
+					typeName:     "Channel Free Indication Tone",
+					max:          1,
+					bitOffset:    523,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtDisableAllTones,
+					typeName:     "Disable All Tones",
+					max:          1,
+					bitOffset:    525,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtSaveModeReceive,
+					typeName:     "Save Mode Receive",
+					max:          1,
+					bitOffset:    526,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
+				},
+				fInfo{
+					fType:        FtSavePreamble,
+					typeName:     "Save Preamble",
+					max:          1,
+					bitOffset:    527,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
+				},
+				fInfo{
+					fType:        FtIntroScreen,
+					typeName:     "Intro Screen",
+					max:          1,
+					bitOffset:    531,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Character String",
+					strings: &[]string{
+						"Character String",
+						"Picture",
+					},
+				},
+				fInfo{
+					fType:        FtRadioID,
+					typeName:     "Radio ID",
+					max:          1,
+					bitOffset:    544,
+					bitSize:      24,
+					valueType:    VtCallID,
+					defaultValue: "1",
+				},
+				fInfo{
+					fType:        FtTxPreambleDuration,
+					typeName:     "Tx Preamble Duration (mS)",
+					max:          1,
+					bitOffset:    576,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "600",
+					span: &Span{
+						min:      0,
+						max:      144,
+						scale:    60,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtGroupCallHangTime,
+					typeName:     "Group Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    584,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3000",
+					span: &Span{
+						min:      0,
+						max:      70,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtPrivateCallHangTime,
+					typeName:     "Private Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    592,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "4000",
+					span: &Span{
+						min:      0,
+						max:      70,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtVoxSensitivity,
+					typeName:     "VOX Sensitivity",
+					max:          1,
+					bitOffset:    600,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3",
+					span: &Span{
+						min:      1,
+						max:      10,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtRxLowBatteryInterval,
+					typeName:     "Rx Low Battery Interval (S)",
+					max:          1,
+					bitOffset:    624,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "120",
+					span: &Span{
+						min:      0,
+						max:      127,
+						scale:    5,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtCallAlertToneDuration,
+					typeName:     "Call Alert Tone Duration (S)",
+					max:          1,
+					bitOffset:    632,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "Continue",
+					span: &Span{
+						min:       0,
+						max:       240,
+						scale:     5,
+						interval:  1,
+						minString: "Continue",
+					},
+				},
+				fInfo{
+					fType:        FtLoneWorkerResponseTime,
+					typeName:     "Lone Worker Response Time (min)",
+					max:          1,
+					bitOffset:    640,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1",
+					span: &Span{
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtLoneWorkerReminderTime,
+					typeName:     "Lone Worker Reminder Time (S)",
+					max:          1,
+					bitOffset:    648,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "10",
+					span: &Span{
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtScanDigitalHangTime,
+					typeName:     "Scan Digital Hang Time (mS)",
+					max:          1,
+					bitOffset:    664,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
+					span: &Span{
+						min:      5,
+						max:      100,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtScanAnalogHangTime,
+					typeName:     "Scan Analog Hang Time (mS)",
+					max:          1,
+					bitOffset:    672,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
+					span: &Span{
+						min:      5,
+						max:      100,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtSetKeypadLockTime,
+					typeName:     "Set Keypad Lock Time (S)",
+					max:          1,
+					bitOffset:    688,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Manual",
+					indexedStrings: &[]IndexedString{
+						IndexedString{255, "Manual"},
+						IndexedString{5, "5"},
+						IndexedString{10, "10"},
+						IndexedString{15, "15"},
+					},
+				},
+				fInfo{
+					fType:        FtMode,
+					typeName:     "Mode",
+					max:          1,
+					bitOffset:    696,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Channel",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Memory"},
+						IndexedString{255, "Channel"},
+					},
+				},
+				fInfo{
+					fType:        FtPowerOnPassword,
+					typeName:     "Power On Password",
+					max:          1,
+					bitOffset:    704,
+					bitSize:      32,
+					valueType:    VtRadioPassword,
+					defaultValue: "00000000",
+					enabler:      FtPwAndLockEnable,
+				},
+				fInfo{
+					fType:     FtRadioProgPw,
+					typeName:  "Radio Programming Password",
+					max:       1,
+					bitOffset: 736,
+					bitSize:   32,
+					valueType: VtRadioPassword,
+				},
+				fInfo{
+					fType:     FtPcProgPw,
+					typeName:  "PC Programming Password",
+					max:       1,
+					bitOffset: 768,
+					bitSize:   64,
+					valueType: VtPcPassword,
+				},
+				fInfo{
+					fType:     FtRadioName,
+					typeName:  "Radio Name",
+					max:       1,
+					bitOffset: 896,
+					bitSize:   256,
+					valueType: VtRadioName,
+				},
+			},
+		},
+		rInfo{
+			rType:    RtTextMessage,
+			typeName: "Text Message",
+			max:      50,
+			offset:   9125,
+			size:     288,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   8,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtTextMessage,
+					typeName:  "Message",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   2304,
+					valueType: VtTextMessage,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtDigitalContacts,
+			typeName:      "Digital Contacts",
+			max:           1000,
+			offset:        24997,
+			size:          36,
+			nameFieldType: FtContactName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0xdc, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   3,
+					value:  255,
+				},
+				delDesc{
+					offset: 4,
+					size:   2,
+					value:  0,
+				},
+				delDesc{
+					offset: 4,
+					size:   16,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtCallID,
+					typeName:  "Call ID",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   24,
+					valueType: VtCallID,
+				},
+				fInfo{
+					fType:     FtCallReceiveTone,
+					typeName:  "Call Receive Tone",
+					max:       1,
+					bitOffset: 26,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"No",
+						"Yes",
+					},
+				},
+				fInfo{
+					fType:     FtCallType,
+					typeName:  "Call Type",
+					max:       1,
+					bitOffset: 30,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"",
+						"Group",
+						"Private",
+						"All",
+					},
+				},
+				fInfo{
+					fType:     FtContactName,
+					typeName:  "Contact Name",
+					max:       1,
+					bitOffset: 32,
+					bitSize:   256,
+					valueType: VtName,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtGroupList,
+			typeName:      "Digital Rx Group List",
+			max:           250,
+			offset:        60997,
+			size:          96,
+			nameFieldType: FtName,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Group List Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:          FtContactMember,
+					typeName:       "Contact Member",
+					max:            32,
+					bitOffset:      256,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtDigitalContacts,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtZoneInformation,
+			typeName:      "Zone Information",
+			max:           250,
+			offset:        84997,
+			size:          64,
+			nameFieldType: FtName,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Zone Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:          FtChannelMember,
+					typeName:       "Channel Member",
+					max:            16,
+					bitOffset:      256,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtChannelInformation,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtScanList,
+			typeName:      "Scan List",
+			max:           250,
+			offset:        100997,
+			size:          104,
+			nameFieldType: FtName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0xff, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Scan List Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:     FtPriorityChannel1,
+					typeName:  "Priority Channel 1",
+					max:       1,
+					bitOffset: 256,
+					bitSize:   16,
+					valueType: VtMemberListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "None"},
+					},
+					listRecordType: RtChannelInformation,
+					enablingValue:  "None",
+				},
+				fInfo{
+					fType:        FtPriorityChannel2,
+					typeName:     "Priority Channel 2",
+					max:          1,
+					bitOffset:    272,
+					bitSize:      16,
+					valueType:    VtMemberListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "None"},
+					},
+					listRecordType: RtChannelInformation,
+					disabler:       FtPriorityChannel1,
+				},
+				fInfo{
+					fType:     FtTxDesignatedChannel,
+					typeName:  "Tx Designated Channel",
+					max:       1,
+					bitOffset: 288,
+					bitSize:   16,
+					valueType: VtListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "Last Active Channel"},
+					},
+					listRecordType: RtChannelInformation,
+				},
+				fInfo{
+					fType:        FtSignallingHoldTime,
+					typeName:     "Signalling Hold Time (mS)",
+					max:          1,
+					bitOffset:    312,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "500",
+					span: &Span{
+						min:      2,
+						max:      255,
+						scale:    25,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtPrioritySampleTime,
+					typeName:     "Priority Sample Time (mS)",
+					max:          1,
+					bitOffset:    320,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "2000",
+					span: &Span{
+						min:      3,
+						max:      31,
+						scale:    250,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:          FtChannelMember,
+					typeName:       "Channel Member",
+					max:            31,
+					bitOffset:      336,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtChannelInformation,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtChannelInformation,
+			typeName:      "Channel Information",
+			max:           1000,
+			offset:        127013,
+			size:          64,
+			nameFieldType: FtChannelName,
+			unknownMask: []byte{
+				0x44, 0x00, 0x00, 0x34, 0x00, 0xff, 0x00, 0x00, 0xc0, 0x00, 0xff, 0x00,
+				0x00, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xf8, 0xf8, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 16,
+					size:   1,
+					value:  255,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtLoneWorker,
+					typeName:  "Lone Worker",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtSquelch,
+					typeName:  "Squelch",
+					max:       1,
+					bitOffset: 2,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Tight",
+						"Normal",
+					},
+				},
+				fInfo{
+					fType:     FtAutoscan,
+					typeName:  "Autoscan",
+					max:       1,
+					bitOffset: 3,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtBandwidth,
+					typeName:  "Bandwidth",
+					max:       1,
+					bitOffset: 4,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"12.5",
+						"25",
+					},
+				},
+				fInfo{
+					fType:     FtChannelMode,
+					typeName:  "Channel Mode",
+					max:       1,
+					bitOffset: 6,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"",
+						"Analog",
+						"Digital",
+					},
+					enablingValue: "Digital",
+				},
+				fInfo{
+					fType:     FtColorCode,
+					typeName:  "Color Code",
+					max:       1,
+					bitOffset: 8,
+					bitSize:   4,
+					valueType: VtSpan,
+					span: &Span{
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
+					},
+					enabler: FtChannelMode,
+				},
+				fInfo{
+					fType:        FtRepeaterSlot,
+					typeName:     "Repeater Slot",
+					max:          1,
+					bitOffset:    12,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "1",
+					strings: &[]string{
+						"",
+						"1",
+						"2",
+					},
+					enabler: FtChannelMode,
+				},
+				fInfo{
+					fType:     FtRxOnly,
+					typeName:  "Rx Only",
+					max:       1,
+					bitOffset: 14,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtAllowTalkaround,
+					typeName:  "Allow Talkaround",
+					max:       1,
+					bitOffset: 15,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtDataCallConfirmed,
+					typeName:  "Data Call Confirmed",
+					max:       1,
+					bitOffset: 16,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtPrivateCallConfirmed,
+					typeName:  "Private Call Confimed",
+					max:       1,
+					bitOffset: 17,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:        FtPrivacy,
+					typeName:     "Privacy",
+					max:          1,
+					bitOffset:    18,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "None",
+					strings: &[]string{
+						"None",
+						"Basic",
+						"Enhanced",
+					},
+					enablingValue: "None",
+					enabler:       FtChannelMode,
+				},
+				fInfo{
+					fType:        FtPrivacyNumber,
+					typeName:     "Privacy Number",
+					max:          1,
+					bitOffset:    20,
+					bitSize:      4,
+					valueType:    VtPrivacyNumber,
+					defaultValue: "0",
+					span: &Span{
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
+					},
+					disabler: FtPrivacy,
+				},
+				fInfo{
+					fType:     FtDisplayPTTID,
+					typeName:  "Display PTT ID",
+					max:       1,
+					bitOffset: 24,
+					bitSize:   1,
+					valueType: VtOnOff,
+					disabler:  FtChannelMode,
+				},
+				fInfo{
+					fType:     FtCompressedUdpDataHeader,
+					typeName:  "Compressed UDP Data Header",
+					max:       1,
+					bitOffset: 25,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtEmergencyAlarmAck,
+					typeName:  "Emergency Alarm Ack",
+					max:       1,
+					bitOffset: 28,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtRxRefFrequency,
+					typeName:  "Rx Ref Frequency",
+					max:       1,
+					bitOffset: 30,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"Medium",
+						"High",
+					},
+				},
+				fInfo{
+					fType:     FtAdmitCriteria,
+					typeName:  "Admit Criteria",
+					max:       1,
+					bitOffset: 32,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Always",
+						"Channel free",
+						"CTCSS/DCS",
+						"Color code",
+					},
+				},
+				fInfo{
+					fType:     FtPower,
+					typeName:  "Power",
+					max:       1,
+					bitOffset: 34,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"High",
+					},
+				},
+				fInfo{
+					fType:     FtVox,
+					typeName:  "VOX",
+					max:       1,
+					bitOffset: 35,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtQtReverse,
+					typeName:  "QT Reverse",
+					max:       1,
+					bitOffset: 36,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"180",
+						"120",
+					},
+					disabler: FtCtcssEncode,
+				},
+				fInfo{
+					fType:     FtReverseBurst,
+					typeName:  "Reverse Burst/Turn Off Code",
+					max:       1,
+					bitOffset: 37,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtCtcssEncode,
+				},
+				fInfo{
+					fType:     FtTxRefFrequency,
+					typeName:  "Tx Ref Frequency",
+					max:       1,
+					bitOffset: 38,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"Medium",
+						"High",
+					},
+				},
+				fInfo{
+					fType:        FtContactName,
+					typeName:     "Contact Name",
+					max:          1,
+					bitOffset:    48,
+					bitSize:      16,
+					valueType:    VtListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtDigitalContacts,
+					enabler:        FtChannelMode,
+				},
+				fInfo{
+					fType:     FtTot,
+					typeName:  "TOT (S)",
+					max:       1,
+					bitOffset: 66,
+					bitSize:   6,
+					valueType: VtSpan,
+					span: &Span{
+						min:       0,
+						max:       63,
+						scale:     15,
+						interval:  1,
+						minString: "Infinite",
+					},
+				},
+				fInfo{
+					fType:     FtTotRekeyDelay,
+					typeName:  "TOT Rekey Delay (S)",
+					max:       1,
+					bitOffset: 72,
+					bitSize:   8,
+					valueType: VtSpan,
+					span: &Span{
+						min:      0,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:     FtScanList,
+					typeName:  "Scan List",
+					max:       1,
+					bitOffset: 88,
+					bitSize:   8,
+					valueType: VtListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtScanList,
+				},
+				fInfo{
+					fType:        FtGroupList,
+					typeName:     "Group List",
+					max:          1,
+					bitOffset:    96,
+					bitSize:      8,
+					valueType:    VtListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtGroupList,
+					enabler:        FtChannelMode,
+				},
+				fInfo{
+					fType:     FtDecode1,
+					typeName:  "Decode 1",
+					max:       1,
+					bitOffset: 112,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode2,
+					typeName:  "Decode 2",
+					max:       1,
+					bitOffset: 113,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode3,
+					typeName:  "Decode 3",
+					max:       1,
+					bitOffset: 114,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode4,
+					typeName:  "Decode 4",
+					max:       1,
+					bitOffset: 115,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode5,
+					typeName:  "Decode 5",
+					max:       1,
+					bitOffset: 116,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode6,
+					typeName:  "Decode 6",
+					max:       1,
+					bitOffset: 117,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode7,
+					typeName:  "Decode 7",
+					max:       1,
+					bitOffset: 118,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtDecode8,
+					typeName:  "Decode 8",
+					max:       1,
+					bitOffset: 119,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtRxSignallingSystem,
+				},
+				fInfo{
+					fType:     FtRxFrequency,
+					typeName:  "Rx Frequency (MHz)",
+					max:       1,
+					bitOffset: 128,
+					bitSize:   32,
+					valueType: VtFrequency,
+				},
+				fInfo{
+					fType:     FtTxFrequency,
+					typeName:  "Tx Frequency (MHz)",
+					max:       1,
+					bitOffset: 160,
+					bitSize:   32,
+					valueType: VtFrequency,
+				},
+				fInfo{
+					fType:        FtCtcssDecode,
+					typeName:     "CTCSS/DCS Decode",
+					max:          1,
+					bitOffset:    192,
+					bitSize:      16,
+					valueType:    VtCtcssDcs,
+					defaultValue: "None",
+					disabler:     FtChannelMode,
+				},
+				fInfo{
+					fType:         FtCtcssEncode,
+					typeName:      "CTCSS/DCS Encode",
+					max:           1,
+					bitOffset:     208,
+					bitSize:       16,
+					valueType:     VtCtcssDcs,
+					defaultValue:  "None",
+					enablingValue: "None",
+					disabler:      FtChannelMode,
+				},
+				fInfo{
+					fType:        FtRxSignallingSystem,
+					typeName:     "Rx Signaling System",
+					max:          1,
+					bitOffset:    229,
+					bitSize:      3,
+					valueType:    VtIStrings,
+					defaultValue: "Off",
+					strings: &[]string{
+						"Off",
+						"DTMF-1",
+						"DTMF-2",
+						"DTMF-3",
+						"DTMF-4",
+					},
+					enablingValue: "Off",
+					disabler:      FtChannelMode,
+				},
+				fInfo{
+					fType:        FtTxSignallingSystem,
+					typeName:     "Tx Signaling System",
+					max:          1,
+					bitOffset:    237,
+					bitSize:      3,
+					valueType:    VtIStrings,
+					defaultValue: "Off",
+					strings: &[]string{
+						"Off",
+						"DTMF-1",
+						"DTMF-2",
+						"DTMF-3",
+						"DTMF-4",
+					},
+					disabler: FtChannelMode,
+				},
+				fInfo{
+					fType:     FtChannelName,
+					typeName:  "Channel Name",
+					max:       1,
+					bitOffset: 256,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:        FtGpsSystem,
+					typeName:     "GPS System",
+					max:          1,
+					bitOffset:    104,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "None",
+					span: &Span{
+						min:       0,
+						max:       16,
+						scale:     1,
+						interval:  1,
+						minString: "None",
+					},
+				},
+			},
+		},
+		rInfo{
+			rType:    RtGpsSystem,
+			typeName: "GPS System",
+			max:      16,
+			offset:   257637,
+			size:     16,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0xff, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff,
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:        FtRevertChannel,
+					typeName:     "Revert Channel",
+					max:          1,
+					bitOffset:    0,
+					bitSize:      16,
+					valueType:    VtListIndex,
+					defaultValue: "Current Channel",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Current Channel"},
+					},
+					listRecordType: RtChannelInformation,
+				},
+				fInfo{
+					fType:        FtReportInterval,
+					typeName:     "Report Interval (S)",
+					max:          1,
+					bitOffset:    16,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "Off",
+					span: &Span{
+						min:       0,
+						max:       240,
+						scale:     30,
+						interval:  1,
+						minString: "Off",
+					},
+				},
+				fInfo{
+					fType:        FtDestinationContact,
+					typeName:     "Destination Contact",
+					max:          1,
+					bitOffset:    32,
+					bitSize:      16,
+					valueType:    VtListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtDigitalContacts,
+				},
+			},
+		},
+	},
+	CtUv380: []rInfo{
+		rInfo{
+			rType:    RtRdtHeader,
+			typeName: "Rdt Header",
+			max:      1,
+			offset:   0,
+			size:     549,
+			unknownMask: []byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtLowFrequency,
+					typeName:  "Low Frequency",
+					max:       1,
+					bitOffset: 2504,
+					bitSize:   16,
+					valueType: VtRhFrequency,
+				},
+				fInfo{
+					fType:     FtHighFrequency,
+					typeName:  "High Frequency",
+					max:       1,
+					bitOffset: 2520,
+					bitSize:   16,
+					valueType: VtRhFrequency,
+				},
+			},
+		},
+		rInfo{
+			rType:    RtGeneralSettings,
+			typeName: "General Settings",
+			max:      1,
+			offset:   8805,
+			size:     144,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xeb, 0x08, 0xef, 0xff, 0x00, 0x00, 0x00, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0xff, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtIntroScreenLine1,
+					typeName:  "Intro Screen Line 1",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   160,
+					valueType: VtIntroLine,
+				},
+				fInfo{
+					fType:     FtIntroScreenLine2,
+					typeName:  "Intro Screen Line 2",
+					max:       1,
+					bitOffset: 160,
+					bitSize:   160,
+					valueType: VtIntroLine,
+				},
+				fInfo{
+					fType:        FtMonitorType,
+					typeName:     "Monitor Type",
+					max:          1,
+					bitOffset:    515,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Open Squelch",
+					strings: &[]string{
+						"Silent",
+						"Open Squelch",
+					},
+				},
+				fInfo{
+					fType:        FtDisableAllLeds,
+					typeName:     "Disable All LEDS",
+					max:          1,
+					bitOffset:    517,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtTalkPermitTone,
+					typeName:     "Talk Permit Tone",
+					max:          1,
+					bitOffset:    520,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "None",
+					strings: &[]string{
+						"None",
+						"Digital",
+						"Analog",
+						"Digital and Analog",
+					},
+				},
+				fInfo{
+					fType:         FtPwAndLockEnable,
+					typeName:      "Password And Lock Enable",
+					max:           1,
+					bitOffset:     522,
+					bitSize:       1,
+					valueType:     VtOnOff,
+					defaultValue:  "Off",
+					enablingValue: "On",
+				},
+				fInfo{
+					fType:        FtChFreeIndicationTone,
+					typeName:     "Channel Free Indication Tone",
+					max:          1,
+					bitOffset:    523,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtDisableAllTones,
+					typeName:     "Disable All Tones",
+					max:          1,
+					bitOffset:    525,
+					bitSize:      1,
+					valueType:    VtOnOff,
+					defaultValue: "Off",
+				},
+				fInfo{
+					fType:        FtSaveModeReceive,
+					typeName:     "Save Mode Receive",
+					max:          1,
+					bitOffset:    526,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
+				},
+				fInfo{
+					fType:        FtSavePreamble,
+					typeName:     "Save Preamble",
+					max:          1,
+					bitOffset:    527,
+					bitSize:      1,
+					valueType:    VtOffOn,
+					defaultValue: "On",
+				},
+				fInfo{
+					fType:        FtIntroScreen,
+					typeName:     "Intro Screen",
+					max:          1,
+					bitOffset:    531,
+					bitSize:      1,
+					valueType:    VtIStrings,
+					defaultValue: "Character String",
+					strings: &[]string{
+						"Character String",
+						"Picture",
+					},
+				},
+				fInfo{
+					fType:        FtRadioID,
+					typeName:     "Radio ID",
+					max:          1,
+					bitOffset:    544,
+					bitSize:      24,
+					valueType:    VtCallID,
+					defaultValue: "1",
+				},
+				fInfo{
+					fType:        FtTxPreambleDuration,
+					typeName:     "Tx Preamble Duration (mS)",
+					max:          1,
+					bitOffset:    576,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "600",
+					span: &Span{
+						min:      0,
+						max:      144,
+						scale:    60,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtGroupCallHangTime,
+					typeName:     "Group Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    584,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3000",
+					span: &Span{
+						min:      0,
+						max:      70,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtPrivateCallHangTime,
+					typeName:     "Private Call Hang Time (mS)",
+					max:          1,
+					bitOffset:    592,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "4000",
+					span: &Span{
+						min:      0,
+						max:      70,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtVoxSensitivity,
+					typeName:     "VOX Sensitivity",
+					max:          1,
+					bitOffset:    600,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "3",
+					span: &Span{
+						min:      1,
+						max:      10,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtRxLowBatteryInterval,
+					typeName:     "Rx Low Battery Interval (S)",
+					max:          1,
+					bitOffset:    624,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "120",
+					span: &Span{
+						min:      0,
+						max:      127,
+						scale:    5,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtCallAlertToneDuration,
+					typeName:     "Call Alert Tone Duration (S)",
+					max:          1,
+					bitOffset:    632,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "Continue",
+					span: &Span{
+						min:       0,
+						max:       240,
+						scale:     5,
+						interval:  1,
+						minString: "Continue",
+					},
+				},
+				fInfo{
+					fType:        FtLoneWorkerResponseTime,
+					typeName:     "Lone Worker Response Time (min)",
+					max:          1,
+					bitOffset:    640,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1",
+					span: &Span{
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtLoneWorkerReminderTime,
+					typeName:     "Lone Worker Reminder Time (S)",
+					max:          1,
+					bitOffset:    648,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "10",
+					span: &Span{
+						min:      1,
+						max:      255,
+						scale:    1,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtScanDigitalHangTime,
+					typeName:     "Scan Digital Hang Time (mS)",
+					max:          1,
+					bitOffset:    664,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
+					span: &Span{
+						min:      5,
+						max:      100,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtScanAnalogHangTime,
+					typeName:     "Scan Analog Hang Time (mS)",
+					max:          1,
+					bitOffset:    672,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "1000",
+					span: &Span{
+						min:      5,
+						max:      100,
+						scale:    100,
+						interval: 5,
+					},
+				},
+				fInfo{
+					fType:        FtSetKeypadLockTime,
+					typeName:     "Set Keypad Lock Time (S)",
+					max:          1,
+					bitOffset:    688,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Manual",
+					indexedStrings: &[]IndexedString{
+						IndexedString{255, "Manual"},
+						IndexedString{5, "5"},
+						IndexedString{10, "10"},
+						IndexedString{15, "15"},
+					},
+				},
+				fInfo{
+					fType:        FtMode,
+					typeName:     "Mode",
+					max:          1,
+					bitOffset:    696,
+					bitSize:      8,
+					valueType:    VtIndexedStrings,
+					defaultValue: "Channel",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Memory"},
+						IndexedString{255, "Channel"},
+					},
+				},
+				fInfo{
+					fType:        FtPowerOnPassword,
+					typeName:     "Power On Password",
+					max:          1,
+					bitOffset:    704,
+					bitSize:      32,
+					valueType:    VtRadioPassword,
+					defaultValue: "00000000",
+					enabler:      FtPwAndLockEnable,
+				},
+				fInfo{
+					fType:     FtRadioProgPw,
+					typeName:  "Radio Programming Password",
+					max:       1,
+					bitOffset: 736,
+					bitSize:   32,
+					valueType: VtRadioPassword,
+				},
+				fInfo{
+					fType:     FtPcProgPw,
+					typeName:  "PC Programming Password",
+					max:       1,
+					bitOffset: 768,
+					bitSize:   64,
+					valueType: VtPcPassword,
+				},
+				fInfo{
+					fType:     FtRadioName,
+					typeName:  "Radio Name",
+					max:       1,
+					bitOffset: 896,
+					bitSize:   256,
+					valueType: VtRadioName,
+				},
+			},
+		},
+		rInfo{
+			rType:    RtTextMessage,
+			typeName: "Text Message",
+			max:      50,
+			offset:   9125,
+			size:     288,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   8,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtTextMessage,
+					typeName:  "Message",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   2304,
+					valueType: VtTextMessage,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtDigitalContacts,
+			typeName:      "Digital Contacts",
+			max:           10000,
+			offset:        459301,
+			size:          36,
+			nameFieldType: FtContactName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0xdc, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   3,
+					value:  255,
+				},
+				delDesc{
+					offset: 4,
+					size:   2,
+					value:  0,
+				},
+				delDesc{
+					offset: 4,
+					size:   16,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtCallID,
+					typeName:  "Call ID",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   24,
+					valueType: VtCallID,
+				},
+				fInfo{
+					fType:     FtCallReceiveTone,
+					typeName:  "Call Receive Tone",
+					max:       1,
+					bitOffset: 26,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"No",
+						"Yes",
+					},
+				},
+				fInfo{
+					fType:     FtCallType,
+					typeName:  "Call Type",
+					max:       1,
+					bitOffset: 30,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"",
+						"Group",
+						"Private",
+						"All",
+					},
+				},
+				fInfo{
+					fType:     FtContactName,
+					typeName:  "Contact Name",
+					max:       1,
+					bitOffset: 32,
+					bitSize:   256,
+					valueType: VtName,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtGroupList,
+			typeName:      "Digital Rx Group List",
+			max:           250,
+			offset:        60997,
+			size:          96,
+			nameFieldType: FtName,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Group List Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:          FtContactMember,
+					typeName:       "Contact Member",
+					max:            32,
+					bitOffset:      256,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtDigitalContacts,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtZoneInformation,
+			typeName:      "Zone Information",
+			max:           250,
+			offset:        84997,
+			size:          64,
+			nameFieldType: FtName,
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Zone Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:          FtChannelMember,
+					typeName:       "Channel Member",
+					max:            16,
+					bitOffset:      256,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtChannelInformation,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtScanList,
+			typeName:      "Scan List",
+			max:           250,
+			offset:        100997,
+			size:          104,
+			nameFieldType: FtName,
+			unknownMask: []byte{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0xff, 0x00, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 0,
+					size:   1,
+					value:  0,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtName,
+					typeName:  "Scan List Name",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   256,
+					valueType: VtName,
+				},
+				fInfo{
+					fType:     FtPriorityChannel1,
+					typeName:  "Priority Channel 1",
+					max:       1,
+					bitOffset: 256,
+					bitSize:   16,
+					valueType: VtMemberListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "None"},
+					},
+					listRecordType: RtChannelInformation,
+					enablingValue:  "None",
+				},
+				fInfo{
+					fType:        FtPriorityChannel2,
+					typeName:     "Priority Channel 2",
+					max:          1,
+					bitOffset:    272,
+					bitSize:      16,
+					valueType:    VtMemberListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "None"},
+					},
+					listRecordType: RtChannelInformation,
+					disabler:       FtPriorityChannel1,
+				},
+				fInfo{
+					fType:     FtTxDesignatedChannel,
+					typeName:  "Tx Designated Channel",
+					max:       1,
+					bitOffset: 288,
+					bitSize:   16,
+					valueType: VtListIndex,
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "Selected"},
+						IndexedString{65535, "Last Active Channel"},
+					},
+					listRecordType: RtChannelInformation,
+				},
+				fInfo{
+					fType:        FtSignallingHoldTime,
+					typeName:     "Signalling Hold Time (mS)",
+					max:          1,
+					bitOffset:    312,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "500",
+					span: &Span{
+						min:      2,
+						max:      255,
+						scale:    25,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:        FtPrioritySampleTime,
+					typeName:     "Priority Sample Time (mS)",
+					max:          1,
+					bitOffset:    320,
+					bitSize:      8,
+					valueType:    VtSpan,
+					defaultValue: "2000",
+					span: &Span{
+						min:      3,
+						max:      31,
+						scale:    250,
+						interval: 1,
+					},
+				},
+				fInfo{
+					fType:          FtChannelMember,
+					typeName:       "Channel Member",
+					max:            31,
+					bitOffset:      336,
+					bitSize:        16,
+					valueType:      VtListIndex,
+					listRecordType: RtChannelInformation,
+				},
+			},
+		},
+		rInfo{
+			rType:         RtChannelInformation,
+			typeName:      "Channel Information",
+			max:           3000,
+			offset:        262693,
+			size:          64,
+			nameFieldType: FtChannelName,
+			unknownMask: []byte{
+				0x44, 0x00, 0x00, 0x34, 0x00, 0xff, 0x00, 0x00, 0xc0, 0x00, 0xff, 0x00,
+				0x00, 0xff, 0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0xf8, 0xf8, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
+			delDescs: []delDesc{
+				delDesc{
+					offset: 16,
+					size:   1,
+					value:  255,
+				},
+			},
+			fInfos: []fInfo{
+				fInfo{
+					fType:     FtLoneWorker,
+					typeName:  "Lone Worker",
+					max:       1,
+					bitOffset: 0,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtSquelch,
+					typeName:  "Squelch",
+					max:       1,
+					bitOffset: 2,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Tight",
+						"Normal",
+					},
+				},
+				fInfo{
+					fType:     FtAutoscan,
+					typeName:  "Autoscan",
+					max:       1,
+					bitOffset: 3,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtBandwidth,
+					typeName:  "Bandwidth",
+					max:       1,
+					bitOffset: 4,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"12.5",
+						"25",
+					},
+				},
+				fInfo{
+					fType:     FtChannelMode,
+					typeName:  "Channel Mode",
+					max:       1,
+					bitOffset: 6,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"",
+						"Analog",
+						"Digital",
+					},
+					enablingValue: "Digital",
+				},
+				fInfo{
+					fType:     FtColorCode,
+					typeName:  "Color Code",
+					max:       1,
+					bitOffset: 8,
+					bitSize:   4,
+					valueType: VtSpan,
+					span: &Span{
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
+					},
+					enabler: FtChannelMode,
+				},
+				fInfo{
+					fType:        FtRepeaterSlot,
+					typeName:     "Repeater Slot",
+					max:          1,
+					bitOffset:    12,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "1",
+					strings: &[]string{
+						"",
+						"1",
+						"2",
+					},
+					enabler: FtChannelMode,
+				},
+				fInfo{
+					fType:     FtRxOnly,
+					typeName:  "Rx Only",
+					max:       1,
+					bitOffset: 14,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtAllowTalkaround,
+					typeName:  "Allow Talkaround",
+					max:       1,
+					bitOffset: 15,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtDataCallConfirmed,
+					typeName:  "Data Call Confirmed",
+					max:       1,
+					bitOffset: 16,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtPrivateCallConfirmed,
+					typeName:  "Private Call Confimed",
+					max:       1,
+					bitOffset: 17,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:        FtPrivacy,
+					typeName:     "Privacy",
+					max:          1,
+					bitOffset:    18,
+					bitSize:      2,
+					valueType:    VtIStrings,
+					defaultValue: "None",
+					strings: &[]string{
+						"None",
+						"Basic",
+						"Enhanced",
+					},
+					enablingValue: "None",
+					enabler:       FtChannelMode,
+				},
+				fInfo{
+					fType:        FtPrivacyNumber,
+					typeName:     "Privacy Number",
+					max:          1,
+					bitOffset:    20,
+					bitSize:      4,
+					valueType:    VtPrivacyNumber,
+					defaultValue: "0",
+					span: &Span{
+						min:      0,
+						max:      15,
+						scale:    1,
+						interval: 1,
+					},
+					disabler: FtPrivacy,
+				},
+				fInfo{
+					fType:     FtDisplayPTTID,
+					typeName:  "Display PTT ID",
+					max:       1,
+					bitOffset: 24,
+					bitSize:   1,
+					valueType: VtOnOff,
+					disabler:  FtChannelMode,
+				},
+				fInfo{
+					fType:     FtCompressedUdpDataHeader,
+					typeName:  "Compressed UDP Data Header",
+					max:       1,
+					bitOffset: 25,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtEmergencyAlarmAck,
+					typeName:  "Emergency Alarm Ack",
+					max:       1,
+					bitOffset: 28,
+					bitSize:   1,
+					valueType: VtOffOn,
+					enabler:   FtChannelMode,
+				},
+				fInfo{
+					fType:     FtRxRefFrequency,
+					typeName:  "Rx Ref Frequency",
+					max:       1,
+					bitOffset: 30,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"Medium",
+						"High",
+					},
+				},
+				fInfo{
+					fType:     FtAdmitCriteria,
+					typeName:  "Admit Criteria",
+					max:       1,
+					bitOffset: 32,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Always",
+						"Channel free",
+						"CTCSS/DCS",
+						"Color code",
+					},
+				},
+				fInfo{
+					fType:     FtPower,
+					typeName:  "Power",
+					max:       1,
+					bitOffset: 34,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"High",
+					},
+				},
+				fInfo{
+					fType:     FtVox,
+					typeName:  "VOX",
+					max:       1,
+					bitOffset: 35,
+					bitSize:   1,
+					valueType: VtOffOn,
+				},
+				fInfo{
+					fType:     FtQtReverse,
+					typeName:  "QT Reverse",
+					max:       1,
+					bitOffset: 36,
+					bitSize:   1,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"180",
+						"120",
+					},
+					disabler: FtCtcssEncode,
+				},
+				fInfo{
+					fType:     FtReverseBurst,
+					typeName:  "Reverse Burst/Turn Off Code",
+					max:       1,
+					bitOffset: 37,
+					bitSize:   1,
+					valueType: VtOffOn,
+					disabler:  FtCtcssEncode,
+				},
+				fInfo{
+					fType:     FtTxRefFrequency,
+					typeName:  "Tx Ref Frequency",
+					max:       1,
+					bitOffset: 38,
+					bitSize:   2,
+					valueType: VtIStrings,
+					strings: &[]string{
+						"Low",
+						"Medium",
+						"High",
+					},
+				},
+				fInfo{
+					fType:        FtContactName,
+					typeName:     "Contact Name",
+					max:          1,
+					bitOffset:    48,
+					bitSize:      16,
+					valueType:    VtListIndex,
+					defaultValue: "None",
+					indexedStrings: &[]IndexedString{
+						IndexedString{0, "None"},
+					},
+					listRecordType: RtDigitalContacts,
+					enabler:        FtChannelMode,
+				},
+				fInfo{
+					fType:     FtTot,
+					typeName:  "TOT (S)",
+					max:       1,
+					bitOffset: 66,
+					bitSize:   6,
+					valueType: VtSpan,
+					span: &Span{
+						min:       0,
+						max:       63,
+						scale:     15,
+						interval:  1,
+						minString: "Infinite",
+					},
+				},
+				fInfo{
+					fType:     FtTotRekeyDelay,
+					typeName:  "TOT Rekey Delay (S)",
+					max:       1,
+					bitOffset: 72,
+					bitSize:   8,
+					valueType: VtSpan,
+					span: &Span{
+						min:      0,
+						max:      255,
+						scale:    1,
+						interval: 1,
 					},
 				},
 				fInfo{
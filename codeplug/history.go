@@ -0,0 +1,506 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// HistorySuffix is appended to the name of a codeplug file to form the
+// name of the sidecar file holding its undo/redo history.
+const HistorySuffix = ".history"
+
+// An encodedHistory is the form in which a codeplug's undo/redo history
+// is written.  Hash is that of the codeplug state to which the history
+// applies.  Changes up to ChangeIndex have been made, and are encoded
+// as of the state after each was made.  The rest have been undone,
+// and are encoded as of the state before each would be redone.
+type encodedHistory struct {
+	Hash        string          `json:"hash"`
+	ChangeIndex int             `json:"changeIndex"`
+	Changes     []encodedChange `json:"changes"`
+}
+
+// An encodedChange is the form in which a change is written.  Records
+// and fields are referred to by their indexes.  A record or field that
+// isn't part of the codeplug in the state in which it is encoded, such
+// as a deleted record, is written out in full.  Offset is the rdt
+// offset of the bytes set by a BytesChange, which has no records.
+type encodedChange struct {
+	Type         ChangeType      `json:"type"`
+	Records      []encodedRecord `json:"records,omitempty"`
+	Fields       []encodedField  `json:"fields,omitempty"`
+	FieldType    FieldType       `json:"fieldType,omitempty"`
+	Strings      []string        `json:"strings,omitempty"`
+	AfterStrings []string        `json:"afterStrings,omitempty"`
+	Offset       int             `json:"offset,omitempty"`
+	Changes      []encodedChange `json:"changes,omitempty"`
+}
+
+// An encodedRecord refers to a record.  Bytes, in hex, holds the
+// contents of a record that isn't part of the codeplug.
+type encodedRecord struct {
+	Type  RecordType `json:"type"`
+	Index int        `json:"index"`
+	Bytes string     `json:"bytes,omitempty"`
+}
+
+// An encodedField refers to a field.  Value holds the value of a field
+// that isn't part of its record.
+type encodedField struct {
+	Record encodedRecord `json:"record"`
+	Type   FieldType     `json:"type"`
+	Index  int           `json:"index"`
+	Value  *string       `json:"value,omitempty"`
+}
+
+// SaveHistoryTo writes the codeplug's undo/redo history to the named
+// file.  See SaveHistory.
+func (cp *Codeplug) SaveHistoryTo(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = cp.SaveHistory(file)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// SaveHistory writes the codeplug's undo/redo history, along with the
+// hash of the codeplug's current state, to w.
+func (cp *Codeplug) SaveHistory(w io.Writer) error {
+	eh := encodedHistory{
+		Hash:        cp.historyHash(),
+		ChangeIndex: cp.changeIndex,
+		Changes:     cp.encodeChanges(),
+	}
+
+	bytes, err := json.MarshalIndent(eh, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+// LoadHistoryFrom replaces the codeplug's undo/redo history with that
+// read from the named file.  See LoadHistory.
+func (cp *Codeplug) LoadHistoryFrom(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return cp.LoadHistory(file)
+}
+
+// LoadHistory replaces the codeplug's undo/redo history with that read
+// from rdr.  An error is returned, and the history is unchanged, unless
+// the history was saved when the codeplug was in its current state.
+func (cp *Codeplug) LoadHistory(rdr io.Reader) error {
+	var eh encodedHistory
+	err := json.NewDecoder(rdr).Decode(&eh)
+	if err != nil {
+		return err
+	}
+
+	if eh.Hash != cp.historyHash() {
+		return fmt.Errorf("history is not that of the codeplug")
+	}
+
+	n := len(eh.Changes)
+	if eh.ChangeIndex < 0 || eh.ChangeIndex > n {
+		return fmt.Errorf("bad history change index: %d", eh.ChangeIndex)
+	}
+
+	changes, err := cp.decodeChanges(eh.Changes, eh.ChangeIndex)
+	if err != nil {
+		return err
+	}
+
+	cp.changeList = append([]*Change{&Change{}}, changes...)
+	cp.changeIndex = eh.ChangeIndex
+
+	return nil
+}
+
+// historyHash returns, in hex, the hash of the codeplug's current state.
+func (cp *Codeplug) historyHash() string {
+	hash := sha256.Sum256(cp.imageBytes())
+	return hex.EncodeToString(hash[:])
+}
+
+// encodeChanges returns the encoded changes of the codeplug's history.
+// To encode each change in the proper state, the changes are undone
+// and then redone, leaving the codeplug in its current state.
+func (cp *Codeplug) encodeChanges() []encodedChange {
+	changes := cp.changeList[1:]
+	k := cp.changeIndex
+	encoded := make([]encodedChange, len(changes))
+
+	for i := k - 1; i >= 0; i-- {
+		cp.undoChange(changes[i])
+	}
+	for i, change := range changes {
+		if i >= k {
+			encoded[i] = cp.encodeChange(change)
+		}
+		cp.redoChange(change)
+		if i < k {
+			encoded[i] = cp.encodeChange(change)
+		}
+	}
+	for i := len(changes) - 1; i >= k; i-- {
+		cp.undoChange(changes[i])
+	}
+
+	return encoded
+}
+
+// decodeChanges returns the changes of an encoded history, the first k
+// of which have been made.  Like encodeChanges, it undoes and redoes the
+// changes as they are decoded, leaving the codeplug in its current state.
+func (cp *Codeplug) decodeChanges(encoded []encodedChange, k int) ([]*Change, error) {
+	changes := make([]*Change, len(encoded))
+
+	var i int
+	var err error
+	for i = k - 1; i >= 0; i-- {
+		changes[i], err = cp.decodeChange(encoded[i])
+		if err == nil {
+			err = cp.checkChange(changes[i])
+		}
+		if err != nil {
+			break
+		}
+		cp.undoChange(changes[i])
+	}
+	for i++; i < k; i++ {
+		cp.redoChange(changes[i])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i = k; i < len(encoded); i++ {
+		changes[i], err = cp.decodeChange(encoded[i])
+		if err == nil {
+			err = cp.checkChange(changes[i])
+		}
+		if err != nil {
+			break
+		}
+		cp.redoChange(changes[i])
+	}
+	for i--; i >= k; i-- {
+		cp.undoChange(changes[i])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// encodeChange returns the encoded form of a change.
+func (cp *Codeplug) encodeChange(change *Change) encodedChange {
+	ec := encodedChange{
+		Type:         change.cType,
+		Strings:      change.strings,
+		AfterStrings: change.afterStrings,
+		Offset:       change.offset,
+	}
+
+	for _, r := range change.records {
+		ec.Records = append(ec.Records, cp.encodeRecord(r))
+	}
+
+	if change.cType == ListIndexChange {
+		// A ListIndexChange's fields are replaced whenever it is
+		// made, so only their type is of use.
+		ec.FieldType = change.FieldType()
+	} else {
+		for _, f := range change.fields {
+			ec.Fields = append(ec.Fields, cp.encodeField(f))
+		}
+	}
+
+	for _, subChange := range change.changes {
+		ec.Changes = append(ec.Changes, cp.encodeChange(subChange))
+	}
+
+	return ec
+}
+
+// encodeRecord returns the encoded form of a reference to a record.
+func (cp *Codeplug) encodeRecord(r *Record) encodedRecord {
+	er := encodedRecord{
+		Type:  r.rType,
+		Index: r.rIndex,
+	}
+	if !r.inCodeplug() {
+		er.Bytes = hex.EncodeToString(r.recordBytes())
+	}
+
+	return er
+}
+
+// encodeField returns the encoded form of a reference to a field.
+func (cp *Codeplug) encodeField(f *Field) encodedField {
+	ef := encodedField{
+		Record: cp.encodeRecord(f.record),
+		Type:   f.fType,
+		Index:  f.fIndex,
+	}
+	if !f.inRecord() {
+		value := f.String()
+		ef.Value = &value
+	}
+
+	return ef
+}
+
+// decodeChange returns the change of an encoded change.
+func (cp *Codeplug) decodeChange(ec encodedChange) (*Change, error) {
+	change := &Change{
+		cType:        ec.Type,
+		strings:      ec.Strings,
+		afterStrings: ec.AfterStrings,
+	}
+
+	switch ec.Type {
+	case FieldChange, MoveRecordsChange, InsertRecordsChange,
+		RemoveRecordsChange, MoveFieldsChange, InsertFieldsChange,
+		RemoveFieldsChange, ListIndexChange, BatchFieldChange:
+	case BytesChange:
+		return cp.decodeBytesChange(change, ec)
+	default:
+		return nil, fmt.Errorf("unknown change type: %s", ec.Type)
+	}
+
+	if len(ec.Records) == 0 {
+		return nil, fmt.Errorf("%s has no records", ec.Type)
+	}
+	for _, er := range ec.Records {
+		r, err := cp.decodeRecord(er)
+		if err != nil {
+			return nil, err
+		}
+		change.records = append(change.records, r)
+	}
+
+	if ec.Type == ListIndexChange {
+		r := change.records[0]
+		if (*r.fDesc)[ec.FieldType] == nil {
+			return nil, fmt.Errorf("%s has no %s field", r.rType, ec.FieldType)
+		}
+		change.fields = r.Fields(ec.FieldType)
+		if len(change.fields) == 0 {
+			change.fields = []*Field{r.NewField(ec.FieldType)}
+		}
+	}
+
+	for _, ef := range ec.Fields {
+		f, err := cp.decodeField(ef)
+		if err != nil {
+			return nil, err
+		}
+		change.fields = append(change.fields, f)
+	}
+
+	switch ec.Type {
+	case FieldChange, MoveFieldsChange, InsertFieldsChange, RemoveFieldsChange:
+		if len(change.fields) == 0 || len(change.strings) < len(change.fields) {
+			return nil, fmt.Errorf("%s has too few fields", ec.Type)
+		}
+	case BatchFieldChange:
+		if len(change.fields) == 0 || len(change.afterStrings) == 0 {
+			return nil, fmt.Errorf("%s has too few fields", ec.Type)
+		}
+		if len(change.afterStrings) > 1 && len(change.afterStrings) != len(change.fields) {
+			return nil, fmt.Errorf("%s has too few values", ec.Type)
+		}
+	}
+
+	for _, esc := range ec.Changes {
+		subChange, err := cp.decodeChange(esc)
+		if err != nil {
+			return nil, err
+		}
+		change.changes = append(change.changes, subChange)
+	}
+
+	return change, nil
+}
+
+// checkChange returns an error if a decoded change can't be undone or
+// redone, whichever is next, in the codeplug's current state.  The
+// values set by the change must be valid, and the records or fields
+// by which it positions others must exist.  The list index changes
+// made along with moving, inserting or removing records or fields
+// are checked only as they are made, which drops invalid values.
+func (cp *Codeplug) checkChange(change *Change) error {
+	switch change.cType {
+	case FieldChange:
+		f := change.Field()
+		return checkValue(f.record, f.fType, f.fIndex, change.strings[0])
+
+	case ListIndexChange:
+		r := change.Record()
+		for i, str := range change.strings {
+			err := checkValue(r, change.FieldType(), i, str)
+			if err != nil {
+				return err
+			}
+		}
+
+	case BatchFieldChange:
+		for _, subChange := range change.changes {
+			err := cp.checkChange(subChange)
+			if err != nil {
+				return err
+			}
+		}
+
+	case MoveRecordsChange, InsertRecordsChange, RemoveRecordsChange:
+		r := change.records[0]
+		names := recordNames(change.records)
+		names = append(names, recordNames(cp.rDesc[r.rType].records)...)
+		return checkPositions(change, len(change.records), names)
+
+	case MoveFieldsChange, InsertFieldsChange, RemoveFieldsChange:
+		f := change.fields[0]
+		names := fieldStrings(change.fields)
+		names = append(names, fieldStrings(f.record.Fields(f.fType))...)
+		return checkPositions(change, len(change.fields), names)
+	}
+
+	return nil
+}
+
+// checkValue returns an error if str is not a valid value for the
+// record's field of the given type and index.
+func checkValue(r *Record, fType FieldType, index int, str string) error {
+	_, err := r.NewFieldWithValue(fType, index, str)
+	if err != nil {
+		return fmt.Errorf("bad %s %s value %q: %s", r.rType, fType, str, err)
+	}
+
+	return nil
+}
+
+// checkPositions returns an error unless the change has the names of
+// the records or fields after which each of its n records or fields
+// is placed, and each is one of the given names.
+func checkPositions(change *Change, n int, names []string) error {
+	if len(change.strings) < n {
+		return fmt.Errorf("%s has too few positions", change.cType)
+	}
+
+	for _, str := range change.strings[:n] {
+		if str != "" && !stringInSlice(str, names) {
+			return fmt.Errorf("%s has bad position: %q", change.cType, str)
+		}
+	}
+
+	return nil
+}
+
+// decodeBytesChange completes the decoding of a BytesChange.
+func (cp *Codeplug) decodeBytesChange(change *Change, ec encodedChange) (*Change, error) {
+	if len(change.strings) == 0 || len(change.afterStrings) == 0 {
+		return nil, fmt.Errorf("%s has no bytes", ec.Type)
+	}
+
+	data, err := hex.DecodeString(change.strings[0])
+	after, afterErr := hex.DecodeString(change.afterStrings[0])
+	if err != nil || afterErr != nil || len(data) != len(after) {
+		return nil, fmt.Errorf("%s has bad bytes", ec.Type)
+	}
+	if err := cp.checkBytes(ec.Offset, data); err != nil {
+		return nil, err
+	}
+
+	change.codeplug = cp
+	change.offset = ec.Offset
+
+	return change, nil
+}
+
+// decodeRecord returns the record referred to by an encoded record.
+func (cp *Codeplug) decodeRecord(er encodedRecord) (*Record, error) {
+	rd := cp.rDesc[er.Type]
+	if rd == nil {
+		return nil, fmt.Errorf("unknown record type: %s", er.Type)
+	}
+
+	if er.Bytes == "" {
+		if er.Index < 0 || er.Index >= len(rd.records) {
+			return nil, fmt.Errorf("no %s record %d", er.Type, er.Index+1)
+		}
+		return rd.records[er.Index], nil
+	}
+
+	rBytes, err := hex.DecodeString(er.Bytes)
+	if err != nil || len(rBytes) != rd.size {
+		return nil, fmt.Errorf("bad %s record contents", er.Type)
+	}
+
+	return cp.bytesToRecord(er.Type, er.Index, rBytes), nil
+}
+
+// decodeField returns the field referred to by an encoded field.
+func (cp *Codeplug) decodeField(ef encodedField) (*Field, error) {
+	r, err := cp.decodeRecord(ef.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := (*r.fDesc)[ef.Type]
+	if fd == nil {
+		return nil, fmt.Errorf("%s has no %s field", r.rType, ef.Type)
+	}
+
+	if ef.Value != nil {
+		return r.NewFieldWithValue(ef.Type, ef.Index, *ef.Value)
+	}
+
+	if ef.Index < 0 || ef.Index >= len(fd.fields) {
+		return nil, fmt.Errorf("%s has no %s field %d", r.rType, ef.Type, ef.Index+1)
+	}
+
+	return fd.fields[ef.Index], nil
+}
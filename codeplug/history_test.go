@@ -0,0 +1,159 @@
+package codeplug
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// historyCodeplug returns a blank codeplug with a history of edits,
+// the last of which has been undone.
+func historyCodeplug(t *testing.T) *Codeplug {
+	t.Helper()
+
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addRecords(t, cp, RtChannelInformation, "Two", "Three")
+	channels := cp.Records(RtChannelInformation)
+	zone := cp.Records(RtZoneInformation)[0]
+	setListFields(zone, FtChannelMember, recordNames(channels))
+
+	if err := cp.SetFields(channels[:1], FtColorCode, "7"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.SetFields(channels, FtTot, "60"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.RemoveRecords(channels[1:2], DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	if err := cp.SetFields(channels[:1], FtColorCode, "3"); err != nil {
+		t.Fatal(err)
+	}
+	cp.UndoChange()
+
+	return cp
+}
+
+// copyCodeplug returns a new codeplug in the same state as cp, but
+// without its history.
+func copyCodeplug(t *testing.T, cp *Codeplug) *Codeplug {
+	t.Helper()
+
+	cpBytes, err := cp.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy, err := NewCodeplugFromBytes(cpBytes, cp.Type())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return copy
+}
+
+func exportString(t *testing.T, cp *Codeplug) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := cp.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	cp := historyCodeplug(t)
+	defer cp.Free()
+
+	var history bytes.Buffer
+	if err := cp.SaveHistory(&history); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := copyCodeplug(t, cp)
+	defer loaded.Free()
+	if err := loaded.LoadHistory(&history); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; cp.UndoString() != ""; i++ {
+		if got, want := loaded.UndoString(), cp.UndoString(); got != want {
+			t.Fatalf("undo %d is %q, not %q", i, got, want)
+		}
+		cp.UndoChange()
+		loaded.UndoChange()
+		if exportString(t, loaded) != exportString(t, cp) {
+			t.Fatalf("codeplugs differ after undo %d", i)
+		}
+	}
+
+	for i := 0; cp.RedoString() != ""; i++ {
+		if got, want := loaded.RedoString(), cp.RedoString(); got != want {
+			t.Fatalf("redo %d is %q, not %q", i, got, want)
+		}
+		cp.RedoChange()
+		loaded.RedoChange()
+		if exportString(t, loaded) != exportString(t, cp) {
+			t.Fatalf("codeplugs differ after redo %d", i)
+		}
+	}
+}
+
+func TestHistoryCorrupt(t *testing.T) {
+	cp := historyCodeplug(t)
+	defer cp.Free()
+
+	var history bytes.Buffer
+	if err := cp.SaveHistory(&history); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(changes []encodedChange)
+	}{
+		{"field value", func(changes []encodedChange) {
+			changes[0].Changes[0].Strings[0] = "99"
+		}},
+		{"batch value", func(changes []encodedChange) {
+			changes[1].Changes[2].Strings[0] = "9999"
+		}},
+		{"position", func(changes []encodedChange) {
+			changes[2].Strings[0] = "Nonexistent"
+		}},
+		{"undone field value", func(changes []encodedChange) {
+			changes[3].Changes[0].Strings[0] = "99"
+		}},
+	}
+
+	for _, test := range tests {
+		var eh encodedHistory
+		if err := json.Unmarshal(history.Bytes(), &eh); err != nil {
+			t.Fatal(err)
+		}
+		test.corrupt(eh.Changes)
+		corrupt, err := json.Marshal(eh)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded := copyCodeplug(t, cp)
+		before := exportString(t, loaded)
+		err = loaded.LoadHistory(bytes.NewReader(corrupt))
+		if err == nil {
+			t.Errorf("%s: LoadHistory succeeded", test.name)
+		}
+		if loaded.UndoString() != "" || loaded.RedoString() != "" {
+			t.Errorf("%s: LoadHistory changed the history", test.name)
+		}
+		if exportString(t, loaded) != before {
+			t.Errorf("%s: LoadHistory changed the codeplug", test.name)
+		}
+		loaded.Free()
+	}
+}
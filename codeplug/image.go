@@ -0,0 +1,502 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// ImageBytes returns a copy of the codeplug's rdt contents, updated
+// with the current state of all of its records.  Offsets into the
+// image are rdt offsets, even for a codeplug read from a bin file.
+func (cp *Codeplug) ImageBytes() []byte {
+	return cp.imageBytes()
+}
+
+// A ByteLocation tells what part of the codeplug holds the byte at an
+// rdt offset.  RecordType is empty for a byte that isn't part of any
+// record slot.  Record is nil for a byte of an empty record slot.
+// RecordOffset is the byte's offset within its record, and Bits gives
+// the field holding each of its bits.
+type ByteLocation struct {
+	Offset       int
+	Value        byte
+	RecordType   RecordType
+	RecordIndex  int
+	RecordOffset int
+	Record       *Record
+	Bits         [8]MapBit
+}
+
+// String returns a description of the location, such as
+// "ChannelInformation 3 (Local 1), byte 0x05: 7-6 Privacy, 5-0 unclaimed".
+func (l ByteLocation) String() string {
+	if l.RecordType == "" {
+		return fmt.Sprintf("0x%05x: not part of any record", l.Offset)
+	}
+
+	name := fmt.Sprintf("%s %d", l.RecordType, l.RecordIndex+1)
+	switch {
+	case l.Record == nil:
+		name += " (empty)"
+	case l.Record.Name() != "":
+		name += " (" + l.Record.Name() + ")"
+	}
+
+	fields := []string{}
+	for _, run := range (MapByte{Bits: l.Bits}).runs() {
+		fields = append(fields, run.bitNumbers()+" "+run.bit.String())
+	}
+
+	return fmt.Sprintf("%s, byte 0x%02x: %s", name, l.RecordOffset,
+		strings.Join(fields, ", "))
+}
+
+// Locate returns the location of the byte at the given rdt offset.
+func (cp *Codeplug) Locate(offset int) (ByteLocation, error) {
+	cpBytes := cp.imageBytes()
+	if offset < 0 || offset >= len(cpBytes) {
+		return ByteLocation{}, fmt.Errorf("offset 0x%05x is outside the codeplug", offset)
+	}
+
+	l := ByteLocation{
+		Offset:      offset,
+		Value:       cpBytes[offset],
+		RecordIndex: -1,
+	}
+
+	for _, rd := range cp.rDesc {
+		if offset < rd.offset || offset >= rd.offset+rd.max*rd.size {
+			continue
+		}
+		l.RecordType = rd.rType
+		l.RecordIndex = (offset - rd.offset) / rd.size
+		l.RecordOffset = (offset - rd.offset) % rd.size
+		if l.RecordIndex < len(rd.records) {
+			l.Record = rd.records[l.RecordIndex]
+		}
+		m := newMemoryMap(cp.codeplugType, rd.rInfo)
+		l.Bits = m.Bytes[l.RecordOffset].Bits
+		break
+	}
+
+	return l, nil
+}
+
+// SetBytes sets the codeplug's rdt bytes at offset to data, as a
+// single change that may be undone.  The records holding the bytes are
+// reloaded, so the bytes may add or remove records, or change the
+// values of their fields.  Records are reloaded by slot, so adding or
+// removing a record renumbers the records that follow it.  Bytes that
+// no field describes are kept as set.
+func (cp *Codeplug) SetBytes(offset int, data []byte) error {
+	if err := cp.checkBytes(offset, data); err != nil {
+		return err
+	}
+
+	start, end := cp.recordSpan(offset, offset+len(data))
+	before := cp.imageBytes()[start:end]
+	cp.setBytes(offset, data)
+	after := cp.imageBytes()[start:end]
+
+	change := bytesChange(cp, start, before, after)
+	if change != nil {
+		change.Complete()
+	}
+
+	return nil
+}
+
+// checkBytes returns an error if data doesn't fit in the codeplug's rdt
+// contents at offset.
+func (cp *Codeplug) checkBytes(offset int, data []byte) error {
+	size := cpInfos[cp.codeplugType].rdtSize
+	if offset < 0 || offset+len(data) > size {
+		return fmt.Errorf("%d bytes at 0x%05x don't fit in the codeplug",
+			len(data), offset)
+	}
+
+	return nil
+}
+
+// recordSpan returns the range of rdt offsets from start to end,
+// extended to include all of the record slots of each record type
+// having slots in the range.  Setting bytes in one slot may add or
+// remove a record, and so move the records in the slots that follow.
+func (cp *Codeplug) recordSpan(start int, end int) (int, int) {
+	spanStart, spanEnd := start, end
+	for _, rd := range cp.rDesc {
+		rdEnd := rd.offset + rd.max*rd.size
+		if start < rdEnd && end > rd.offset {
+			if rd.offset < spanStart {
+				spanStart = rd.offset
+			}
+			if rdEnd > spanEnd {
+				spanEnd = rdEnd
+			}
+		}
+	}
+
+	return spanStart, spanEnd
+}
+
+// bytesChange returns a BytesChange that changed the rdt bytes at
+// offset from before to after, limited to the bytes that differ, or
+// nil if none differ.  As with a ListIndexChange, strings holds the
+// bytes to be set by undo or redo, afterStrings holds the bytes now in
+// place, in hex, and the two are swapped each time the change is made.
+func bytesChange(cp *Codeplug, offset int, before []byte, after []byte) *Change {
+	i, j := 0, len(after)
+	for i < j && before[i] == after[i] {
+		i++
+	}
+	for j > i && before[j-1] == after[j-1] {
+		j--
+	}
+	if i == j {
+		return nil
+	}
+
+	change := Change{
+		cType:        BytesChange,
+		codeplug:     cp,
+		offset:       offset + i,
+		strings:      []string{hex.EncodeToString(before[i:j])},
+		afterStrings: []string{hex.EncodeToString(after[i:j])},
+	}
+
+	return &change
+}
+
+// applyBytesChange sets the bytes given by the change's strings.
+func (cp *Codeplug) applyBytesChange(change *Change) {
+	data, err := hex.DecodeString(change.strings[0])
+	if err != nil {
+		log.Fatal("BytesChange: bad bytes ", change.strings[0])
+	}
+	cp.setBytes(change.offset, data)
+	change.strings, change.afterStrings = change.afterStrings, change.strings
+}
+
+// setBytes sets the rdt bytes at offset to data and reloads the
+// records of each record type whose slots hold any of the bytes.
+func (cp *Codeplug) setBytes(offset int, data []byte) {
+	cpBytes := cp.imageBytes()
+	copy(cpBytes[offset:], data)
+
+	if cp.byteEdits == nil {
+		cp.byteEdits = make(map[int]byte)
+	}
+	for i, b := range data {
+		cp.byteEdits[offset+i] = b
+	}
+
+	end := offset + len(data)
+	for _, rd := range cp.rDesc {
+		if offset < rd.offset+rd.max*rd.size && end > rd.offset {
+			rd.reloadRecords(cpBytes)
+		}
+	}
+	cp.clearCachedListNames()
+}
+
+// bytesString returns a description of a BytesChange that changes
+// the bytes from one hex string to another.
+func (change *Change) bytesString(from string, to string) string {
+	n := len(from) / 2
+	if n > 8 {
+		return fmt.Sprintf("0x%05x: set %d bytes", change.offset, n)
+	}
+
+	return fmt.Sprintf("0x%05x: %s -> %s", change.offset, from, to)
+}
+
+// replayBytesChange replays the BytesChange event ev.
+func (cp *Codeplug) replayBytesChange(ev *ChangeEvent) error {
+	if len(ev.Indexes) == 0 || len(ev.Before) == 0 || len(ev.After) == 0 {
+		return fmt.Errorf("%s has no bytes", ev.Type)
+	}
+
+	offset := ev.Indexes[0]
+	before, err := hex.DecodeString(ev.Before[0])
+	if err != nil {
+		return fmt.Errorf("%s: %s", ev.Type, err)
+	}
+	after, err := hex.DecodeString(ev.After[0])
+	if err != nil {
+		return fmt.Errorf("%s: %s", ev.Type, err)
+	}
+	if len(before) != len(after) {
+		return fmt.Errorf("%s: before and after differ in length", ev.Type)
+	}
+	if err := cp.checkBytes(offset, after); err != nil {
+		return err
+	}
+
+	current := cp.imageBytes()[offset : offset+len(after)]
+	if bytes.Equal(current, after) {
+		return nil
+	}
+	if !bytes.Equal(current, before) {
+		return fmt.Errorf("bytes at 0x%05x are %x, not %x",
+			offset, current, before)
+	}
+
+	start, end := cp.recordSpan(offset, offset+len(after))
+	before = cp.imageBytes()[start:end]
+	cp.setBytes(offset, after)
+
+	change := bytesChange(cp, start, before, cp.imageBytes()[start:end])
+	if change == nil {
+		return nil
+	}
+	change.remote = true
+	change.add()
+	cp.publishChange(change)
+
+	return nil
+}
+
+// imageBytesName names the section of a codeplug's text form holding
+// the image bytes that none of its exported records hold.
+const imageBytesName = "ImageBytes"
+
+// imageRegionName names each region of the ImageBytes section.
+const imageRegionName = "Bytes"
+
+const (
+	// minRunBytes is the shortest run of a repeated byte given
+	// as a run, rather than as its bytes.
+	minRunBytes = 8
+
+	// maxRegionBytes is the most bytes given in one region,
+	// other than as a run.
+	maxRegionBytes = 32
+)
+
+// An imageRegion holds image bytes that no exported record holds: those
+// of the rdt header, of empty record slots, and of the gaps between
+// record types.  Export writes them and Import sets them, so that an
+// imported codeplug's image is identical to that exported, whatever the
+// codeplug it is imported into.  The region's bytes are count copies
+// of pattern, which is a single byte or slot for a run of them.
+type imageRegion struct {
+	offset  int
+	pattern []byte
+	count   int
+}
+
+// bytes returns the region's bytes.
+func (ir imageRegion) bytes() []byte {
+	return bytes.Repeat(ir.pattern, ir.count)
+}
+
+// String returns the region as its rdt offset and its bytes, in
+// hexadecimal, as in "0x00226:00ff01", or, for a run, as its pattern
+// and a decimal count, as in "0x00226:ff*1024".
+func (ir imageRegion) String() string {
+	str := fmt.Sprintf("0x%05x:%s", ir.offset, hex.EncodeToString(ir.pattern))
+	if ir.count > 1 {
+		str += fmt.Sprintf("*%d", ir.count)
+	}
+
+	return str
+}
+
+// repeats returns the number of leading copies, in data, of its first
+// size bytes.
+func repeats(data []byte, size int) int {
+	if len(data) < size {
+		return 0
+	}
+
+	n := 1
+	for (n+1)*size <= len(data) &&
+		bytes.Equal(data[n*size:(n+1)*size], data[:size]) {
+		n++
+	}
+
+	return n
+}
+
+// imageRegions returns the regions holding the codeplug's image bytes
+// that lie outside the slots of its exported records.
+func (cp *Codeplug) imageRegions() []imageRegion {
+	cpBytes := cp.imageBytes()
+
+	// slotSizes holds 0 for a byte of a used slot, the slot size
+	// for a byte of an empty slot, and 1 for any other byte.
+	slotSizes := make([]int, len(cpBytes))
+	for i := range slotSizes {
+		slotSizes[i] = 1
+	}
+	for _, rType := range cp.RecordTypes() {
+		rd := cp.rDesc[rType]
+		used := rd.offset + len(rd.records)*rd.size
+		end := rd.offset + rd.max*rd.size
+		for i := rd.offset; i < end; i++ {
+			if i < used {
+				slotSizes[i] = 0
+			} else {
+				slotSizes[i] = rd.size
+			}
+		}
+	}
+
+	var regions []imageRegion
+	for start := 0; start < len(cpBytes); {
+		size := slotSizes[start]
+		end := start + 1
+		for end < len(cpBytes) && slotSizes[end] == size {
+			end++
+		}
+		if size != 0 {
+			regions = append(regions, splitRegion(start, cpBytes[start:end], size)...)
+		}
+		start = end
+	}
+
+	return regions
+}
+
+// splitRegion splits the bytes at offset, which are those of slots of
+// the given size, into regions.  Each region holds a run of identical
+// slots, a run of a repeated byte, or at most maxRegionBytes bytes.
+// No region but a run of slots crosses a slot boundary.
+func splitRegion(offset int, data []byte, slotSize int) []imageRegion {
+	var regions []imageRegion
+	for len(data) > 0 {
+		if n := repeats(data, slotSize); slotSize > 1 && n > 1 {
+			ir := imageRegion{offset, data[:slotSize], n}
+			if repeats(ir.pattern, 1) == slotSize {
+				ir = imageRegion{offset, data[:1], n * slotSize}
+			}
+			regions = append(regions, ir)
+			offset += n * slotSize
+			data = data[n*slotSize:]
+			continue
+		}
+
+		slot := data
+		if len(slot) > slotSize && slotSize > 1 {
+			slot = data[:slotSize]
+		}
+		for len(slot) > 0 {
+			ir := imageRegion{offset, slot[:1], repeats(slot, 1)}
+			if ir.count < minRunBytes {
+				n := 1
+				for n < len(slot) && n < maxRegionBytes &&
+					repeats(slot[n:], 1) < minRunBytes {
+					n++
+				}
+				ir = imageRegion{offset, slot[:n], 1}
+			}
+			n := len(ir.pattern) * ir.count
+			regions = append(regions, ir)
+			offset += n
+			slot = slot[n:]
+			data = data[n:]
+		}
+	}
+
+	return regions
+}
+
+// parseImageRegion returns the region given by str, in the form
+// returned by imageRegion's String.
+func (cp *Codeplug) parseImageRegion(str string) (imageRegion, error) {
+	badRegion := fmt.Errorf("bad %s: %s", imageBytesName, str)
+
+	strs := strings.SplitN(str, ":", 2)
+	if len(strs) != 2 {
+		return imageRegion{}, badRegion
+	}
+	offset, err := strconv.ParseInt(strs[0], 0, 0)
+	if err != nil {
+		return imageRegion{}, badRegion
+	}
+
+	strs = strings.SplitN(strs[1], "*", 2)
+	pattern, err := hex.DecodeString(strs[0])
+	if err != nil || len(pattern) == 0 {
+		return imageRegion{}, badRegion
+	}
+	count := 1
+	if len(strs) == 2 {
+		count, err = strconv.Atoi(strs[1])
+		if err != nil || count < 1 {
+			return imageRegion{}, badRegion
+		}
+	}
+
+	ir := imageRegion{int(offset), pattern, count}
+	if count > cpInfos[cp.codeplugType].rdtSize/len(pattern) {
+		return imageRegion{}, badRegion
+	}
+	data := ir.bytes()
+	if err := cp.checkBytes(int(offset), data); err != nil {
+		return imageRegion{}, err
+	}
+
+	return ir, nil
+}
+
+// setImageRegions sets the image bytes of the given regions, as
+// SetBytes sets bytes but without a change, and reloads the rdt header.
+// The bytes of other records' slots are kept only where no record is
+// stored.  The codeplug's previous byteEdits map is left unchanged, so
+// that Import may restore it.
+func (cp *Codeplug) setImageRegions(regions []imageRegion) {
+	if len(regions) == 0 {
+		return
+	}
+
+	cpBytes := cp.imageBytes()
+	byteEdits := make(map[int]byte, len(cp.byteEdits))
+	for offset, b := range cp.byteEdits {
+		byteEdits[offset] = b
+	}
+	for _, ir := range regions {
+		data := ir.bytes()
+		copy(cpBytes[ir.offset:], data)
+		for i, b := range data {
+			offset := ir.offset + i
+			if b == cp.bytes[offset] {
+				delete(byteEdits, offset)
+				continue
+			}
+			byteEdits[offset] = b
+		}
+	}
+	cp.byteEdits = byteEdits
+
+	cp.rDesc[RtRdtHeader].loadRecords(cpBytes)
+	cp.lowFrequency = 0
+	cp.highFrequency = 0
+}
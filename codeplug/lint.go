@@ -0,0 +1,400 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A LintRule checks a codeplug for a kind of programming mistake that,
+// unlike an invalid field value, doesn't prevent the codeplug from
+// being written, but makes the radio behave badly on the air.
+type LintRule struct {
+	// ID identifies the rule, as in "digital-no-contact".
+	ID string
+
+	// Severity is that of the rule's issues.
+	Severity Severity
+
+	// Description tells what the rule checks.
+	Description string
+
+	// Check reports each mistake found in the linter's codeplug.
+	Check func(l *Linter)
+}
+
+// A Linter is passed to a LintRule's Check function to collect its
+// issues.
+type Linter struct {
+	codeplug *Codeplug
+	rule     *LintRule
+	issues   []Issue
+}
+
+// Codeplug returns the codeplug being checked.
+func (l *Linter) Codeplug() *Codeplug {
+	return l.codeplug
+}
+
+// Report adds an issue, with a message formatted as by fmt.Sprintf,
+// concerning the given field of the record.  If f is nil, the issue
+// concerns the record as a whole.
+func (l *Linter) Report(r *Record, f *Field, format string, args ...interface{}) {
+	issue := Issue{
+		RecordType:  r.rType,
+		RecordIndex: r.rIndex,
+		Severity:    l.rule.Severity,
+		Message:     fmt.Sprintf(format, args...),
+		Rule:        l.rule.ID,
+	}
+
+	location := string(r.rType)
+	if r.max > 1 {
+		location += fmt.Sprintf("[%d]", r.rIndex+1)
+	}
+	issue.name = r.TypeName()
+	if r.Name() != "" {
+		issue.name += " " + r.Name()
+	}
+	issue.location = location
+
+	if f != nil {
+		issue.FieldType = f.fType
+		issue.FieldIndex = f.fIndex
+		issue.Value = f.String()
+		issue.name += ": " + f.typeName
+		issue.location = f.location()
+	}
+
+	l.issues = append(l.issues, issue)
+}
+
+// lintRules holds the registered lint rules, in order of registration.
+var lintRules []*LintRule
+var lintRulesMutex sync.Mutex
+
+// RegisterLintRule adds a rule to those checked by Lint.  It returns an
+// error if a rule with the same ID has already been registered.
+func RegisterLintRule(rule *LintRule) error {
+	lintRulesMutex.Lock()
+	defer lintRulesMutex.Unlock()
+
+	if lintRuleByID(rule.ID) != nil {
+		return fmt.Errorf("duplicate lint rule: %s", rule.ID)
+	}
+	lintRules = append(lintRules, rule)
+
+	return nil
+}
+
+// LintRules returns the registered lint rules.
+func LintRules() []*LintRule {
+	lintRulesMutex.Lock()
+	defer lintRulesMutex.Unlock()
+
+	return append([]*LintRule{}, lintRules...)
+}
+
+// LintRuleByID returns the registered rule with the given ID, or nil.
+func LintRuleByID(id string) *LintRule {
+	lintRulesMutex.Lock()
+	defer lintRulesMutex.Unlock()
+
+	return lintRuleByID(id)
+}
+
+// lintRuleByID is LintRuleByID, called with lintRulesMutex held.
+func lintRuleByID(id string) *LintRule {
+	for _, rule := range lintRules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// Lint checks the codeplug against the given rules, or, if none are
+// given, against all registered rules, and returns the issues found.
+// Each issue's Rule is the ID of the rule that found it.
+func (cp *Codeplug) Lint(rules ...*LintRule) []Issue {
+	if len(rules) == 0 {
+		rules = LintRules()
+	}
+
+	var issues []Issue
+	for _, rule := range rules {
+		l := &Linter{codeplug: cp, rule: rule}
+		rule.Check(l)
+		issues = append(issues, l.issues...)
+	}
+
+	return issues
+}
+
+func init() {
+	rules := []*LintRule{
+		{
+			ID:          "digital-no-contact",
+			Severity:    SeverityWarning,
+			Description: "digital channels that aren't receive-only must have a contact",
+			Check:       lintDigitalNoContact,
+		},
+		{
+			ID:          "contact-not-in-group-list",
+			Severity:    SeverityWarning,
+			Description: "a digital channel's contact should be a member of its group list",
+			Check:       lintContactNotInGroupList,
+		},
+		{
+			ID:          "admit-criteria-mode",
+			Severity:    SeverityWarning,
+			Description: "a channel's admit criteria should suit its mode",
+			Check:       lintAdmitCriteriaMode,
+		},
+		{
+			ID:          "scan-tx-not-member",
+			Severity:    SeverityWarning,
+			Description: "a scan list's designated transmit channel should be a member",
+			Check:       lintScanTxNotMember,
+		},
+		{
+			ID:          "zone-mixed-bands",
+			Severity:    SeverityInfo,
+			Description: "a zone's channels should all be in the same band",
+			Check:       lintZoneMixedBands,
+		},
+		{
+			ID:          "duplicate-channel",
+			Severity:    SeverityWarning,
+			Description: "channels should differ in more than their names",
+			Check:       lintDuplicateChannel,
+		},
+		{
+			ID:          "nonstandard-split",
+			Severity:    SeverityWarning,
+			Description: "a channel's split should be the band plan's repeater offset, or none in a simplex segment",
+			Check:       lintNonstandardSplit,
+		},
+		{
+			ID:          "tx-no-transmit-segment",
+			Severity:    SeverityWarning,
+			Description: "a channel shouldn't transmit in a band plan segment where transmitting is not allowed",
+			Check:       lintTxNoTransmitSegment,
+		},
+	}
+
+	for _, rule := range rules {
+		if err := RegisterLintRule(rule); err != nil {
+			panic(err.Error())
+		}
+	}
+}
+
+// lintValue returns the value of the record's first field of the given
+// type, or "" if it has none.
+func lintValue(r *Record, fType FieldType) string {
+	fields := fieldsOfType(r, fType)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0].String()
+}
+
+// isDigital returns true if r is a digital channel.
+func isDigital(r *Record) bool {
+	return lintValue(r, FtChannelMode) == "Digital"
+}
+
+func lintDigitalNoContact(l *Linter) {
+	for _, r := range l.Codeplug().Records(RtChannelInformation) {
+		if !isDigital(r) || lintValue(r, FtRxOnly) == "On" {
+			continue
+		}
+		f := r.Field(FtContactName)
+		if f != nil && f.String() == "None" {
+			l.Report(r, f, "digital channel has no contact, so it can't transmit")
+		}
+	}
+}
+
+func lintContactNotInGroupList(l *Linter) {
+	cp := l.Codeplug()
+	for _, r := range cp.Records(RtChannelInformation) {
+		contact := lintValue(r, FtContactName)
+		groupList := lintValue(r, FtGroupList)
+		if !isDigital(r) || contact == "" || contact == "None" ||
+			groupList == "" || groupList == "None" {
+			continue
+		}
+
+		gl := cp.FindRecordByName(RtGroupList, groupList)
+		if gl == nil {
+			continue
+		}
+		var members []string
+		for _, f := range fieldsOfType(gl, FtContactMember) {
+			members = append(members, f.String())
+		}
+		if !stringInSlice(contact, members) {
+			l.Report(r, r.Field(FtGroupList),
+				"group list %s doesn't contain the channel's contact %s",
+				groupList, contact)
+		}
+	}
+}
+
+func lintAdmitCriteriaMode(l *Linter) {
+	for _, r := range l.Codeplug().Records(RtChannelInformation) {
+		f := r.Field(FtAdmitCriteria)
+		if f == nil {
+			continue
+		}
+		switch {
+		case isDigital(r) && f.String() == "CTCSS/DCS":
+			l.Report(r, f, "CTCSS/DCS admit criteria on a digital channel")
+		case !isDigital(r) && f.String() == "Color code":
+			l.Report(r, f, "color code admit criteria on an analog channel")
+		}
+	}
+}
+
+func lintScanTxNotMember(l *Linter) {
+	for _, r := range l.Codeplug().Records(RtScanList) {
+		f := r.Field(FtTxDesignatedChannel)
+		if f == nil {
+			continue
+		}
+		switch f.String() {
+		case "Selected", "Last Active Channel":
+			continue
+		}
+
+		var members []string
+		for _, m := range fieldsOfType(r, FtChannelMember) {
+			members = append(members, m.String())
+		}
+		if !stringInSlice(f.String(), members) {
+			l.Report(r, f, "designated transmit channel %s is not a member",
+				f.String())
+		}
+	}
+}
+
+// band returns the name of the amateur band containing the frequency,
+// in MHz, or "" if it is not a valid frequency.
+func band(freq string) string {
+	mhz, err := strconv.ParseFloat(freq, 64)
+	switch {
+	case err != nil || mhz <= 0:
+		return ""
+	case mhz < 300:
+		return "VHF"
+	}
+
+	return "UHF"
+}
+
+func lintZoneMixedBands(l *Linter) {
+	cp := l.Codeplug()
+	for _, r := range cp.Records(RtZoneInformation) {
+		channels := make(map[string][]string)
+		var bands []string
+		for _, f := range fieldsOfType(r, FtChannelMember) {
+			ch := cp.FindRecordByName(RtChannelInformation, f.String())
+			if ch == nil {
+				continue
+			}
+			b := band(lintValue(ch, FtRxFrequency))
+			if b == "" {
+				continue
+			}
+			if channels[b] == nil {
+				bands = append(bands, b)
+			}
+			channels[b] = append(channels[b], ch.Name())
+		}
+		if len(bands) < 2 {
+			continue
+		}
+
+		var strs []string
+		for _, b := range bands {
+			strs = append(strs, fmt.Sprintf("%s (%s)", b,
+				maxNamesString(channels[b], 3)))
+		}
+		l.Report(r, nil, "zone has channels in more than one band: %s",
+			strings.Join(strs, ", "))
+	}
+}
+
+// channelKey returns a string identifying a channel's behavior on the
+// air: its mode and frequencies and, for digital channels, its slot,
+// color code and contact, or for analog channels, its CTCSS/DCS codes.
+func channelKey(r *Record) string {
+	fTypes := []FieldType{FtChannelMode, FtRxFrequency, FtTxFrequency}
+	if isDigital(r) {
+		fTypes = append(fTypes, FtRepeaterSlot, FtColorCode, FtContactName)
+	} else {
+		fTypes = append(fTypes, FtCtcssDecode, FtCtcssEncode)
+	}
+
+	var strs []string
+	for _, fType := range fTypes {
+		strs = append(strs, lintValue(r, fType))
+	}
+
+	return strings.Join(strs, "\x00")
+}
+
+func lintDuplicateChannel(l *Linter) {
+	first := make(map[string]*Record)
+	var keys []string
+	duplicates := make(map[string][]*Record)
+	for _, r := range l.Codeplug().Records(RtChannelInformation) {
+		key := channelKey(r)
+		if first[key] == nil {
+			first[key] = r
+			continue
+		}
+		if duplicates[key] == nil {
+			keys = append(keys, key)
+		}
+		duplicates[key] = append(duplicates[key], r)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return first[keys[i]].rIndex < first[keys[j]].rIndex
+	})
+	for _, key := range keys {
+		for _, r := range duplicates[key] {
+			l.Report(r, nil, "channel is the same as %s", first[key].Name())
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package codeplug
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lintBandPlan is a band plan for testing the band plan lint rules.
+var lintBandPlan = &BandPlan{
+	Name: "Lint Test",
+	Bands: []*Band{{
+		Name: "2 m",
+		Low:  144,
+		High: 148,
+		Segments: []*Segment{
+			{Low: 145.8, High: 146.0, Use: SegmentNoTransmit},
+			{Low: 146.4, High: 146.6, Use: SegmentSimplex},
+			{Low: 146.6, High: 147.4, Use: SegmentRepeaterOutput, Offset: -0.6},
+		},
+	}},
+}
+
+// lintCodeplug returns a dual-band codeplug for which no lint rule
+// reports an issue.  It has an analog simplex channel, "Channel 1", and
+// a digital repeater channel, "Digital", both members of the zone and
+// the scan list.
+func lintCodeplug(t *testing.T) *Codeplug {
+	t.Helper()
+
+	cp, err := NewBlankCodeplug(CtUv380, FrequencyRanges(CtUv380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.SetBandPlan(lintBandPlan)
+
+	addRecords(t, cp, RtChannelInformation, "Digital")
+	values := []struct {
+		name  string
+		fType FieldType
+		str   string
+	}{
+		{"Channel 1", FtRxFrequency, "146.52"},
+		{"Channel 1", FtTxFrequency, "146.52"},
+		{"Digital", FtChannelMode, "Digital"},
+		{"Digital", FtRxFrequency, "146.94"},
+		{"Digital", FtTxFrequency, "146.34"},
+		{"Digital", FtContactName, "Contact 1"},
+		{"Digital", FtGroupList, "Group List 1"},
+		{"Digital", FtAdmitCriteria, "Color code"},
+	}
+	for _, v := range values {
+		setField(t, cp, RtChannelInformation, v.name, v.fType, v.str)
+	}
+
+	members := []string{"Channel 1", "Digital"}
+	setListFields(cp.Records(RtZoneInformation)[0], FtChannelMember, members)
+	scanList := cp.Records(RtScanList)[0]
+	setListFields(scanList, FtChannelMember, members)
+	setField(t, cp, RtScanList, scanList.Name(), FtTxDesignatedChannel, "Channel 1")
+	groupList := cp.Records(RtGroupList)[0]
+	setListFields(groupList, FtContactMember, []string{"Contact 1"})
+
+	return cp
+}
+
+func TestLintRules(t *testing.T) {
+	channel := func(name string, fType FieldType, str string) func(*testing.T, *Codeplug) {
+		return func(t *testing.T, cp *Codeplug) {
+			setField(t, cp, RtChannelInformation, name, fType, str)
+		}
+	}
+	edits := func(edits ...func(*testing.T, *Codeplug)) func(*testing.T, *Codeplug) {
+		return func(t *testing.T, cp *Codeplug) {
+			for _, edit := range edits {
+				edit(t, cp)
+			}
+		}
+	}
+	scanTx := func(str string) func(*testing.T, *Codeplug) {
+		return func(t *testing.T, cp *Codeplug) {
+			r := cp.Records(RtScanList)[0]
+			setField(t, cp, RtScanList, r.Name(), FtTxDesignatedChannel, str)
+		}
+	}
+	copyChannel := func(t *testing.T, cp *Codeplug) {
+		addRecords(t, cp, RtChannelInformation, "Copy")
+	}
+
+	tests := []struct {
+		rule  string
+		edit  func(t *testing.T, cp *Codeplug)
+		names []string
+	}{
+		{"digital-no-contact", channel("Digital", FtContactName, "None"), []string{"Digital"}},
+		{"digital-no-contact", edits(
+			channel("Digital", FtContactName, "None"),
+			channel("Digital", FtRxOnly, "On"),
+		), nil},
+
+		{"contact-not-in-group-list", func(t *testing.T, cp *Codeplug) {
+			addRecords(t, cp, RtDigitalContacts, "Other")
+			setField(t, cp, RtDigitalContacts, "Other", FtCallID, "2")
+			setField(t, cp, RtChannelInformation, "Digital", FtContactName, "Other")
+		}, []string{"Digital"}},
+		{"contact-not-in-group-list", channel("Digital", FtGroupList, "None"), nil},
+
+		{"admit-criteria-mode", channel("Channel 1", FtAdmitCriteria, "Color code"), []string{"Channel 1"}},
+		{"admit-criteria-mode", channel("Channel 1", FtAdmitCriteria, "CTCSS/DCS"), nil},
+
+		{"scan-tx-not-member", func(t *testing.T, cp *Codeplug) {
+			r := cp.Records(RtScanList)[0]
+			setListFields(r, FtChannelMember, []string{"Digital"})
+		}, []string{"Scan List 1"}},
+		{"scan-tx-not-member", scanTx("Last Active Channel"), nil},
+
+		{"zone-mixed-bands", channel("Digital", FtRxFrequency, "446"), []string{"Zone 1"}},
+		{"zone-mixed-bands", channel("Digital", FtRxFrequency, "147.0"), nil},
+
+		{"duplicate-channel", edits(copyChannel, channel("Copy", FtColorCode, "5")), []string{"Copy"}},
+		{"duplicate-channel", edits(copyChannel, channel("Copy", FtCtcssEncode, "100.0")), nil},
+
+		{"nonstandard-split", channel("Channel 1", FtTxFrequency, "147"), []string{"Channel 1"}},
+		{"nonstandard-split", edits(
+			channel("Channel 1", FtTxFrequency, "147"),
+			channel("Channel 1", FtRxOnly, "On"),
+		), nil},
+		{"nonstandard-split", channel("Digital", FtTxFrequency, "147.54"), []string{"Digital"}},
+
+		{"tx-no-transmit-segment", channel("Channel 1", FtTxFrequency, "145.9"), []string{"Channel 1"}},
+		{"tx-no-transmit-segment", edits(
+			channel("Channel 1", FtTxFrequency, "145.9"),
+			func(t *testing.T, cp *Codeplug) { cp.SetBandPlan(nil) },
+		), nil},
+	}
+
+	rules := make(map[string]bool)
+	for _, test := range tests {
+		rule := LintRuleByID(test.rule)
+		if rule == nil {
+			t.Fatalf("no rule %s", test.rule)
+		}
+		rules[test.rule] = true
+
+		cp := lintCodeplug(t)
+		if issues := cp.Lint(rule); len(issues) != 0 {
+			t.Errorf("%s: unedited codeplug has issues %v", test.rule, issues)
+		}
+
+		test.edit(t, cp)
+		var names []string
+		for _, issue := range cp.Lint(rule) {
+			if issue.Rule != test.rule {
+				t.Errorf("%s: issue's rule is %s", test.rule, issue.Rule)
+			}
+			r := cp.Records(issue.RecordType)[issue.RecordIndex]
+			names = append(names, r.Name())
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%s: issues concern %q, not %q", test.rule, names, test.names)
+		}
+
+		cp.Free()
+	}
+
+	for _, rule := range LintRules() {
+		if !rules[rule.ID] && !strings.HasPrefix(rule.ID, "test-") {
+			t.Errorf("rule %s is not tested", rule.ID)
+		}
+	}
+}
+
+func TestRegisterLintRule(t *testing.T) {
+	rule := LintRules()[0]
+	if err := RegisterLintRule(rule); err == nil {
+		t.Errorf("duplicate rule %s was registered", rule.ID)
+	}
+}
+
+// TestLintConcurrently registers a rule while codeplugs are linted.
+// It is meant to be run with -race.
+func TestLintConcurrently(t *testing.T) {
+	cp := lintCodeplug(t)
+	defer cp.Free()
+
+	// The ID is unique, so that the test may be repeated.
+	id := "test-concurrent-" + cp.ID()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		err := RegisterLintRule(&LintRule{
+			ID:    id,
+			Check: func(l *Linter) {},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		cp.Lint()
+	}()
+	wg.Wait()
+
+	if LintRuleByID(id) == nil {
+		t.Errorf("%s wasn't registered", id)
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// A MemoryMap shows which bits of a record are described by the fields
+// of its record type, and which are not.  It may include the bytes of
+// a record of a codeplug, and may mark the bytes that differ from those
+// of the same record of another codeplug, to help find undescribed
+// bits whose values change.
+type MemoryMap struct {
+	CodeplugType CodeplugType
+	RecordType   RecordType
+	RecordIndex  int
+	Offset       int
+	HasValues    bool
+	Bytes        []MapByte
+}
+
+// A MapByte describes one byte of a MemoryMap.  Bits[0] is the most
+// significant bit.  Value is the byte's value, if the memory map has
+// values.  Changed is true if the value differs from that of the
+// codeplug compared.
+type MapByte struct {
+	Offset  int
+	Value   byte
+	Changed bool
+	Bits    [8]MapBit
+}
+
+// A MapBit gives the field containing a bit of a MemoryMap.  Index is
+// the index of the field's value, for fields with multiple values.
+// FieldType is empty for a bit that is not described by any field.
+type MapBit struct {
+	FieldType FieldType
+	Index     int
+	max       int
+}
+
+// Unclaimed returns true if the bit is not described by any field.
+func (b MapBit) Unclaimed() bool {
+	return b.FieldType == ""
+}
+
+// String returns the name of the bit's field, including its index, if
+// the field has multiple values.
+func (b MapBit) String() string {
+	switch {
+	case b.Unclaimed():
+		return "unclaimed"
+
+	case b.max > 1:
+		return fmt.Sprintf("%s[%d]", b.FieldType, b.Index+1)
+	}
+
+	return string(b.FieldType)
+}
+
+// NewMemoryMap returns the memory map of the layout of a record type of
+// a codeplug type, without values.
+func NewMemoryMap(cpType CodeplugType, rType RecordType) (*MemoryMap, error) {
+	for i := range cpTypes[cpType] {
+		ri := &cpTypes[cpType][i]
+		if ri.rType == rType {
+			return newMemoryMap(cpType, ri), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s codeplugs have no %s records", cpType, rType)
+}
+
+func newMemoryMap(cpType CodeplugType, ri *rInfo) *MemoryMap {
+	m := &MemoryMap{
+		CodeplugType: cpType,
+		RecordType:   ri.rType,
+		RecordIndex:  -1,
+		Offset:       ri.offset,
+		Bytes:        make([]MapByte, ri.size),
+	}
+
+	for i := range m.Bytes {
+		m.Bytes[i].Offset = i
+	}
+
+	for i := range ri.fInfos {
+		fi := &ri.fInfos[i]
+		for index := 0; index < fi.max; index++ {
+			start := fi.bitOffset + index*fi.bitSize
+			for bit := start; bit < start+fi.bitSize; bit++ {
+				if bit/8 >= len(m.Bytes) {
+					break
+				}
+				m.Bytes[bit/8].Bits[bit%8] = MapBit{fi.fType, index, fi.max}
+			}
+		}
+	}
+
+	return m
+}
+
+// MemoryMap returns the memory map of the record of the given type at
+// rIndex, with the record's current values.  Any record slot may be
+// mapped, including those holding deleted records.
+func (cp *Codeplug) MemoryMap(rType RecordType, rIndex int) (*MemoryMap, error) {
+	rd := cp.rDesc[rType]
+	if rd == nil {
+		return nil, fmt.Errorf("%s codeplugs have no %s records",
+			cp.codeplugType, rType)
+	}
+	if rIndex < 0 || rIndex >= rd.max {
+		return nil, fmt.Errorf("%s index %d is not between 1 and %d",
+			rType, rIndex+1, rd.max)
+	}
+
+	m := newMemoryMap(cp.codeplugType, rd.rInfo)
+	m.RecordIndex = rIndex
+	m.Offset = rd.offset + rIndex*rd.size
+	m.HasValues = true
+
+	cpBytes := cp.imageBytes()
+	for i := range m.Bytes {
+		m.Bytes[i].Value = cpBytes[m.Offset+i]
+	}
+
+	return m, nil
+}
+
+// Compare marks the bytes of the memory map whose values differ from
+// those of the same record of codeplug cp.
+func (m *MemoryMap) Compare(cp *Codeplug) error {
+	if !m.HasValues {
+		return fmt.Errorf("memory map has no values to compare")
+	}
+	if cp.codeplugType != m.CodeplugType {
+		return fmt.Errorf("cannot compare %s codeplug with %s codeplug",
+			cp.codeplugType, m.CodeplugType)
+	}
+
+	cpBytes := cp.imageBytes()
+	for i := range m.Bytes {
+		b := &m.Bytes[i]
+		b.Changed = cpBytes[m.Offset+i] != b.Value
+	}
+
+	return nil
+}
+
+// UnclaimedBits returns the number of bits not described by any field.
+func (m *MemoryMap) UnclaimedBits() int {
+	count := 0
+	for _, b := range m.Bytes {
+		for _, bit := range b.Bits {
+			if bit.Unclaimed() {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+// A bitRun is a run of adjacent bits of a byte belonging to the same
+// field value, or to no field.
+type bitRun struct {
+	bit   MapBit
+	first int
+	last  int
+}
+
+// bitNumbers returns the numbers of the bits of the run, where bit 7
+// is the most significant bit.
+func (run bitRun) bitNumbers() string {
+	high, low := 7-run.first, 7-run.last
+	if high == low {
+		return fmt.Sprintf("%d", high)
+	}
+
+	return fmt.Sprintf("%d-%d", high, low)
+}
+
+// runs returns the runs of bits making up the byte.
+func (b MapByte) runs() []bitRun {
+	runs := []bitRun{}
+	for i, bit := range b.Bits {
+		n := len(runs)
+		if n > 0 && runs[n-1].bit == bit {
+			runs[n-1].last = i
+			continue
+		}
+		runs = append(runs, bitRun{bit, i, i})
+	}
+
+	return runs
+}
+
+// unclaimed returns true if any of the byte's bits are unclaimed.
+func (b MapByte) unclaimed() bool {
+	for _, bit := range b.Bits {
+		if bit.Unclaimed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *MemoryMap) title() string {
+	title := fmt.Sprintf("%s %s", m.CodeplugType, m.RecordType)
+	if m.RecordIndex >= 0 {
+		title += fmt.Sprintf(" %d", m.RecordIndex+1)
+	}
+
+	return fmt.Sprintf("%s: %d bytes at rdt offset 0x%05x, %d bits unclaimed",
+		title, len(m.Bytes), m.Offset, m.UnclaimedBits())
+}
+
+// WriteText writes the memory map to w, one line per byte.  A "?" flag
+// marks bytes with unclaimed bits, and a "~" flag marks bytes whose
+// values differ from those of the codeplug compared.
+func (m *MemoryMap) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, m.title())
+	fmt.Fprintln(bw, "Offset Value Bits     Flags Fields")
+	for _, b := range m.Bytes {
+		value, bits := "-    ", "--------"
+		if m.HasValues {
+			value = fmt.Sprintf("0x%02x ", b.Value)
+			bits = fmt.Sprintf("%08b", b.Value)
+		}
+
+		flags := ""
+		if b.unclaimed() {
+			flags += "?"
+		}
+		if b.Changed {
+			flags += "~"
+		}
+
+		fields := []string{}
+		for _, run := range b.runs() {
+			fields = append(fields, run.bitNumbers()+" "+run.bit.String())
+		}
+
+		fmt.Fprintf(bw, "0x%04x %s %s %-5s %s\n", b.Offset, value, bits,
+			flags, strings.Join(fields, ", "))
+	}
+
+	return bw.Flush()
+}
+
+const memoryMapStyle = `<style>
+table.memorymap { border-collapse: collapse; font-family: monospace; }
+table.memorymap th, table.memorymap td { border: 1px solid #999; padding: 1px 4px; text-align: center; }
+table.memorymap td.unclaimed { background: #f99; }
+table.memorymap tr.changed td.value { background: #ff6; }
+</style>
+`
+
+// WriteHTML writes the memory map to w as an HTML document containing
+// a table with a row for each byte and a column for each bit.  Bits
+// not described by a field are highlighted, as are the values of bytes
+// that differ from those of the codeplug compared.
+func (m *MemoryMap) WriteHTML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	title := html.EscapeString(m.title())
+
+	fmt.Fprintf(bw, "<!DOCTYPE html>\n<html>\n<head>\n")
+	fmt.Fprintf(bw, "<meta charset=\"utf-8\">\n<title>%s</title>\n", title)
+	fmt.Fprint(bw, memoryMapStyle)
+	fmt.Fprintf(bw, "</head>\n<body>\n<h1>%s</h1>\n", title)
+	fmt.Fprintln(bw, `<table class="memorymap">`)
+	fmt.Fprint(bw, "<tr><th>Offset</th><th>Value</th>")
+	for bit := 7; bit >= 0; bit-- {
+		fmt.Fprintf(bw, "<th>%d</th>", bit)
+	}
+	fmt.Fprintln(bw, "</tr>")
+
+	for _, b := range m.Bytes {
+		class := ""
+		if b.Changed {
+			class = ` class="changed"`
+		}
+		value := "-"
+		if m.HasValues {
+			value = fmt.Sprintf("0x%02x", b.Value)
+		}
+		fmt.Fprintf(bw, "<tr%s><td>0x%04x</td><td class=\"value\">%s</td>",
+			class, b.Offset, value)
+
+		for _, run := range b.runs() {
+			class := ""
+			if run.bit.Unclaimed() {
+				class = ` class="unclaimed"`
+			}
+			label := html.EscapeString(run.bit.String())
+			if m.HasValues {
+				bits := fmt.Sprintf("%08b", b.Value)[run.first : run.last+1]
+				label += "<br>" + bits
+			}
+			fmt.Fprintf(bw, "<td colspan=\"%d\"%s>%s</td>",
+				run.last-run.first+1, class, label)
+		}
+		fmt.Fprintln(bw, "</tr>")
+	}
+
+	fmt.Fprintln(bw, "</table>\n</body>\n</html>")
+
+	return bw.Flush()
+}
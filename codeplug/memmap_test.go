@@ -0,0 +1,152 @@
+package codeplug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+// jsonCodeplugs is the form of codeplugs.json, with only the members
+// needed to find the bits claimed by each record type's fields.
+type jsonCodeplugs struct {
+	Codeplugs []struct {
+		Name     string `json:"name"`
+		Inherits string `json:"inherits"`
+		Records  []struct {
+			Type   string `json:"type"`
+			Size   int    `json:"size"`
+			Fields []struct {
+				BitOffset int `json:"bitOffset"`
+				BitSize   int `json:"bitSize"`
+				Max       int `json:"max"`
+			} `json:"fields"`
+		} `json:"records"`
+	} `json:"codeplugs"`
+}
+
+// TestUnclaimedBits checks the unclaimed bits of each record type
+// against codeplugs.json.  Records that modify an inherited record are
+// not checked.
+func TestUnclaimedBits(t *testing.T) {
+	data, err := ioutil.ReadFile("codeplugs.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jcps jsonCodeplugs
+	if err := json.Unmarshal(data, &jcps); err != nil {
+		t.Fatal(err)
+	}
+
+	inherited := make(map[string]map[string]bool)
+	checked := 0
+	for _, jcp := range jcps.Codeplugs {
+		rTypes := make(map[string]bool)
+		inherited[jcp.Name] = rTypes
+		for _, jr := range jcp.Records {
+			rTypes[jr.Type] = true
+			if inherited[jcp.Inherits][jr.Type] {
+				continue
+			}
+
+			claimed := make(map[int]bool)
+			for _, jf := range jr.Fields {
+				max := jf.Max
+				if max == 0 {
+					max = 1
+				}
+				for i := 0; i < max*jf.BitSize; i++ {
+					bit := jf.BitOffset + i
+					if bit < jr.Size*8 {
+						claimed[bit] = true
+					}
+				}
+			}
+			want := jr.Size*8 - len(claimed)
+
+			m, err := NewMemoryMap(CodeplugType(jcp.Name), RecordType(jr.Type))
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if n := m.UnclaimedBits(); n != want {
+				t.Errorf("%s %s: %d unclaimed bits, not %d", jcp.Name, jr.Type, n, want)
+			}
+			checked++
+		}
+		for rType := range inherited[jcp.Inherits] {
+			rTypes[rType] = true
+		}
+	}
+
+	if checked == 0 {
+		t.Errorf("no record types checked")
+	}
+}
+
+func TestMemoryMapCompare(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+	other := copyCodeplug(t, cp)
+	defer other.Free()
+
+	m, err := cp.MemoryMap(RtChannelInformation, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Compare(other); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range m.Bytes {
+		if b.Changed {
+			t.Errorf("byte 0x%04x of an identical codeplug is changed", b.Offset)
+		}
+	}
+
+	setField(t, other, RtChannelInformation, "Channel 1", FtColorCode, "5")
+	if err := m.Compare(other); err != nil {
+		t.Fatal(err)
+	}
+	changed := 0
+	for _, b := range m.Bytes {
+		if !b.Changed {
+			continue
+		}
+		changed++
+		hasColorCode := false
+		for _, bit := range b.Bits {
+			if bit.FieldType == FtColorCode {
+				hasColorCode = true
+			}
+		}
+		if !hasColorCode {
+			t.Errorf("byte 0x%04x, without ColorCode bits, is changed", b.Offset)
+		}
+	}
+	if changed != 1 {
+		t.Errorf("%d bytes changed, not 1", changed)
+	}
+
+	layout, err := NewMemoryMap(CtMd380, RtChannelInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := layout.Compare(other); err == nil {
+		t.Errorf("memory map without values was compared")
+	}
+
+	uv, err := NewBlankCodeplug(CtUv380, FrequencyRanges(CtUv380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer uv.Free()
+	if err := m.Compare(uv); err == nil {
+		t.Errorf("md380 memory map was compared with a uv380 codeplug")
+	}
+
+	if _, err := cp.MemoryMap(RtChannelInformation, cp.MaxRecords(RtChannelInformation)); err == nil {
+		t.Errorf("record slot beyond the last was mapped")
+	}
+}
@@ -0,0 +1,734 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A Conflict describes a change made in one derived codeplug that
+// could not be merged with a change made in the other.  Conflicts are
+// resolved in favor of ours, except that a record deleted on one side
+// and edited on the other is kept, with the edits.
+type Conflict struct {
+	RecordType RecordType
+	Name       string    // the record's name, if it has one
+	Index      int       // the record's index in the merged codeplug, or -1
+	FieldType  FieldType // empty for conflicts involving whole records
+	Base       string
+	Ours       string
+	Theirs     string
+	Message    string
+}
+
+// String returns a description of the conflict.
+func (c Conflict) String() string {
+	s := string(c.RecordType)
+	if c.Name != "" || c.Index >= 0 {
+		s += " " + recordLabel(c.Name, c.Index)
+	}
+	if c.FieldType != "" {
+		s += " " + string(c.FieldType)
+	}
+
+	return s + ": " + c.Message
+}
+
+// The three codeplugs of a merge.
+const (
+	sideBase = iota
+	sideOurs
+	sideTheirs
+	numSides
+)
+
+// A mergeRecord is a record of the merged codeplug, along with the
+// records of the base and derived codeplugs from which it is merged.
+type mergeRecord struct {
+	rType   RecordType
+	recs    [numSides]*Record
+	deleted bool
+	name    string
+	index   int
+	values  map[FieldType][]mergeValue
+}
+
+// A mergeValue is a field value of a merged record.  Values referring
+// to other records refer to the merged record, so that they follow
+// the record when it is renamed or moved.
+type mergeValue struct {
+	str string
+	ref *mergeRecord
+}
+
+// String returns the value as it is named in the merged codeplug.
+func (v mergeValue) String() string {
+	if v.ref != nil {
+		return v.ref.name
+	}
+
+	return v.str
+}
+
+// A merger holds the state of a three-way merge.
+type merger struct {
+	cps       [numSides]*Codeplug
+	records   map[RecordType][]*mergeRecord
+	ids       [numSides]map[*Record]*mergeRecord
+	byName    [numSides]map[RecordType]map[string]*Record
+	conflicts []Conflict
+}
+
+// Merge performs a three-way merge of two codeplugs, ours and theirs,
+// derived from a common base codeplug.  Records are matched by name, as
+// in Diff.  Changes made on only one side are taken from that side, and
+// changes made on both sides are reported as conflicts.  References to
+// other records are re-resolved by name in the merged codeplug, so they
+// follow records that were renamed or moved.  The merged codeplug is a
+// new codeplug, with the file type and header of ours.
+func Merge(base, ours, theirs *Codeplug) (*Codeplug, []Conflict, error) {
+	cpType := ours.codeplugType
+	if base.codeplugType != cpType || theirs.codeplugType != cpType {
+		return nil, nil, fmt.Errorf("codeplug types differ: %s, %s, %s",
+			base.codeplugType, ours.codeplugType, theirs.codeplugType)
+	}
+
+	m := &merger{
+		cps:     [numSides]*Codeplug{base, ours, theirs},
+		records: make(map[RecordType][]*mergeRecord),
+	}
+	for side, cp := range m.cps {
+		m.ids[side] = make(map[*Record]*mergeRecord)
+		m.byName[side] = make(map[RecordType]map[string]*Record)
+		for _, rType := range cp.RecordTypes() {
+			names := make(map[string]*Record)
+			for _, r := range cp.Records(rType) {
+				if r.NameField() != nil {
+					names[r.Name()] = r
+				}
+			}
+			m.byName[side][rType] = names
+		}
+	}
+
+	// All records must be matched before references can be compared.
+	for _, rType := range ours.RecordTypes() {
+		m.matchRecords(rType)
+	}
+	for _, rType := range ours.RecordTypes() {
+		for _, mr := range m.records[rType] {
+			m.mergeDeletion(mr)
+		}
+	}
+	for _, rType := range ours.RecordTypes() {
+		m.mergeOrder(rType)
+	}
+	for _, rType := range ours.RecordTypes() {
+		m.mergeNames(rType)
+	}
+	for _, rType := range ours.RecordTypes() {
+		for _, mr := range m.records[rType] {
+			m.mergeFields(mr)
+		}
+	}
+
+	merged, err := m.codeplug()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return merged, m.conflicts, nil
+}
+
+// matchRecords creates the merge records of the given type, matching
+// the records of each derived codeplug with those of the base codeplug.
+func (m *merger) matchRecords(rType RecordType) {
+	baseRecords := m.cps[sideBase].Records(rType)
+	for _, r := range baseRecords {
+		mr := &mergeRecord{rType: rType}
+		mr.recs[sideBase] = r
+		m.ids[sideBase][r] = mr
+		m.records[rType] = append(m.records[rType], mr)
+	}
+
+	added := make(map[string]*mergeRecord)
+	for _, side := range []int{sideOurs, sideTheirs} {
+		ms := matchRecords(baseRecords, m.cps[side].Records(rType))
+		for _, rm := range ms {
+			if rm.b == nil {
+				continue
+			}
+			if rm.a != nil {
+				mr := m.ids[sideBase][rm.a]
+				mr.recs[side] = rm.b
+				m.ids[side][rm.b] = mr
+				continue
+			}
+
+			// Records added on both sides with the same name
+			// are the same record.
+			mr := added[rm.b.Name()]
+			if mr == nil || rm.b.NameField() == nil {
+				mr = &mergeRecord{rType: rType}
+				m.records[rType] = append(m.records[rType], mr)
+				if rm.b.NameField() != nil {
+					added[rm.b.Name()] = mr
+				}
+			}
+			mr.recs[side] = rm.b
+			m.ids[side][rm.b] = mr
+		}
+	}
+}
+
+// mergeValues returns the values of the record's fields of the given
+// type, with references to other records replaced by merge records.
+func (m *merger) mergeValues(side int, r *Record, fType FieldType) []mergeValue {
+	fields := fieldsOfType(r, fType)
+	if fields == nil {
+		return nil
+	}
+
+	values := make([]mergeValue, len(fields))
+	for i, f := range fields {
+		s := f.String()
+		values[i] = mergeValue{str: s}
+		if f.listRecordType == "" {
+			continue
+		}
+		if ref := m.byName[side][f.listRecordType][s]; ref != nil {
+			values[i] = mergeValue{ref: m.ids[side][ref]}
+		}
+	}
+
+	return values
+}
+
+// sameValues returns true if the two slices of values are identical.
+func sameValues(a, b []mergeValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// edited returns true if a derived record differs from its base record.
+func (m *merger) edited(mr *mergeRecord, side int) bool {
+	r := mr.recs[side]
+	bRec := mr.recs[sideBase]
+	if r.NameField() != nil && r.Name() != bRec.Name() {
+		return true
+	}
+
+	for _, fType := range unionFieldTypes(bRec, r) {
+		if fType == r.nameFieldType {
+			continue
+		}
+		bValues := m.mergeValues(sideBase, bRec, fType)
+		if !sameValues(m.mergeValues(side, r, fType), bValues) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addConflict records a conflict involving the merged record.
+func (m *merger) addConflict(mr *mergeRecord, fType FieldType, vals [numSides]string, msg string) {
+	name := mr.name
+	if name == "" {
+		for _, r := range mr.recs {
+			if r != nil && r.NameField() != nil {
+				name = r.Name()
+				break
+			}
+		}
+	}
+
+	m.conflicts = append(m.conflicts, Conflict{
+		RecordType: mr.rType,
+		Name:       name,
+		Index:      mr.index,
+		FieldType:  fType,
+		Base:       vals[sideBase],
+		Ours:       vals[sideOurs],
+		Theirs:     vals[sideTheirs],
+		Message:    msg,
+	})
+}
+
+// mergeDeletion decides whether the merged record is deleted.  A record
+// deleted on one side is deleted, unless it was edited on the other.
+func (m *merger) mergeDeletion(mr *mergeRecord) {
+	ours, theirs := mr.recs[sideOurs], mr.recs[sideTheirs]
+	if mr.recs[sideBase] == nil || (ours != nil && theirs != nil) {
+		return
+	}
+
+	switch {
+	case ours == nil && theirs == nil:
+		mr.deleted = true
+
+	case ours == nil && m.edited(mr, sideTheirs):
+		m.addConflict(mr, "", [numSides]string{}, "deleted in ours, edited in theirs")
+
+	case theirs == nil && m.edited(mr, sideOurs):
+		m.addConflict(mr, "", [numSides]string{}, "edited in ours, deleted in theirs")
+
+	default:
+		mr.deleted = true
+	}
+}
+
+// mergeOrder orders the merged records of the given type.
+func (m *merger) mergeOrder(rType RecordType) {
+	var seqs [numSides][]mergeValue
+	for side, cp := range m.cps {
+		for _, r := range cp.Records(rType) {
+			seqs[side] = append(seqs[side], mergeValue{ref: m.ids[side][r]})
+		}
+	}
+
+	keep := func(v mergeValue) bool {
+		return !v.ref.deleted
+	}
+	seq, reordered := mergeSequences(seqs, keep)
+
+	records := make([]*mergeRecord, len(seq))
+	for i, v := range seq {
+		records[i] = v.ref
+		v.ref.index = i
+	}
+	m.records[rType] = records
+
+	if reordered {
+		mr := &mergeRecord{rType: rType, index: -1}
+		m.addConflict(mr, "", [numSides]string{}, "reordered in both ours and theirs")
+	}
+
+	max := m.cps[sideOurs].MaxRecords(rType)
+	if len(records) > max {
+		for _, mr := range records[max:] {
+			mr.deleted = true
+			m.addConflict(mr, "", [numSides]string{}, "too many records, dropped")
+		}
+		m.records[rType] = records[:max]
+	}
+}
+
+// mergeSequences merges the orderings of a sequence of values.  Values
+// are kept if keep returns true for them.  The order is taken from the
+// side that changed it from the base order, and values added by the
+// other side are inserted following the value that preceded them.  If
+// both sides changed the order, ours is used and reordered is true.
+func mergeSequences(seqs [numSides][]mergeValue, keep func(mergeValue) bool) (seq []mergeValue, reordered bool) {
+	ourOrderChanged := orderChanged(seqs[sideBase], seqs[sideOurs])
+	theirOrderChanged := orderChanged(seqs[sideBase], seqs[sideTheirs])
+
+	primary, secondary := seqs[sideOurs], seqs[sideTheirs]
+	if theirOrderChanged && !ourOrderChanged {
+		primary, secondary = secondary, primary
+	}
+	reordered = ourOrderChanged && theirOrderChanged &&
+		orderChanged(seqs[sideOurs], seqs[sideTheirs])
+
+	in := make(map[mergeValue]bool)
+	for _, v := range primary {
+		if keep(v) && !in[v] {
+			seq = append(seq, v)
+			in[v] = true
+		}
+	}
+
+	pos := 0
+	for _, v := range secondary {
+		if in[v] {
+			pos = indexOfValue(seq, v) + 1
+			continue
+		}
+		if !keep(v) {
+			continue
+		}
+		seq = append(seq, mergeValue{})
+		copy(seq[pos+1:], seq[pos:])
+		seq[pos] = v
+		in[v] = true
+		pos++
+	}
+
+	return seq, reordered
+}
+
+// orderChanged returns true if the values common to a and b are in a
+// different order in each.
+func orderChanged(a, b []mergeValue) bool {
+	inA := make(map[mergeValue]bool)
+	for _, v := range a {
+		inA[v] = true
+	}
+	inB := make(map[mergeValue]bool)
+	for _, v := range b {
+		inB[v] = true
+	}
+
+	var commonA, commonB []mergeValue
+	for _, v := range a {
+		if inB[v] {
+			commonA = append(commonA, v)
+		}
+	}
+	for _, v := range b {
+		if inA[v] {
+			commonB = append(commonB, v)
+		}
+	}
+
+	return !sameValues(commonA, commonB)
+}
+
+// indexOfValue returns the index of v in values, or -1.
+func indexOfValue(values []mergeValue, v mergeValue) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// merge3 merges a value changed on either side.  If it was changed
+// differently on both sides, ours is returned and conflict is true.
+func merge3(vals [numSides][]mergeValue, present [numSides]bool) (merged []mergeValue, conflict bool) {
+	switch {
+	case !present[sideOurs]:
+		return vals[sideTheirs], false
+	case !present[sideTheirs]:
+		return vals[sideOurs], false
+	case sameValues(vals[sideOurs], vals[sideTheirs]):
+		return vals[sideOurs], false
+	case present[sideBase] && sameValues(vals[sideOurs], vals[sideBase]):
+		return vals[sideTheirs], false
+	case present[sideBase] && sameValues(vals[sideTheirs], vals[sideBase]):
+		return vals[sideOurs], false
+	}
+
+	return vals[sideOurs], true
+}
+
+// mergeNames merges the names of the records of the given type.
+// Records given the same name on each side are reported as conflicts
+// and the later one is renamed.
+func (m *merger) mergeNames(rType RecordType) {
+	names := make(map[string]bool)
+	for _, mr := range m.records[rType] {
+		var vals [numSides][]mergeValue
+		var present [numSides]bool
+		var strs [numSides]string
+		for side, r := range mr.recs {
+			if r != nil && r.NameField() != nil {
+				strs[side] = r.Name()
+				vals[side] = []mergeValue{{str: strs[side]}}
+				present[side] = true
+			}
+		}
+		if !present[sideOurs] && !present[sideTheirs] {
+			continue
+		}
+
+		name, conflict := merge3(vals, present)
+		mr.name = name[0].str
+		if conflict {
+			m.addConflict(mr, "", strs, "renamed differently in ours and theirs")
+		}
+
+		if names[mr.name] {
+			m.addConflict(mr, "", strs, "name is used by another record")
+			mr.name = uniqueName(mr.name, names, m.nameField(mr).bitSize/16)
+		}
+		names[mr.name] = true
+	}
+}
+
+// nameField returns the name field of one of the merged record's records.
+func (m *merger) nameField(mr *mergeRecord) *Field {
+	for _, r := range mr.recs {
+		if r != nil && r.NameField() != nil {
+			return r.NameField()
+		}
+	}
+
+	return nil
+}
+
+// uniqueName returns a name, based on name, that is not in names,
+// in the same form as Record.makeNameUnique.  If there is no such
+// name, name is returned.
+func uniqueName(name string, names map[string]bool, maxLen int) string {
+	runes := []rune(name)
+	if len(runes) >= maxLen {
+		runes = runes[:maxLen-2]
+	}
+
+	suffixRunes := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	for _, c := range suffixRunes {
+		newName := string(runes) + "." + string(c)
+		if !names[newName] {
+			return newName
+		}
+	}
+
+	return name
+}
+
+// mergeFields merges the values of the merged record's fields.
+func (m *merger) mergeFields(mr *mergeRecord) {
+	mr.values = make(map[FieldType][]mergeValue)
+
+	var fTypes []FieldType
+	for _, r := range mr.recs {
+		if r == nil {
+			continue
+		}
+		for _, fType := range r.FieldTypes() {
+			if fType != r.nameFieldType && (*r.fDesc)[fType] != nil {
+				fTypes = append(fTypes, fType)
+			}
+		}
+	}
+
+	seen := make(map[FieldType]bool)
+	for _, fType := range fTypes {
+		if seen[fType] {
+			continue
+		}
+		seen[fType] = true
+
+		var vals [numSides][]mergeValue
+		var present [numSides]bool
+		var fd *fDesc
+		for side, r := range mr.recs {
+			if r != nil {
+				vals[side] = m.mergeValues(side, r, fType)
+				present[side] = true
+				if (*r.fDesc)[fType] != nil {
+					fd = (*r.fDesc)[fType]
+				}
+			}
+		}
+
+		var merged []mergeValue
+		if fd.max > 1 && fd.listRecordType != "" {
+			merged = m.mergeMembers(mr, fType, vals, present)
+		} else {
+			var conflict bool
+			merged, conflict = merge3(vals, present)
+			if conflict {
+				m.addConflict(mr, fType, valuesStrings(vals),
+					"changed differently in ours and theirs")
+			}
+		}
+
+		mr.values[fType] = m.resolveDeleted(mr, fType, fd, merged)
+	}
+}
+
+// valuesStrings returns the values of each side, joined for display.
+func valuesStrings(vals [numSides][]mergeValue) [numSides]string {
+	var strs [numSides]string
+	for side, values := range vals {
+		s := make([]string, len(values))
+		for i, v := range values {
+			if v.ref != nil {
+				s[i] = v.ref.recs[side].Name()
+			} else {
+				s[i] = v.str
+			}
+		}
+		strs[side] = strings.Join(s, ", ")
+	}
+
+	return strs
+}
+
+// mergeMembers merges a list of references to other records.  Members
+// added on either side are added and members removed on either side
+// are removed.
+func (m *merger) mergeMembers(mr *mergeRecord, fType FieldType, vals [numSides][]mergeValue, present [numSides]bool) []mergeValue {
+	if !present[sideOurs] || !present[sideTheirs] {
+		merged, _ := merge3(vals, present)
+		return merged
+	}
+
+	in := [numSides]map[mergeValue]bool{}
+	for side, values := range vals {
+		in[side] = make(map[mergeValue]bool)
+		for _, v := range values {
+			in[side][v] = true
+		}
+	}
+	keep := func(v mergeValue) bool {
+		if in[sideBase][v] {
+			return in[sideOurs][v] && in[sideTheirs][v]
+		}
+		return true
+	}
+
+	merged, reordered := mergeSequences(vals, keep)
+	if reordered {
+		m.addConflict(mr, fType, valuesStrings(vals),
+			"reordered differently in ours and theirs")
+	}
+
+	max := (*mr.recs[sideOurs].fDesc)[fType].max
+	if len(merged) > max {
+		m.addConflict(mr, fType, valuesStrings(vals), "too many members, list truncated")
+		merged = merged[:max]
+	}
+
+	return merged
+}
+
+// resolveDeleted removes references to deleted records from the merged
+// values.  A single reference is replaced by the field's first indexed
+// string, typically "None".
+func (m *merger) resolveDeleted(mr *mergeRecord, fType FieldType, fd *fDesc, values []mergeValue) []mergeValue {
+	var resolved []mergeValue
+	for _, v := range values {
+		if v.ref == nil || !v.ref.deleted {
+			resolved = append(resolved, v)
+			continue
+		}
+
+		var vals [numSides]string
+		for side, r := range v.ref.recs {
+			if r != nil && r.NameField() != nil {
+				vals[side] = r.Name()
+			}
+		}
+		if fd.max > 1 || fd.indexedStrings == nil || len(*fd.indexedStrings) == 0 {
+			m.addConflict(mr, fType, vals, "refers to a deleted record, removed")
+			continue
+		}
+		str := (*fd.indexedStrings)[0].String
+		m.addConflict(mr, fType, vals, "refers to a deleted record, set to "+str)
+		resolved = append(resolved, mergeValue{str: str})
+	}
+
+	return resolved
+}
+
+// codeplug returns the merged codeplug.  It is built by importing the
+// text form of the merged records into a copy of ours, so that
+// references are resolved by name.
+func (m *merger) codeplug() (*Codeplug, error) {
+	ours := m.cps[sideOurs]
+	cp, err := NewCodeplugFromBytes(ours.fileBytes(), ours.codeplugType)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, rType := range ours.RecordTypes() {
+		for _, mr := range m.records[rType] {
+			m.printRecord(&buf, mr)
+		}
+	}
+
+	if err := cp.Import(&buf); err != nil {
+		cp.Free()
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// printRecord writes the merged record in the text form read by Import.
+func (m *merger) printRecord(buf *bytes.Buffer, mr *mergeRecord) {
+	var r *Record
+	for _, side := range []int{sideOurs, sideTheirs, sideBase} {
+		if mr.recs[side] != nil {
+			r = mr.recs[side]
+			break
+		}
+	}
+
+	ind := ""
+	if r.max > 1 {
+		ind = fmt.Sprintf("[%d]", mr.index+1)
+	}
+	fmt.Fprintf(buf, "%s%s:\n", string(mr.rType), ind)
+
+	if r.NameField() != nil {
+		fmt.Fprintf(buf, "\t%s: %s\n", string(r.nameFieldType), quoteString(mr.name))
+	}
+
+	fTypes := make([]string, 0, len(mr.values))
+	for fType := range mr.values {
+		fTypes = append(fTypes, string(fType))
+	}
+	sort.Strings(fTypes)
+
+	for _, name := range fTypes {
+		fType := FieldType(name)
+		fd := (*r.fDesc)[fType]
+		for i, v := range mr.values[fType] {
+			ind := ""
+			if fd.max > 1 {
+				ind = fmt.Sprintf("[%d]", i+1)
+			}
+			fmt.Fprintf(buf, "\t%s%s: %s\n", name, ind, quoteString(v.String()))
+		}
+	}
+
+	if u := mr.unknownRecord(r); u.hasUnknownBits() {
+		fmt.Fprintf(buf, "\t%s: %s\n", unknownBytesName, u.unknownString())
+	}
+	fmt.Fprintln(buf)
+}
+
+// unknownRecord returns the record whose unknown bytes the merged record
+// keeps: theirs, if only they changed the bytes from the base, and
+// otherwise r.
+func (mr *mergeRecord) unknownRecord(r *Record) *Record {
+	base, ours, theirs := mr.recs[sideBase], mr.recs[sideOurs], mr.recs[sideTheirs]
+	if base == nil || ours == nil || theirs == nil {
+		return r
+	}
+
+	if bytes.Equal(ours.unknown, base.unknown) {
+		return theirs
+	}
+
+	return ours
+}
@@ -0,0 +1,223 @@
+package codeplug
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// setUnknownBits sets the unknown bits of the first byte of the
+// record that has any.
+func setUnknownBits(t *testing.T, r *Record) {
+	t.Helper()
+
+	for i, mask := range r.unknownMask {
+		if mask != 0 {
+			r.unknown[i] |= mask
+			return
+		}
+	}
+	t.Fatalf("%s has no unknown bits", r.rType)
+}
+
+func TestMergeUnknownBytes(t *testing.T) {
+	image := blankImage(t, CtMd380)
+	var cps []*Codeplug
+	for i := 0; i < 3; i++ {
+		cp, err := NewCodeplugFromBytes(image, CtMd380)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cp.Free()
+		cps = append(cps, cp)
+	}
+	base, ours, theirs := cps[0], cps[1], cps[2]
+
+	err := ours.SetFields(ours.Records(RtChannelInformation)[:1], FtChannelName, "Ours")
+	if err != nil {
+		t.Fatal(err)
+	}
+	setUnknownBits(t, theirs.Records(RtChannelInformation)[0])
+
+	merged, conflicts, err := Merge(base, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer merged.Free()
+	if len(conflicts) != 0 {
+		t.Errorf("%d conflicts", len(conflicts))
+	}
+
+	r := merged.Records(RtChannelInformation)[0]
+	if r.Name() != "Ours" {
+		t.Errorf("merged channel is named %q", r.Name())
+	}
+	want := theirs.Records(RtChannelInformation)[0].UnknownBytes()
+	if !bytes.Equal(r.UnknownBytes(), want) {
+		t.Errorf("merged channel's unknown bytes are not theirs")
+	}
+}
+
+// channelNames returns the names of the codeplug's channels.
+func channelNames(cp *Codeplug) []string {
+	var names []string
+	for _, r := range cp.Records(RtChannelInformation) {
+		names = append(names, r.Name())
+	}
+
+	return names
+}
+
+func TestMerge(t *testing.T) {
+	noEdit := func(t *testing.T, cp *Codeplug) {}
+
+	tests := []struct {
+		name       string
+		ours       func(t *testing.T, cp *Codeplug)
+		theirs     func(t *testing.T, cp *Codeplug)
+		channels   []string
+		members    []string
+		colorCodes map[string]string
+		conflicts  []string
+	}{
+		{
+			name: "clean",
+			ours: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtChannelName, "Deux")
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Three", FtColorCode, "5")
+				addRecords(t, cp, RtChannelInformation, "Five")
+			},
+			channels:   []string{"Channel 1", "Deux", "Three", "Four", "Five"},
+			members:    []string{"Channel 1", "Deux", "Three"},
+			colorCodes: map[string]string{"Deux": "0", "Three": "5"},
+		},
+		{
+			name: "edit/edit",
+			ours: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtColorCode, "3")
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtColorCode, "4")
+			},
+			channels:   []string{"Channel 1", "Two", "Three", "Four"},
+			members:    []string{"Channel 1", "Two", "Three"},
+			colorCodes: map[string]string{"Two": "3"},
+			conflicts: []string{
+				`ChannelInformation Two ColorCode: changed differently in ours and theirs`,
+			},
+		},
+		{
+			name: "delete/edit",
+			ours: func(t *testing.T, cp *Codeplug) {
+				cp.RemoveRecord(cp.FindRecordByName(RtChannelInformation, "Four"))
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Four", FtColorCode, "7")
+			},
+			channels:   []string{"Channel 1", "Two", "Three", "Four"},
+			members:    []string{"Channel 1", "Two", "Three"},
+			colorCodes: map[string]string{"Four": "7"},
+			conflicts: []string{
+				`ChannelInformation Four: deleted in ours, edited in theirs`,
+			},
+		},
+		{
+			name: "delete/unedited",
+			ours: noEdit,
+			theirs: func(t *testing.T, cp *Codeplug) {
+				cp.RemoveRecord(cp.FindRecordByName(RtChannelInformation, "Four"))
+			},
+			channels: []string{"Channel 1", "Two", "Three"},
+			members:  []string{"Channel 1", "Two", "Three"},
+		},
+		{
+			name: "reference to deleted",
+			ours: func(t *testing.T, cp *Codeplug) {
+				cp.RemoveRecord(cp.FindRecordByName(RtChannelInformation, "Four"))
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				zone := cp.Records(RtZoneInformation)[0]
+				setListFields(zone, FtChannelMember,
+					[]string{"Channel 1", "Two", "Three", "Four"})
+			},
+			channels: []string{"Channel 1", "Two", "Three"},
+			members:  []string{"Channel 1", "Two", "Three"},
+			conflicts: []string{
+				`ZoneInformation "Zone 1" ChannelMember: refers to a deleted record, removed`,
+			},
+		},
+		{
+			name: "name collision",
+			ours: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Two", FtChannelName, "Same")
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				setField(t, cp, RtChannelInformation, "Three", FtChannelName, "Same")
+			},
+			channels: []string{"Channel 1", "Same", "Same.a", "Four"},
+			members:  []string{"Channel 1", "Same", "Same.a"},
+			conflicts: []string{
+				`ChannelInformation Same: name is used by another record`,
+			},
+		},
+		{
+			name: "member lists",
+			ours: func(t *testing.T, cp *Codeplug) {
+				zone := cp.Records(RtZoneInformation)[0]
+				setListFields(zone, FtChannelMember, []string{"Channel 1", "Three"})
+			},
+			theirs: func(t *testing.T, cp *Codeplug) {
+				zone := cp.Records(RtZoneInformation)[0]
+				setListFields(zone, FtChannelMember,
+					[]string{"Channel 1", "Two", "Four", "Three"})
+			},
+			channels: []string{"Channel 1", "Two", "Three", "Four"},
+			members:  []string{"Channel 1", "Four", "Three"},
+		},
+	}
+
+	for _, test := range tests {
+		base := diffCodeplug(t)
+		ours := copyCodeplug(t, base)
+		theirs := copyCodeplug(t, base)
+		test.ours(t, ours)
+		test.theirs(t, theirs)
+
+		merged, conflicts, err := Merge(base, ours, theirs)
+		if err != nil {
+			t.Fatalf("%s: %s", test.name, err)
+		}
+
+		var strs []string
+		for _, c := range conflicts {
+			strs = append(strs, c.String())
+		}
+		if !reflect.DeepEqual(strs, test.conflicts) {
+			t.Errorf("%s: conflicts are %q, not %q", test.name, strs, test.conflicts)
+		}
+		if names := channelNames(merged); !reflect.DeepEqual(names, test.channels) {
+			t.Errorf("%s: channels are %q, not %q", test.name, names, test.channels)
+		}
+		zone := merged.Records(RtZoneInformation)[0]
+		if members := zoneMembers(zone); !reflect.DeepEqual(members, test.members) {
+			t.Errorf("%s: zone members are %q, not %q", test.name, members, test.members)
+		}
+		for name, want := range test.colorCodes {
+			r := merged.FindRecordByName(RtChannelInformation, name)
+			if r == nil {
+				t.Errorf("%s: no channel %s", test.name, name)
+				continue
+			}
+			if cc := r.Field(FtColorCode).String(); cc != want {
+				t.Errorf("%s: %s's color code is %s, not %s", test.name, name, cc, want)
+			}
+		}
+
+		base.Free()
+		ours.Free()
+		theirs.Free()
+		merged.Free()
+	}
+}
@@ -0,0 +1,544 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A Predicate selects records.  A predicate on a field type selects a
+// record if any of the record's fields of that type satisfy it.
+type Predicate func(r *Record) bool
+
+// FindRecords returns the codeplug's records of the given type that are
+// selected by pred.
+func (cp *Codeplug) FindRecords(rType RecordType, pred Predicate) []*Record {
+	var records []*Record
+	for _, r := range cp.rDesc[rType].records {
+		if pred(r) {
+			records = append(records, r)
+		}
+	}
+
+	return records
+}
+
+// anyField returns a predicate selecting records having a field of the
+// given type for which match returns true.
+func anyField(fType FieldType, match func(f *Field) bool) Predicate {
+	return func(r *Record) bool {
+		fd := (*r.fDesc)[fType]
+		if fd == nil {
+			return false
+		}
+		for _, f := range fd.fields {
+			if match(f) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FieldEquals returns a predicate selecting records having a field of
+// the given type whose value is the given string.  Strings are compared
+// without regard to case, and numbers by value, so that 146.52 equals
+// 146.52000.
+func FieldEquals(fType FieldType, value string) Predicate {
+	number, err := strconv.ParseFloat(value, 64)
+	isNumber := err == nil
+
+	return anyField(fType, func(f *Field) bool {
+		s := f.String()
+		if isNumber {
+			n, err := strconv.ParseFloat(s, 64)
+			if err == nil {
+				return n == number
+			}
+		}
+		return strings.EqualFold(s, value)
+	})
+}
+
+// FieldMatches returns a predicate selecting records having a field of
+// the given type whose value matches re.
+func FieldMatches(fType FieldType, re *regexp.Regexp) Predicate {
+	return anyField(fType, func(f *Field) bool {
+		return re.MatchString(f.String())
+	})
+}
+
+// FieldInRange returns a predicate selecting records having a field of
+// the given type whose value is a number, such as a frequency or span,
+// from low to high inclusive.
+func FieldInRange(fType FieldType, low, high float64) Predicate {
+	return fieldCompare(fType, func(n float64) bool {
+		return n >= low && n <= high
+	})
+}
+
+// fieldCompare returns a predicate selecting records having a field of
+// the given type whose value is a number for which cmp returns true.
+func fieldCompare(fType FieldType, cmp func(float64) bool) Predicate {
+	return anyField(fType, func(f *Field) bool {
+		n, err := strconv.ParseFloat(f.String(), 64)
+		return err == nil && cmp(n)
+	})
+}
+
+// NameMatches returns a predicate selecting records whose name matches re.
+func NameMatches(re *regexp.Regexp) Predicate {
+	return func(r *Record) bool {
+		return r.NameField() != nil && re.MatchString(r.Name())
+	}
+}
+
+// MemberOf returns a predicate selecting records that are members of the
+// given list record, such as a zone, scan list or group list.
+func MemberOf(list *Record) Predicate {
+	return func(r *Record) bool {
+		for _, fType := range list.FieldTypes() {
+			for _, f := range list.Fields(fType) {
+				if f.max <= 1 || f.listRecordType != r.rType {
+					continue
+				}
+				index, ok := f.referenceIndex()
+				if ok && index == r.rIndex+1 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// And returns a predicate selecting records selected by all of preds.
+func And(preds ...Predicate) Predicate {
+	return func(r *Record) bool {
+		for _, pred := range preds {
+			if !pred(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate selecting records selected by any of preds.
+func Or(preds ...Predicate) Predicate {
+	return func(r *Record) bool {
+		for _, pred := range preds {
+			if pred(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate selecting records not selected by pred.
+func Not(pred Predicate) Predicate {
+	return func(r *Record) bool {
+		return !pred(r)
+	}
+}
+
+// ParseQuery returns the predicate described by a query on records of
+// the given type.  A query is made of terms combined with "and", "or",
+// "not" and parentheses.  The terms are:
+//
+//	<field> = <value>	the field has the value, see FieldEquals
+//	<field> != <value>	the field doesn't have the value
+//	<field> ~ <regexp>	the field's value matches the regular expression
+//	<field> !~ <regexp>	the field's value doesn't match it
+//	<field> < <number>	also <=, > and >=, for numeric fields
+//	<field> in <low>..<high>	see FieldInRange
+//	in <recordtype> <name>	the record is a member of the named list
+//
+// Fields are named by their field types, as in Export, and the record's
+// name field may also be named "Name".  Values containing spaces or
+// operator characters must be quoted with double quotes.  For example:
+//
+//	ChannelMode = Digital and RepeaterSlot = 2 and ColorCode = 1
+//	not TxFrequency in 144..148 and not in ScanList "Scan 1"
+func (cp *Codeplug) ParseQuery(rType RecordType, query string) (Predicate, error) {
+	if cp.rDesc[rType] == nil {
+		return nil, fmt.Errorf("unknown record type: %s", rType)
+	}
+
+	tokens, err := queryTokens(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+
+	p := &queryParser{cp: cp, rType: rType, tokens: tokens}
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %s", p.peek())
+	}
+
+	return pred, nil
+}
+
+// A queryToken is a word, quoted string or operator of a query.
+type queryToken struct {
+	str    string
+	quoted bool
+}
+
+// String returns the token as it appears in a query.
+func (t queryToken) String() string {
+	if t.quoted {
+		return strconv.Quote(t.str)
+	}
+	return t.str
+}
+
+// is returns true if the token is the given operator or keyword.
+func (t queryToken) is(str string) bool {
+	return !t.quoted && strings.EqualFold(t.str, str)
+}
+
+const queryOperatorChars = "()=!~<>"
+
+// queryTokens splits a query into its tokens.
+func queryTokens(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string: %s", string(runes[i:]))
+			}
+			str, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return nil, fmt.Errorf("bad string: %s", string(runes[i:j+1]))
+			}
+			tokens = append(tokens, queryToken{str, true})
+			i = j + 1
+
+		case strings.ContainsRune(queryOperatorChars, c):
+			str := string(c)
+			if i+1 < len(runes) && strings.ContainsRune("!<>", c) &&
+				strings.ContainsRune("=~", runes[i+1]) {
+				str += string(runes[i+1])
+			}
+			if str == "!" || str == "<~" || str == ">~" {
+				return nil, fmt.Errorf("bad operator: %s", str)
+			}
+			tokens = append(tokens, queryToken{str, false})
+			i += len(str)
+
+		default:
+			j := i
+			for ; j < len(runes); j++ {
+				c := runes[j]
+				if unicode.IsSpace(c) || c == '"' ||
+					strings.ContainsRune(queryOperatorChars, c) {
+					break
+				}
+			}
+			tokens = append(tokens, queryToken{string(runes[i:j]), false})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+// A queryParser parses a query's tokens into a predicate.
+type queryParser struct {
+	cp     *Codeplug
+	rType  RecordType
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek returns the next token, without consuming it.
+func (p *queryParser) peek() queryToken {
+	if p.done() {
+		return queryToken{str: "end of query"}
+	}
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token.
+func (p *queryParser) next() (queryToken, error) {
+	if p.done() {
+		return queryToken{}, fmt.Errorf("unexpected end of query")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+// accept consumes the next token if it is the given operator or keyword.
+func (p *queryParser) accept(str string) bool {
+	if !p.done() && p.peek().is(str) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (Predicate, error) {
+	pred, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []Predicate{pred}
+	for p.accept("or") {
+		pred, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+
+	return Or(preds...), nil
+}
+
+func (p *queryParser) parseAnd() (Predicate, error) {
+	pred, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	preds := []Predicate{pred}
+	for p.accept("and") {
+		pred, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+
+	return And(preds...), nil
+}
+
+func (p *queryParser) parseUnary() (Predicate, error) {
+	switch {
+	case p.accept("not"):
+		pred, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(pred), nil
+
+	case p.accept("("):
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected ) but found %s", p.peek())
+		}
+		return pred, nil
+
+	case p.accept("in"):
+		return p.parseMembership()
+	}
+
+	return p.parseTerm()
+}
+
+// parseMembership parses the list record type and name following "in".
+func (p *queryParser) parseMembership() (Predicate, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	rType, ok := p.cp.nameToRt[t.str]
+	if !ok || p.cp.rDesc[rType] == nil {
+		return nil, fmt.Errorf("unknown record type: %s", t.str)
+	}
+
+	t, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	list := p.cp.FindRecordByName(rType, t.str)
+	if list == nil {
+		return nil, fmt.Errorf("no %s named %s", rType, t)
+	}
+
+	return MemberOf(list), nil
+}
+
+// parseTerm parses a field name, operator and value.
+func (p *queryParser) parseTerm() (Predicate, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	fType, err := p.fieldType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.quoted {
+		return nil, fmt.Errorf("expected operator but found %s", op)
+	}
+
+	v, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value := v.str
+
+	switch strings.ToLower(op.str) {
+	case "=":
+		return FieldEquals(fType, value), nil
+
+	case "!=":
+		return Not(FieldEquals(fType, value)), nil
+
+	case "~", "!~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		if op.str == "!~" {
+			return Not(FieldMatches(fType, re)), nil
+		}
+		return FieldMatches(fType, re), nil
+
+	case "in":
+		bounds := strings.Split(value, "..")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("bad range: %s", value)
+		}
+		low, err := queryNumber(bounds[0], math.Inf(-1))
+		if err != nil {
+			return nil, err
+		}
+		high, err := queryNumber(bounds[1], math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		return FieldInRange(fType, low, high), nil
+
+	case "<", "<=", ">", ">=":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number: %s", value)
+		}
+		cmps := map[string]func(float64) bool{
+			"<":  func(f float64) bool { return f < n },
+			"<=": func(f float64) bool { return f <= n },
+			">":  func(f float64) bool { return f > n },
+			">=": func(f float64) bool { return f >= n },
+		}
+		return fieldCompare(fType, cmps[op.str]), nil
+	}
+
+	return nil, fmt.Errorf("unknown operator: %s", op)
+}
+
+// fieldType returns the field type of the query's record type named by
+// the token.  Field names are matched without regard to case, unless
+// several names differ only by case, when the name must match exactly.
+func (p *queryParser) fieldType(t queryToken) (FieldType, error) {
+	if t.quoted || strings.ContainsAny(t.str, queryOperatorChars) {
+		return "", fmt.Errorf("expected field name but found %s", t)
+	}
+
+	nameToFt := p.cp.nameToFt[p.rType]
+	if fType, ok := nameToFt[t.str]; ok {
+		return fType, nil
+	}
+
+	var fTypes []FieldType
+	for name, fType := range nameToFt {
+		if strings.EqualFold(name, t.str) {
+			fTypes = append(fTypes, fType)
+		}
+	}
+	switch len(fTypes) {
+	case 1:
+		return fTypes[0], nil
+	case 0:
+	default:
+		return "", fmt.Errorf("ambiguous field name: %s", t.str)
+	}
+
+	nameType := p.cp.rDesc[p.rType].nameFieldType
+	if strings.EqualFold(t.str, "name") && nameType != "" {
+		return nameType, nil
+	}
+
+	return "", fmt.Errorf("%s has no field %s", p.rType, t.str)
+}
+
+// queryNumber returns the number in s, or dflt if s is empty.
+func queryNumber(s string, dflt float64) (float64, error) {
+	if s == "" {
+		return dflt, nil
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad number: %s", s)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,158 @@
+package codeplug
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// queryCodeplug returns the codeplug of diffCodeplug, with channel
+// frequencies and color codes set.
+func queryCodeplug(t *testing.T) *Codeplug {
+	t.Helper()
+
+	cp := diffCodeplug(t)
+	values := []struct {
+		name      string
+		frequency string
+		colorCode string
+	}{
+		{"Channel 1", "146.52", "0"},
+		{"Two", "145.5", "1"},
+		{"Three", "162.55", "2"},
+		{"Four", "170", "1"},
+	}
+	for _, v := range values {
+		setField(t, cp, RtChannelInformation, v.name, FtRxFrequency, v.frequency)
+		setField(t, cp, RtChannelInformation, v.name, FtColorCode, v.colorCode)
+	}
+
+	return cp
+}
+
+func TestParseQuery(t *testing.T) {
+	cp := queryCodeplug(t)
+	defer cp.Free()
+
+	tests := []struct {
+		query string
+		names []string
+	}{
+		{`Name = two`, []string{"Two"}},
+		{`name = Two`, []string{"Two"}},
+		{`Name = "Channel 1"`, []string{"Channel 1"}},
+		{`ChannelName != Two`, []string{"Channel 1", "Three", "Four"}},
+		{`Name ~ ^T`, []string{"Two", "Three"}},
+		{`Name !~ e`, []string{"Two", "Four"}},
+		{`RxFrequency = 146.520`, []string{"Channel 1"}},
+		{`RxFrequency in 145..147`, []string{"Channel 1", "Two"}},
+		{`RxFrequency in ..146`, []string{"Two"}},
+		{`RxFrequency in 160..`, []string{"Three", "Four"}},
+		{`RxFrequency in ..`, []string{"Channel 1", "Two", "Three", "Four"}},
+		{`RxFrequency < 146.52`, []string{"Two"}},
+		{`RxFrequency <= 146.52`, []string{"Channel 1", "Two"}},
+		{`RxFrequency > 146.52`, []string{"Three", "Four"}},
+		{`RxFrequency>=146.52`, []string{"Channel 1", "Three", "Four"}},
+		{`Name = Two or Name = Three and ColorCode = 1`, []string{"Two"}},
+		{`(Name = Two or Name = Three) and ColorCode = 2`, []string{"Three"}},
+		{`ColorCode = 1 AND NOT Name = Two`, []string{"Four"}},
+		{`not Name = Two and not Name = Four`, []string{"Channel 1", "Three"}},
+		{`not (Name = Two or Name = Four)`, []string{"Channel 1", "Three"}},
+		{`in ZoneInformation "Zone 1"`, []string{"Channel 1", "Two", "Three"}},
+		{`not in ZoneInformation "Zone 1"`, []string{"Four"}},
+		{`in ZoneInformation "Zone 1" and RxFrequency > 150`, []string{"Three"}},
+	}
+
+	for _, test := range tests {
+		pred, err := cp.ParseQuery(RtChannelInformation, test.query)
+		if err != nil {
+			t.Errorf("%s: %s", test.query, err)
+			continue
+		}
+
+		var names []string
+		for _, r := range cp.FindRecords(RtChannelInformation, pred) {
+			names = append(names, r.Name())
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%s: found %q, not %q", test.query, names, test.names)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	cp := queryCodeplug(t)
+	defer cp.Free()
+
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{``, "empty query"},
+		{`Name`, "unexpected end of query"},
+		{`Name =`, "unexpected end of query"},
+		{`Name = Two Three`, "unexpected Three"},
+		{`Name = "Two`, "unterminated string"},
+		{`Name ! Two`, "bad operator: !"},
+		{`Name Two Three`, "unknown operator: Two"},
+		{`Name ~ "("`, "missing closing )"},
+		{`"Name" = Two`, "expected field name"},
+		{`Bogus = 1`, "ChannelInformation has no field Bogus"},
+		{`RxFrequency in 146`, "bad range: 146"},
+		{`RxFrequency in a..b`, "bad number: a"},
+		{`RxFrequency < low`, "bad number: low"},
+		{`(Name = Two`, "expected ) but found end of query"},
+		{`in Bogus Two`, "unknown record type: Bogus"},
+		{`in ZoneInformation Nowhere`, "no ZoneInformation named Nowhere"},
+	}
+
+	for _, test := range tests {
+		_, err := cp.ParseQuery(RtChannelInformation, test.query)
+		if err == nil {
+			t.Errorf("%s: no error", test.query)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error is %q, not %q", test.query, err, test.err)
+		}
+	}
+
+	if _, err := cp.ParseQuery("Bogus", "Name = Two"); err == nil {
+		t.Errorf("unknown record type: no error")
+	}
+}
+
+func TestParseQueryFieldCase(t *testing.T) {
+	cp := queryCodeplug(t)
+	defer cp.Free()
+
+	// A field name that differs from another only by case.
+	cp.nameToFt[RtChannelInformation]["RXFREQUENCY"] = FtColorCode
+
+	tests := []struct {
+		query string
+		names []string
+	}{
+		{`RxFrequency < 146`, []string{"Two"}},
+		{`RXFREQUENCY < 1`, []string{"Channel 1"}},
+	}
+	for _, test := range tests {
+		pred, err := cp.ParseQuery(RtChannelInformation, test.query)
+		if err != nil {
+			t.Errorf("%s: %s", test.query, err)
+			continue
+		}
+		var names []string
+		for _, r := range cp.FindRecords(RtChannelInformation, pred) {
+			names = append(names, r.Name())
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%s: found %q, not %q", test.query, names, test.names)
+		}
+	}
+
+	_, err := cp.ParseQuery(RtChannelInformation, "rxfrequency < 1")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous field name") {
+		t.Errorf("rxfrequency: error is %v", err)
+	}
+}
@@ -26,6 +26,7 @@
 package codeplug
 
 import (
+	"encoding/hex"
 	"fmt"
 	"log"
 	"sort"
@@ -34,8 +35,9 @@ import (
 // A Record represents a record within a Codeplug.
 type Record struct {
 	*rDesc
-	fDesc  *map[FieldType]*fDesc
-	rIndex int
+	fDesc   *map[FieldType]*fDesc
+	rIndex  int
+	unknown []byte
 }
 
 // An rDesc contains a record type's dynamic information.
@@ -56,6 +58,7 @@ type rInfo struct {
 	delDescs      []delDesc
 	fInfos        []fInfo
 	nameFieldType FieldType
+	unknownMask   []byte
 }
 
 // A RecordType represents a record's type
@@ -116,6 +119,21 @@ func (rd *rDesc) loadRecords(cpBytes []byte) {
 	rd.records = records[:length]
 }
 
+// reloadRecords reloads the records of rd from cpBytes, which holds
+// the records in their slots, as placed there by store.  Each record
+// is reloaded into the Record that was stored into its slot, so that
+// changes referring to records in unchanged slots remain valid.
+func (rd *rDesc) reloadRecords(cpBytes []byte) {
+	records := make([]*Record, rd.max)
+	copy(records, rd.records)
+	rd.records = records
+	rd.loadRecords(cpBytes)
+
+	for i, r := range rd.records {
+		r.rIndex = i
+	}
+}
+
 // newField creates and returns the address of a new field of the given type.
 func (r *Record) NewField(fType FieldType) *Field {
 	f := new(Field)
@@ -134,7 +152,7 @@ func (r *Record) NewField(fType FieldType) *Field {
 // addField adds the given field to the record.
 func (r *Record) addField(f *Field) error {
 	if len(f.fields) >= f.max {
-		return fmt.Errorf("too many fields: ", string(f.fType))
+		return fmt.Errorf("too many fields: %s", string(f.fType))
 	}
 
 	fd := (*r.fDesc)[f.fType]
@@ -144,32 +162,105 @@ func (r *Record) addField(f *Field) error {
 	return nil
 }
 
+// unknownBytesName is the name given to a record's unknown bytes in
+// its text, JSON and YAML forms.
+const unknownBytesName = "UnknownBytes"
+
+// UnknownBytes returns the record's bytes, with the bits described by
+// its fields cleared.  The remaining bits, whose meaning is unknown,
+// are kept with the record, so they are written wherever the record
+// is stored, and are preserved by Export and Import.  UnknownBytes
+// returns nil if the record wasn't loaded from a codeplug or from text,
+// or if all of its bits are described.  Text omits a record's unknown
+// bytes when they are all zero.
+func (r *Record) UnknownBytes() []byte {
+	if r.unknown == nil {
+		return nil
+	}
+
+	return append([]byte{}, r.unknown...)
+}
+
+// hasUnknownBits returns true if any of the record's unknown bits is set.
+func (r *Record) hasUnknownBits() bool {
+	for _, b := range r.unknown {
+		if b != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clearUnknown zeroes the unknown bits of a record read from text, so
+// that a record whose unknown bytes were omitted is stored with them
+// zero.
+func (r *Record) clearUnknown() {
+	if r.unknownMask != nil {
+		r.unknown = make([]byte, r.size)
+	}
+}
+
+// unknownString returns the record's unknown bytes in hexadecimal.
+func (r *Record) unknownString() string {
+	return hex.EncodeToString(r.unknown)
+}
+
+// setUnknownString sets the record's unknown bytes from a hexadecimal
+// string of the record's size.  Described bits in the string are
+// ignored.
+func (r *Record) setUnknownString(str string) error {
+	if r.unknownMask == nil {
+		return nil
+	}
+
+	bytes, err := hex.DecodeString(str)
+	if err != nil || len(bytes) != r.size {
+		return fmt.Errorf("%s must be %d hexadecimal bytes",
+			unknownBytesName, r.size)
+	}
+
+	for i := range bytes {
+		bytes[i] &= r.unknownMask[i]
+	}
+	r.unknown = bytes
+
+	return nil
+}
+
 // load replaces the record's contents with those corresponding
 // to the byte slice.
 func (r *Record) load(recordBytes []byte) {
 	ri := r.rDesc.rInfo
 
+	r.unknown = nil
+	if ri.unknownMask != nil {
+		r.unknown = make([]byte, ri.size)
+		for i, mask := range ri.unknownMask {
+			r.unknown[i] = recordBytes[i] & mask
+		}
+	}
+
+	// A record that is reloaded keeps its fDescs and fields, so
+	// that changes referring to them remain valid.
 	for i := range ri.fInfos {
 		fi := &ri.fInfos[i]
-		if fi.max == 0 {
-			fi.max = 1
+		if (*r.fDesc)[fi.fType] != nil {
+			continue
 		}
-		fi.rInfo = ri
 		fd := &fDesc{fInfo: fi}
 		(*r.fDesc)[fi.fType] = fd
-		if fi.valueType == VtName {
-			ri.nameFieldType = fi.fType
-		}
 		fd.record = r
 	}
 
 	for _, fd := range *r.fDesc {
 		fi := fd.fInfo
 		fields := fd.fields
-		if len(fields) > 0 {
-			fields = fields[0:cap(fields)]
+		if cap(fields) >= fi.max {
+			fields = fields[0:fi.max]
 		} else {
 			fields = make([]*Field, fi.max)
+			copy(fields, fd.fields)
 		}
 
 		length := 0
@@ -186,16 +277,6 @@ func (r *Record) load(recordBytes []byte) {
 
 				f.load(recordBytes)
 
-				span := f.span
-				if span != nil {
-					if span.scale == 0 {
-						span.scale = 1
-					}
-					if span.interval == 0 {
-						span.interval = 1
-					}
-				}
-
 				fields[length] = f
 				length++
 			}
@@ -205,26 +286,15 @@ func (r *Record) load(recordBytes []byte) {
 	}
 }
 
-// valid returns nil if all fields in the record are valid.
-func (r *Record) valid() error {
-	errStr := ""
-	for _, fType := range r.FieldTypes() {
-		for _, f := range r.Fields(fType) {
-			if err := f.valid(); err != nil {
-				errStr += f.FullTypeName() + ": " + err.Error() + "\n"
-			}
+// stores stores all all fields of the record, and its unknown bytes,
+// into the given byte slice.
+func (r *Record) store(recordBytes []byte) {
+	if r.unknown != nil {
+		for i, mask := range r.unknownMask {
+			recordBytes[i] = recordBytes[i]&^mask | r.unknown[i]
 		}
 	}
 
-	if errStr != "" {
-		return fmt.Errorf("%s", errStr)
-	}
-
-	return nil
-}
-
-// stores stores all all fields of the record into the given byte slice.
-func (r *Record) store(recordBytes []byte) {
 	for _, fd := range *r.fDesc {
 		for fIndex := 0; fIndex < fd.max; fIndex++ {
 			if fIndex < len(fd.fields) {
@@ -409,7 +479,7 @@ func (rd *rDesc) deleteRecord(rIndex int, cpBytes []byte) {
 
 func (r *Record) nameToField(name string, index int, value string) (*Field, error) {
 	rType := r.rType
-	fType, ok := nameToFt[rType][name]
+	fType, ok := r.codeplug.nameToFt[rType][name]
 	if !ok {
 		return nil, fmt.Errorf("bad field name: %s", name)
 	}
@@ -503,6 +573,9 @@ func (r *Record) RemoveField(f *Field) {
 
 func (r *Record) Copy() *Record {
 	copy := *r
+	if r.unknown != nil {
+		copy.unknown = append([]byte{}, r.unknown...)
+	}
 
 	fDesc := make(map[FieldType]*fDesc)
 	copy.fDesc = &fDesc
@@ -529,6 +602,15 @@ func recordNames(records []*Record) []string {
 	return names
 }
 
+func fieldStrings(fields []*Field) []string {
+	strs := make([]string, len(fields))
+	for i, f := range fields {
+		strs[i] = f.String()
+	}
+
+	return strs
+}
+
 func (r *Record) FindFieldByName(fType FieldType, name string) *Field {
 	allFields := (*r.fDesc)[fType].fields
 	for _, f := range allFields {
@@ -539,6 +621,18 @@ func (r *Record) FindFieldByName(fType FieldType, name string) *Field {
 	return nil
 }
 
+// inCodeplug returns true if the record is one of the codeplug's records.
+func (r *Record) inCodeplug() bool {
+	records := r.codeplug.rDesc[r.rType].records
+	return r.rIndex < len(records) && records[r.rIndex] == r
+}
+
+// inRecord returns true if the field is one of its record's fields.
+func (f *Field) inRecord() bool {
+	fd := (*f.record.fDesc)[f.fType]
+	return fd != nil && f.fIndex < len(fd.fields) && fd.fields[f.fIndex] == f
+}
+
 func updateDeferredFields(records []*Record) (error, *Field) {
 	for _, r := range records {
 		for _, fType := range r.FieldTypes() {
@@ -0,0 +1,310 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A ReferenceGraph records which fields of a codeplug refer to which
+// records.  A field refers to a record if its value is an index into
+// the records of its list record type, as are the ContactName,
+// ChannelMember and PriorityChannel1 fields.  The graph is a snapshot;
+// it must be rebuilt after the codeplug changes.
+type ReferenceGraph struct {
+	codeplug  *Codeplug
+	referrers map[*Record][]*Field
+	dangling  []*Field
+}
+
+// ReferenceGraph returns the codeplug's current reference graph.
+func (cp *Codeplug) ReferenceGraph() *ReferenceGraph {
+	g := &ReferenceGraph{
+		codeplug:  cp,
+		referrers: make(map[*Record][]*Field),
+	}
+
+	for _, rType := range cp.RecordTypes() {
+		for _, r := range cp.rDesc[rType].records {
+			for _, fType := range r.FieldTypes() {
+				for _, f := range r.Fields(fType) {
+					g.addField(f)
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// addField adds the field, if it refers to a record, to the graph.
+func (g *ReferenceGraph) addField(f *Field) {
+	index, ok := f.referenceIndex()
+	if !ok {
+		return
+	}
+
+	records := g.codeplug.rDesc[f.listRecordType].records
+	if index < 1 || index > len(records) {
+		g.dangling = append(g.dangling, f)
+		return
+	}
+
+	r := records[index-1]
+	g.referrers[r] = append(g.referrers[r], f)
+}
+
+// References returns the fields that refer to the given record.
+func (g *ReferenceGraph) References(r *Record) []*Field {
+	return g.referrers[r]
+}
+
+// Referrers returns the records having fields that refer to the given
+// record, in codeplug order.
+func (g *ReferenceGraph) Referrers(r *Record) []*Record {
+	var records []*Record
+	seen := make(map[*Record]bool)
+	for _, f := range g.referrers[r] {
+		if !seen[f.record] {
+			seen[f.record] = true
+			records = append(records, f.record)
+		}
+	}
+
+	return records
+}
+
+// Dangling returns the fields whose values refer to records that don't
+// exist.
+func (g *ReferenceGraph) Dangling() []*Field {
+	return g.dangling
+}
+
+// referenceChanges returns a ListIndexChange for each field type of
+// each record that refers to a record of the given type.  Since fields
+// refer to records by index, these are the fields that must be updated
+// when records of that type are moved, inserted or removed.  The
+// changes hold the fields' values, by which updateListIndexChanges
+// restores them once the records have been rearranged, dropping any
+// references to removed records.
+func (g *ReferenceGraph) referenceChanges(rType RecordType) []*Change {
+	type key struct {
+		r     *Record
+		fType FieldType
+	}
+	seen := make(map[key]bool)
+	changes := []*Change{}
+	for _, r := range g.codeplug.rDesc[rType].records {
+		for _, f := range g.referrers[r] {
+			k := key{f.record, f.fType}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			changes = append(changes, listIndexChange(f.record, f.record.Fields(f.fType)))
+		}
+	}
+
+	return changes
+}
+
+// DanglingReferences returns the codeplug's fields whose values refer to
+// records that don't exist.
+func (cp *Codeplug) DanglingReferences() []*Field {
+	return cp.ReferenceGraph().Dangling()
+}
+
+// referenceIndex returns the (1-based) index of the record to which the
+// field refers.  It returns false if the field's value is not a reference,
+// including values, such as "None", that have an indexed string.
+func (f *Field) referenceIndex() (int, bool) {
+	if f.listRecordType == "" {
+		return 0, false
+	}
+
+	v := f.value
+	if iv, invalid := v.(invalidValue); invalid {
+		v = iv.value
+	}
+
+	var index uint16
+	switch v := v.(type) {
+	case *listIndex:
+		index = uint16(*v)
+	case *memberListIndex:
+		index = uint16(v.listIndex)
+	default:
+		return 0, false
+	}
+
+	if f.indexedStrings != nil {
+		for _, is := range *f.indexedStrings {
+			if is.Index == index {
+				return 0, false
+			}
+		}
+	}
+
+	return int(index), true
+}
+
+// A DeletePolicy determines what RemoveRecords does with the references
+// to the records being removed.
+type DeletePolicy int
+
+const (
+	// DeleteBlock refuses to remove records that are referred to.
+	DeleteBlock DeletePolicy = iota
+
+	// DeleteCascade removes the references along with the records.
+	// Members of lists are removed from the list, and other
+	// references are set to None.
+	DeleteCascade
+)
+
+// A ReferenceError is returned when records can't be removed because
+// other records refer to them.
+type ReferenceError struct {
+	References map[*Record][]*Field
+}
+
+// Error returns a description of the references, such as
+// "Digital Contacts: Club is used by 12 Channel Information records".
+func (e *ReferenceError) Error() string {
+	var records []*Record
+	for r := range e.References {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].rIndex < records[j].rIndex
+	})
+
+	var strs []string
+	for _, r := range records {
+		strs = append(strs, r.ReferenceSummary(e.References[r]))
+	}
+
+	return strings.Join(strs, "\n")
+}
+
+// ReferenceSummary returns a description of the given references to the
+// record, counting the referring records of each type.
+func (r *Record) ReferenceSummary(refs []*Field) string {
+	counts := make(map[*rDesc]int)
+	seen := make(map[*Record]bool)
+	var rDescs []*rDesc
+	for _, f := range refs {
+		if seen[f.record] {
+			continue
+		}
+		seen[f.record] = true
+		rd := f.record.rDesc
+		if counts[rd] == 0 {
+			rDescs = append(rDescs, rd)
+		}
+		counts[rd]++
+	}
+
+	var strs []string
+	for _, rd := range rDescs {
+		plural := "s"
+		if counts[rd] == 1 {
+			plural = ""
+		}
+		strs = append(strs, fmt.Sprintf("%d %s record%s", counts[rd], rd.typeName, plural))
+	}
+
+	return fmt.Sprintf("%s: %s is used by %s", r.TypeName(), r.Name(), strings.Join(strs, " and "))
+}
+
+// RemoveRecords removes the given records, which must be of the same
+// type, from the codeplug, as a single change that may be undone.
+// References to the records are handled according to policy.  If
+// policy is DeleteBlock and any of the records is referred to by a
+// record not being removed, a *ReferenceError is returned and the
+// codeplug is unchanged.
+func (cp *Codeplug) RemoveRecords(records []*Record, policy DeletePolicy) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	// records may share its array with the codeplug's records, which
+	// RemoveRecord rearranges.
+	records = append([]*Record{}, records...)
+
+	rType := records[0].rType
+	for _, r := range records {
+		if r.rType != rType {
+			return fmt.Errorf("records are not all of type %s", rType)
+		}
+	}
+
+	g := cp.ReferenceGraph()
+	if policy == DeleteBlock {
+		if err := g.checkReferences(records); err != nil {
+			return err
+		}
+	}
+
+	change := recordsChange(RemoveRecordsChange, records)
+	change.changes = g.referenceChanges(rType)
+	for _, r := range records {
+		cp.RemoveRecord(r)
+	}
+	change.Complete()
+
+	return nil
+}
+
+// CheckReferences returns a *ReferenceError describing the references
+// to the given records by records not among them, or nil if there are
+// none.  Such references would be lost if the records were removed.
+func (cp *Codeplug) CheckReferences(records []*Record) error {
+	return cp.ReferenceGraph().checkReferences(records)
+}
+
+// checkReferences returns a *ReferenceError describing the graph's
+// references to the given records by records not among them.
+func (g *ReferenceGraph) checkReferences(records []*Record) error {
+	removing := make(map[*Record]bool)
+	for _, r := range records {
+		removing[r] = true
+	}
+
+	refs := make(map[*Record][]*Field)
+	for _, r := range records {
+		for _, f := range g.References(r) {
+			if !removing[f.record] {
+				refs[r] = append(refs[r], f)
+			}
+		}
+	}
+	if len(refs) > 0 {
+		return &ReferenceError{refs}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package codeplug
+
+import (
+	"reflect"
+	"testing"
+)
+
+// addRecords appends copies of the first record of the given type,
+// with the given names, to the codeplug.
+func addRecords(t *testing.T, cp *Codeplug, rType RecordType, names ...string) {
+	t.Helper()
+
+	template := cp.Records(rType)[0].recordBytes()
+	for _, name := range names {
+		r := cp.bytesToRecord(rType, len(cp.Records(rType)), template)
+		if err := r.NameField().SetString(name); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := cp.InsertRecord(r); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+	}
+}
+
+// zoneMembers returns the names of the zone's channel members.
+func zoneMembers(zone *Record) []string {
+	var strs []string
+	for _, f := range zone.Fields(FtChannelMember) {
+		strs = append(strs, f.String())
+	}
+
+	return strs
+}
+
+func TestRemoveRecordsCascade(t *testing.T) {
+	cp, err := NewBlankCodeplug(CtMd380, FrequencyRanges(CtMd380)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	channels := cp.Records(RtChannelInformation)
+	first := channels[0].Name()
+	addRecords(t, cp, RtChannelInformation, "Two", "Three")
+	channels = cp.Records(RtChannelInformation)
+
+	zone := cp.Records(RtZoneInformation)[0]
+	setListFields(zone, FtChannelMember, []string{first, "Two", "Three"})
+	all := zoneMembers(zone)
+	if !reflect.DeepEqual(all, []string{first, "Two", "Three"}) {
+		t.Fatalf("zone members are %q", all)
+	}
+
+	err = cp.RemoveRecords(channels[1:2], DeleteBlock)
+	if _, ok := err.(*ReferenceError); !ok {
+		t.Fatalf("DeleteBlock returned %v, not a *ReferenceError", err)
+	}
+	if len(cp.Records(RtChannelInformation)) != 3 {
+		t.Fatal("DeleteBlock removed a record")
+	}
+
+	err = cp.RemoveRecords(channels[1:2], DeleteCascade)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cascaded := []string{first, "Three"}
+	if got := zoneMembers(zone); !reflect.DeepEqual(got, cascaded) {
+		t.Errorf("after DeleteCascade, zone members are %q, not %q", got, cascaded)
+	}
+	if dangling := cp.DanglingReferences(); len(dangling) != 0 {
+		t.Errorf("after DeleteCascade, %d references dangle", len(dangling))
+	}
+
+	cp.UndoChange()
+	if got := zoneMembers(zone); !reflect.DeepEqual(got, all) {
+		t.Errorf("after undo, zone members are %q, not %q", got, all)
+	}
+
+	cp.RedoChange()
+	if got := zoneMembers(zone); !reflect.DeepEqual(got, cascaded) {
+		t.Errorf("after redo, zone members are %q, not %q", got, cascaded)
+	}
+}
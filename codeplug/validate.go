@@ -0,0 +1,254 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+package codeplug
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Severity tells how serious a validation issue is.
+type Severity int
+
+const (
+	// SeverityError issues prevent the codeplug from being saved.
+	SeverityError Severity = iota
+
+	// SeverityWarning issues are invalid values in fields that
+	// are currently disabled, so they are not used by the radio,
+	// values outside a field's usual range, or, when found by Lint,
+	// likely programming mistakes.
+	SeverityWarning
+
+	// SeverityInfo issues, found by Lint, are settings that are
+	// often mistakes but may be intended.
+	SeverityInfo
+)
+
+// String returns the severity's name.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+
+	return fmt.Sprintf("severity %d", int(s))
+}
+
+// An Issue describes an invalid field value, or a mistake found by
+// Lint, in a codeplug.  Rule is the ID of the lint rule that found the
+// issue, or "" for an invalid field value.  A lint issue concerning a
+// whole record has no FieldType.
+type Issue struct {
+	RecordType  RecordType
+	RecordIndex int
+	FieldType   FieldType
+	FieldIndex  int
+	Value       string
+	Severity    Severity
+	Message     string
+	Rule        string
+	name        string
+	location    string
+}
+
+// String returns a description of the issue, naming the field by
+// its record's and its own type names.
+func (issue Issue) String() string {
+	return issue.name + ": " + issue.Message
+}
+
+// Location returns the name of the issue's field in the form used by
+// Export, as in "ZoneInformation[2].ChannelMember[5]", or that of its
+// record, as in "ZoneInformation[2]", if it has no field.
+func (issue Issue) Location() string {
+	return issue.location
+}
+
+// A ValidationError is returned when a codeplug contains one or more
+// issues of SeverityError.  Issues contains all issues found, including
+// warnings.
+type ValidationError struct {
+	Issues []Issue
+}
+
+// Error returns the issues of SeverityError, one per line.
+func (e *ValidationError) Error() string {
+	var strs []string
+	for _, issue := range e.Issues {
+		if issue.Severity == SeverityError {
+			strs = append(strs, issue.String())
+		}
+	}
+
+	return strings.Join(strs, "\n")
+}
+
+// Validate checks the value of every field in the codeplug and returns
+// an issue for each invalid value found.
+func (cp *Codeplug) Validate() []Issue {
+	var issues []Issue
+
+	for _, rType := range cp.RecordTypes() {
+		for _, r := range cp.Records(rType) {
+			issues = append(issues, r.validate()...)
+		}
+	}
+
+	deferred := cp.deferredValid
+	cp.deferredValid = nil
+	for _, f := range deferred {
+		if issue := f.validate(); issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+
+	return issues
+}
+
+// valid returns a *ValidationError if the codeplug contains any issues
+// of SeverityError.
+func (cp *Codeplug) valid() error {
+	issues := cp.Validate()
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return &ValidationError{issues}
+		}
+	}
+
+	return nil
+}
+
+// validate returns the issues found in all of the record's fields.
+func (r *Record) validate() []Issue {
+	var issues []Issue
+
+	for _, fType := range r.FieldTypes() {
+		for _, f := range r.Fields(fType) {
+			if issue := f.validate(); issue != nil {
+				issues = append(issues, *issue)
+			}
+		}
+	}
+
+	return issues
+}
+
+// A warner is a value that may be valid but questionable.  Its warning
+// method returns an error describing why, or nil.
+type warner interface {
+	warning(*Field) error
+}
+
+// validate returns an issue if the field's value is invalid or
+// questionable, or nil.  An invalid value is marked as such, so that
+// it is displayed as invalid and is left unchanged when the codeplug
+// is stored.  A questionable value is only reported, as a warning.
+func (f *Field) validate() *Issue {
+	err := f.value.valid(f)
+	if err == nil {
+		w, ok := f.value.(warner)
+		if !ok {
+			return nil
+		}
+		if err = w.warning(f); err == nil {
+			return nil
+		}
+		return f.issue(SeverityWarning, err)
+	}
+
+	if _, invalid := f.value.(invalidValue); !invalid {
+		f.value = invalidValue{value: f.value}
+	}
+
+	severity := SeverityError
+	if !f.IsEnabled() {
+		severity = SeverityWarning
+	}
+
+	return f.issue(severity, err)
+}
+
+// issue returns an issue of the given severity describing err, for the
+// field.
+func (f *Field) issue(severity Severity, err error) *Issue {
+	r := f.record
+	return &Issue{
+		RecordType:  r.rType,
+		RecordIndex: r.rIndex,
+		FieldType:   f.fType,
+		FieldIndex:  f.fIndex,
+		Value:       f.rawString(),
+		Severity:    severity,
+		Message:     err.Error(),
+		name:        f.FullTypeName(),
+		location:    f.location(),
+	}
+}
+
+// location returns the name of the field in the form used by Export,
+// as in "ZoneInformation[2].ChannelMember[5]".
+func (f *Field) location() string {
+	r := f.record
+	location := string(r.rType)
+	if r.max > 1 {
+		location += fmt.Sprintf("[%d]", r.rIndex+1)
+	}
+	location += "." + string(f.fType)
+	if f.max > 1 {
+		location += fmt.Sprintf("[%d]", f.fIndex+1)
+	}
+
+	return location
+}
+
+// rawString returns the field's value as a string.  Unlike String,
+// it doesn't hide an invalid value.  Values that index into a list
+// are shown as numbers, since an invalid index has no string form.
+func (f *Field) rawString() string {
+	v := f.value
+	if iv, invalid := v.(invalidValue); invalid {
+		v = iv.value
+	}
+
+	switch v := v.(type) {
+	case *iStrings:
+		return strconv.Itoa(int(*v))
+	case *indexedStrings:
+		return strconv.Itoa(int(*v))
+	case *listIndex:
+		return strconv.Itoa(int(*v))
+	case *memberListIndex:
+		return strconv.Itoa(int(v.listIndex))
+	case *ctcssDcs:
+		return fmt.Sprintf("%#04x", int(*v))
+	}
+
+	return v.String(f)
+}
@@ -0,0 +1,41 @@
+package codeplug
+
+import (
+	"testing"
+)
+
+func TestSpanOutOfRange(t *testing.T) {
+	cp, err := NewCodeplugFromBytes(blankImage(t, CtMd380), CtMd380)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	f := cp.Record(RtGeneralSettings).Field(FtTxPreambleDuration)
+	if err := f.SetString("15360"); err == nil {
+		t.Errorf("span too large for its bits accepted")
+	}
+	if err := f.SetString("12000"); err != nil {
+		t.Fatalf("out-of-range span rejected: %s", err)
+	}
+
+	var warnings []Issue
+	for _, issue := range cp.Validate() {
+		if issue.FieldType != FtTxPreambleDuration {
+			continue
+		}
+		if issue.Severity != SeverityWarning {
+			t.Errorf("out-of-range span reported as %s", issue.Severity)
+		}
+		warnings = append(warnings, issue)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("%d issues for the out-of-range span, not 1", len(warnings))
+	}
+	if err := cp.valid(); err != nil {
+		t.Errorf("codeplug with an out-of-range span is not valid: %s", err)
+	}
+	if f.String() != "12000" {
+		t.Errorf("out-of-range span changed to %s", f.String())
+	}
+}
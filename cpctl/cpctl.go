@@ -0,0 +1,877 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Cpctl.
+//
+// Cpctl is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// Cpctl is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Cpctl.  If not, see <http://www.gnu.org/licenses/>.
+
+// Cpctl is a command-line program for reading, converting, validating
+// and modifying codeplug files without a graphical user interface.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dalefarnsworth/codeplug/codeplug"
+)
+
+// Exit codes.  These are part of cpctl's interface and must not change.
+const (
+	exitOK      = 0 // success
+	exitInvalid = 1 // the codeplug is not valid
+	exitUsage   = 2 // bad command line
+	exitError   = 3 // any other failure, such as an I/O error
+)
+
+// stdio is the file name that stands for standard input or output.
+const stdio = "-"
+
+// A cmdError carries an error message along with an exit code.
+type cmdError struct {
+	code int
+	err  error
+}
+
+func (e cmdError) Error() string {
+	return e.err.Error()
+}
+
+func usageErrorf(format string, args ...interface{}) error {
+	return cmdError{exitUsage, fmt.Errorf(format, args...)}
+}
+
+func invalidError(err error) error {
+	return cmdError{exitInvalid, err}
+}
+
+type command struct {
+	name  string
+	args  string
+	nargs int
+	help  string
+	run   func(fs *flag.FlagSet, args []string) error
+	flags func(fs *flag.FlagSet)
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			name: "export",
+			args: "[-format text|json|yaml] <codeplug>",
+			help: "write the codeplug in text, JSON or YAML form",
+			run:  export,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("format", "text", "output `format`: text, json or yaml")
+			},
+		},
+		{
+			name: "import",
+			args: "-template <rdt> [-format text|json|yaml] <textfile>",
+			help: "create a codeplug from a text file and a template codeplug",
+			run:  importText,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("template", "", "template codeplug `file`")
+				fs.String("format", "text", "input `format`: text, json or yaml")
+			},
+		},
+		{
+			name: "convert",
+			args: "-to rdt|bin [-template <rdt>] <codeplug>",
+			help: "convert a codeplug between rdt and bin file types",
+			run:  convert,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("to", "", "output file `type`: rdt or bin")
+				fs.String("template", "", "rdt `file` providing the header when converting from bin to rdt")
+			},
+		},
+		{
+			name: "validate",
+			args: "<codeplug>",
+			help: "check the codeplug for invalid values",
+			run:  validate,
+		},
+		{
+			name: "lint",
+			args: "[-rules <ids>] [-bandplan <plan>] <codeplug>",
+			help: "check the codeplug for common programming mistakes",
+			run:  lint,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("rules", "", "comma-separated rule `IDs` to check (default all)")
+				fs.String("bandplan", "", "band `plan` name, or file of band plans, for the band plan rules")
+			},
+		},
+		{
+			name: "print",
+			args: "[-type <recordtype>] [-index <list>] <codeplug>",
+			help: "print selected records in text form",
+			run:  printRecords,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("type", "", "comma-separated record `types` to print (default all)")
+				fs.String("index", "", "comma-separated record `indexes` or ranges to print, e.g. 1,3-5 (default all)")
+			},
+		},
+		{
+			name:  "query",
+			args:  "-type <recordtype> [-names] <codeplug> <query>",
+			nargs: 2,
+			help:  "print the records selected by a query",
+			run:   query,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("type", "ChannelInformation", "record `type` to query")
+				fs.Bool("names", false, "print only the names of the selected records")
+			},
+		},
+		{
+			name: "map",
+			args: "[-type <recordtype>] [-index <n>] [-compare <codeplug>] [-format text|html] <codeplug>",
+			help: "show which bits of a record are described by fields",
+			run:  memoryMap,
+			flags: func(fs *flag.FlagSet) {
+				fs.String("type", "ChannelInformation", "record `type` to map")
+				fs.Int("index", 1, "`index` of the record to map")
+				fs.String("compare", "", "codeplug `file` whose differing bytes are marked")
+				fs.String("format", "text", "output `format`: text or html")
+			},
+		},
+		{
+			name:  "diff",
+			args:  "<old codeplug> <new codeplug>",
+			nargs: 2,
+			help:  "show the differences between two codeplugs",
+			run:   diff,
+		},
+		{
+			name:  "merge",
+			args:  "<base codeplug> <our codeplug> <their codeplug>",
+			nargs: 3,
+			help:  "merge the changes made to two copies of a codeplug",
+			run:   merge,
+		},
+	}
+}
+
+func usage() {
+	w := os.Stderr
+	fmt.Fprintf(w, "usage: cpctl [-model <model>] <command> [options] <args>\n\n")
+	fmt.Fprintf(w, "Commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.help)
+	}
+	fmt.Fprintf(w, "\nA file name of \"-\" stands for standard input or output.\n")
+	fmt.Fprintf(w, "Run \"cpctl <command> -h\" for help with a command.\n")
+}
+
+// model is the codeplug type of all codeplugs read by cpctl.  If it
+// is empty, the type of each codeplug is inferred from its file size.
+var model codeplug.CodeplugType
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("cpctl", flag.ContinueOnError)
+	fs.Usage = usage
+	modelName := fs.String("model", "", "radio `model` (default: inferred from file size, if unambiguous)")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	model = codeplug.CodeplugType(*modelName)
+	if model != "" && !modelSupported(model) {
+		fmt.Fprintf(os.Stderr, "cpctl: unknown model: %s\n", model)
+		return exitUsage
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		usage()
+		return exitUsage
+	}
+
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "cpctl: unknown command: %s\n", args[0])
+		usage()
+		return exitUsage
+	}
+
+	cmdFs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	output := cmdFs.String("o", stdio, "output `file`")
+	if cmd.flags != nil {
+		cmd.flags(cmdFs)
+	}
+	cmdFs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: cpctl %s [-o <file>] %s\n", cmd.name, cmd.args)
+		cmdFs.PrintDefaults()
+	}
+	if err := cmdFs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	nargs := cmd.nargs
+	if nargs == 0 {
+		nargs = 1
+	}
+	if cmdFs.NArg() != nargs {
+		cmdFs.Usage()
+		return exitUsage
+	}
+	outputFilename = *output
+
+	err := cmd.run(cmdFs, cmdFs.Args())
+	if err == nil {
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "cpctl %s: %s\n", cmd.name, strings.TrimRight(err.Error(), "\n"))
+
+	if e, ok := err.(cmdError); ok {
+		if e.code == exitUsage {
+			cmdFs.Usage()
+		}
+		return e.code
+	}
+
+	return exitError
+}
+
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func modelSupported(cpType codeplug.CodeplugType) bool {
+	for _, t := range codeplug.CodeplugTypes() {
+		if t == cpType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// codeplugType returns the codeplug type of the named file, given its
+// contents.  Unless a model was given, it is the type whose files are
+// of the contents' size.  Several models share a file size, and their
+// files can't be told apart, so the model must then be given.
+func codeplugType(filename string, fileBytes []byte) (codeplug.CodeplugType, error) {
+	if model != "" {
+		return model, nil
+	}
+
+	cpTypes := codeplug.CodeplugTypesForSize(len(fileBytes))
+	switch len(cpTypes) {
+	case 0:
+		return codeplug.CtMd380, nil
+	case 1:
+		return cpTypes[0], nil
+	}
+
+	strs := make([]string, len(cpTypes))
+	for i, cpType := range cpTypes {
+		strs[i] = string(cpType)
+	}
+
+	return "", usageErrorf("%s: ambiguous model (%s), use -model",
+		filename, strings.Join(strs, " or "))
+}
+
+// flagString returns the value of the named string flag.
+func flagString(fs *flag.FlagSet, name string) string {
+	return fs.Lookup(name).Value.String()
+}
+
+// outputFilename is the name of the file given with the -o option.
+var outputFilename string
+
+// readFile returns the contents of the named file, or of standard
+// input if the name is "-".
+func readFile(filename string) ([]byte, error) {
+	if filename == stdio {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(filename)
+}
+
+// openCodeplug reads and returns the named codeplug.
+func openCodeplug(filename string) (*codeplug.Codeplug, error) {
+	fileBytes, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if filename == stdio {
+		filename = "<stdin>"
+	}
+	cpType, err := codeplugType(filename, fileBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	cp, err := codeplug.NewCodeplugFromBytes(fileBytes, cpType)
+	if err != nil {
+		return nil, openError(filename, err)
+	}
+
+	return cp, nil
+}
+
+// openError returns the error reported for a codeplug file that could
+// not be opened.  Only a codeplug that is not valid is reported with
+// exitInvalid; a file of the wrong size or of an unknown model is
+// reported with exitError.
+func openError(filename string, err error) error {
+	_, invalid := err.(*codeplug.ValidationError)
+	err = fmt.Errorf("%s: %s", filename, err.Error())
+	if invalid {
+		return invalidError(err)
+	}
+
+	return cmdError{exitError, err}
+}
+
+// writeCodeplug saves the codeplug into the output file, or writes it
+// to standard output.  A codeplug that is not valid is reported with
+// exitInvalid.
+func writeCodeplug(cp *codeplug.Codeplug) error {
+	var err error
+	if outputFilename != stdio {
+		err = cp.SaveToFile(outputFilename)
+	} else {
+		_, err = cp.WriteTo(os.Stdout)
+	}
+
+	if _, ok := err.(*codeplug.ValidationError); ok {
+		return invalidError(err)
+	}
+	return err
+}
+
+// writeOutput calls fn with a writer for the output file, or for
+// standard output.
+func writeOutput(fn func(w io.Writer) error) (err error) {
+	if outputFilename == stdio {
+		bw := bufio.NewWriter(os.Stdout)
+		err = fn(bw)
+		if err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	file, err := os.Create(outputFilename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	bw := bufio.NewWriter(file)
+	err = fn(bw)
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+func export(fs *flag.FlagSet, args []string) error {
+	format := strings.ToLower(flagString(fs, "format"))
+	if !formatSupported(format) {
+		return usageErrorf("unknown format: %s", format)
+	}
+
+	cp, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		return writeOutput(cp.ExportJson)
+	case "yaml":
+		return writeOutput(cp.ExportYaml)
+	}
+
+	return writeOutput(cp.Export)
+}
+
+// formatSupported returns true if format names a supported text format.
+func formatSupported(format string) bool {
+	switch format {
+	case "text", "json", "yaml":
+		return true
+	}
+
+	return false
+}
+
+func importText(fs *flag.FlagSet, args []string) error {
+	template := flagString(fs, "template")
+	if template == "" {
+		return usageErrorf("a -template codeplug is required")
+	}
+
+	format := strings.ToLower(flagString(fs, "format"))
+	if !formatSupported(format) {
+		return usageErrorf("unknown format: %s", format)
+	}
+
+	cp, err := openCodeplug(template)
+	if err != nil {
+		return err
+	}
+
+	text, err := readFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "text":
+		err = cp.Import(bytes.NewReader(text))
+	case "json":
+		err = cp.ImportJson(bytes.NewReader(text))
+	case "yaml":
+		err = cp.ImportYaml(bytes.NewReader(text))
+	}
+	if err != nil {
+		return invalidError(err)
+	}
+
+	return writeCodeplug(cp)
+}
+
+func convert(fs *flag.FlagSet, args []string) error {
+	var fType codeplug.FileType
+
+	to := flagString(fs, "to")
+	switch strings.ToLower(to) {
+	case "rdt":
+		fType = codeplug.FileTypeRdt
+	case "bin":
+		fType = codeplug.FileTypeBin
+	case "":
+		if outputFilename == stdio {
+			return usageErrorf("-to is required")
+		}
+		switch strings.ToLower(filepath.Ext(outputFilename)) {
+		case ".rdt":
+			fType = codeplug.FileTypeRdt
+		case ".bin":
+			fType = codeplug.FileTypeBin
+		default:
+			return usageErrorf("-to is required")
+		}
+	default:
+		return usageErrorf("bad -to file type: %s", to)
+	}
+
+	cp, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	var rdtTemplate *codeplug.Codeplug
+	if template := flagString(fs, "template"); template != "" {
+		rdtTemplate, err = openCodeplug(template)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cp.ConvertTo(fType, rdtTemplate)
+	if err != nil {
+		return usageErrorf("%s", err.Error())
+	}
+
+	return writeCodeplug(cp)
+}
+
+// validate writes a line for each issue found in the codeplug, in the
+// form "file: location: severity: message (value "value")".
+func validate(fs *flag.FlagSet, args []string) error {
+	filename := args[0]
+	fileBytes, err := readFile(filename)
+	if err != nil {
+		return err
+	}
+	if filename == stdio {
+		filename = "<stdin>"
+	}
+
+	cpType, err := codeplugType(filename, fileBytes)
+	if err != nil {
+		return err
+	}
+
+	var issues []codeplug.Issue
+	cp, err := codeplug.NewCodeplugFromBytes(fileBytes, cpType)
+	if err != nil {
+		vErr, ok := err.(*codeplug.ValidationError)
+		if !ok {
+			return openError(filename, err)
+		}
+		issues = vErr.Issues
+	} else {
+		issues = cp.Validate()
+	}
+
+	errorCount := 0
+	err = writeOutput(func(w io.Writer) error {
+		for _, issue := range issues {
+			if issue.Severity == codeplug.SeverityError {
+				errorCount++
+			}
+			fmt.Fprintf(w, "%s: %s: %s: %s (value %q)\n", filename,
+				issue.Location(), issue.Severity, issue.Message,
+				issue.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if errorCount > 0 {
+		return invalidError(fmt.Errorf("%s: %d invalid field values", filename, errorCount))
+	}
+
+	return nil
+}
+
+func lint(fs *flag.FlagSet, args []string) error {
+	filename := args[0]
+	cp, err := openCodeplug(filename)
+	if err != nil {
+		return err
+	}
+	if filename == stdio {
+		filename = "<stdin>"
+	}
+
+	var rules []*codeplug.LintRule
+	if ruleStr := flagString(fs, "rules"); ruleStr != "" {
+		for _, id := range strings.Split(ruleStr, ",") {
+			rule := codeplug.LintRuleByID(strings.TrimSpace(id))
+			if rule == nil {
+				return usageErrorf("unknown lint rule: %s", id)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	if planStr := flagString(fs, "bandplan"); planStr != "" {
+		bp, err := bandPlan(planStr)
+		if err != nil {
+			return err
+		}
+		cp.SetBandPlan(bp)
+	}
+
+	warningCount := 0
+	err = writeOutput(func(w io.Writer) error {
+		for _, issue := range cp.Lint(rules...) {
+			if issue.Severity != codeplug.SeverityInfo {
+				warningCount++
+			}
+			fmt.Fprintf(w, "%s: %s: %s: %s: %s\n", filename,
+				issue.Location(), issue.Severity, issue.Rule,
+				issue.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if warningCount > 0 {
+		return invalidError(fmt.Errorf("%s: %d lint warnings", filename, warningCount))
+	}
+
+	return nil
+}
+
+// bandPlan returns the band plan with the given name or, if a file
+// of that name exists, the first band plan read from the file.
+func bandPlan(str string) (*codeplug.BandPlan, error) {
+	if _, err := os.Stat(str); err == nil {
+		plans, err := codeplug.LoadBandPlanFile(str)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", str, err.Error())
+		}
+		if len(plans) == 0 {
+			return nil, fmt.Errorf("%s: no band plans", str)
+		}
+		return plans[0], nil
+	}
+
+	bp := codeplug.BandPlanByName(str)
+	if bp == nil {
+		var names []string
+		for _, bp := range codeplug.BandPlans() {
+			names = append(names, bp.Name)
+		}
+		return nil, usageErrorf("unknown band plan: %s (known plans: %s)",
+			str, strings.Join(names, ", "))
+	}
+
+	return bp, nil
+}
+
+func printRecords(fs *flag.FlagSet, args []string) error {
+	cp, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	rTypes := cp.RecordTypes()
+	if typeStr := flagString(fs, "type"); typeStr != "" {
+		rTypes = nil
+		for _, name := range strings.Split(typeStr, ",") {
+			rType, err := recordType(cp, strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			rTypes = append(rTypes, rType)
+		}
+	}
+
+	var indexes map[int]bool
+	if indexStr := flagString(fs, "index"); indexStr != "" {
+		indexes, err = parseIndexes(indexStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeOutput(func(w io.Writer) error {
+		for _, rType := range rTypes {
+			for _, r := range cp.Records(rType) {
+				if indexes != nil && !indexes[r.Index()+1] {
+					continue
+				}
+				codeplug.PrintRecord(w, r)
+			}
+		}
+		return nil
+	})
+}
+
+func query(fs *flag.FlagSet, args []string) error {
+	cp, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	rType, err := recordType(cp, flagString(fs, "type"))
+	if err != nil {
+		return err
+	}
+
+	pred, err := cp.ParseQuery(rType, args[1])
+	if err != nil {
+		return usageErrorf("bad query: %s", err.Error())
+	}
+	namesOnly := flagString(fs, "names") == "true"
+
+	return writeOutput(func(w io.Writer) error {
+		for _, r := range cp.FindRecords(rType, pred) {
+			if namesOnly {
+				fmt.Fprintf(w, "%d\t%s\n", r.Index()+1, r.Name())
+				continue
+			}
+			codeplug.PrintRecord(w, r)
+		}
+		return nil
+	})
+}
+
+// recordType returns the record type of the codeplug with the given
+// name or type name.
+func recordType(cp *codeplug.Codeplug, name string) (codeplug.RecordType, error) {
+	for _, rType := range cp.RecordTypes() {
+		if strings.EqualFold(name, string(rType)) ||
+			strings.EqualFold(name, cp.RecordTypeName(rType)) {
+			return rType, nil
+		}
+	}
+
+	return "", usageErrorf("unknown record type: %s", name)
+}
+
+// parseIndexes returns the set of indexes in a string of the
+// form "1,3-5".
+func parseIndexes(str string) (map[int]bool, error) {
+	indexes := make(map[int]bool)
+
+	for _, s := range strings.Split(str, ",") {
+		first, last := s, s
+		if i := strings.Index(s, "-"); i >= 0 {
+			first, last = s[:i], s[i+1:]
+		}
+		low, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || low < 1 {
+			return nil, usageErrorf("bad index: %s", s)
+		}
+		high, err := strconv.Atoi(strings.TrimSpace(last))
+		if err != nil || high < low {
+			return nil, usageErrorf("bad index: %s", s)
+		}
+		for i := low; i <= high; i++ {
+			indexes[i] = true
+		}
+	}
+
+	return indexes, nil
+}
+
+// memoryMap writes the memory map of a record, marking the bytes that
+// differ from those of the codeplug to compare, if one is given.
+func memoryMap(fs *flag.FlagSet, args []string) error {
+	format := flagString(fs, "format")
+	if format != "text" && format != "html" {
+		return usageErrorf("unknown format: %s", format)
+	}
+
+	cp, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	rType, err := recordType(cp, flagString(fs, "type"))
+	if err != nil {
+		return err
+	}
+
+	index, err := strconv.Atoi(flagString(fs, "index"))
+	if err != nil {
+		return usageErrorf("bad index: %s", flagString(fs, "index"))
+	}
+
+	m, err := cp.MemoryMap(rType, index-1)
+	if err != nil {
+		return usageErrorf("%s", err.Error())
+	}
+
+	if filename := flagString(fs, "compare"); filename != "" {
+		if filename == stdio && args[0] == stdio {
+			return usageErrorf("only one codeplug may be read from standard input")
+		}
+		other, err := openCodeplug(filename)
+		if err != nil {
+			return err
+		}
+		err = m.Compare(other)
+		if err != nil {
+			return err
+		}
+	}
+
+	if format == "html" {
+		return writeOutput(m.WriteHTML)
+	}
+
+	return writeOutput(m.WriteText)
+}
+
+func diff(fs *flag.FlagSet, args []string) error {
+	if args[0] == stdio && args[1] == stdio {
+		return usageErrorf("only one codeplug may be read from standard input")
+	}
+
+	a, err := openCodeplug(args[0])
+	if err != nil {
+		return err
+	}
+
+	b, err := openCodeplug(args[1])
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(codeplug.Diff(a, b).WriteText)
+}
+
+// merge writes the merged codeplug, and writes a line to standard error
+// for each conflict.
+func merge(fs *flag.FlagSet, args []string) error {
+	cps := make([]*codeplug.Codeplug, len(args))
+	stdin := false
+	for i, filename := range args {
+		if filename == stdio {
+			if stdin {
+				return usageErrorf("only one codeplug may be read from standard input")
+			}
+			stdin = true
+		}
+
+		cp, err := openCodeplug(filename)
+		if err != nil {
+			return err
+		}
+		cps[i] = cp
+	}
+
+	cp, conflicts, err := codeplug.Merge(cps[0], cps[1], cps[2])
+	if err != nil {
+		return invalidError(err)
+	}
+
+	err = writeCodeplug(cp)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "conflict: %s\n", c)
+	}
+	if len(conflicts) > 0 {
+		return invalidError(fmt.Errorf("%d conflicts", len(conflicts)))
+	}
+
+	return nil
+}
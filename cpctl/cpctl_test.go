@@ -0,0 +1,181 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dalefarnsworth/codeplug/codeplug"
+)
+
+// blankRdt returns the contents of a new blank md380 rdt file.
+func blankRdt(t *testing.T) []byte {
+	t.Helper()
+
+	cpType := codeplug.CtMd380
+	cp, err := codeplug.NewBlankCodeplug(cpType, codeplug.FrequencyRanges(cpType)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Free()
+
+	cpBytes, err := cp.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cpBytes
+}
+
+// invalidRdt returns the contents of an md380 rdt file whose first
+// channel refers to a scan list that doesn't exist.
+func invalidRdt(t *testing.T) []byte {
+	t.Helper()
+
+	cpBytes := blankRdt(t)
+	m, err := codeplug.NewMemoryMap(codeplug.CtMd380, codeplug.RtChannelInformation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range m.Bytes {
+		if b.Bits[0].FieldType == codeplug.FtScanList {
+			cpBytes[m.Offset+b.Offset] = 0xff
+		}
+	}
+
+	return cpBytes
+}
+
+// runCpctl runs cpctl with the given arguments, reading stdin as its
+// standard input, and returns its exit code and standard output.
+func runCpctl(t *testing.T, stdin []byte, args ...string) (int, []byte) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "cpctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := make([]*os.File, 3)
+	for i, name := range []string{"stdin", "stdout", "stderr"} {
+		filename := filepath.Join(dir, name)
+		if i == 0 {
+			err = ioutil.WriteFile(filename, stdin, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files[i], err = os.Open(filename)
+		} else {
+			files[i], err = os.Create(filename)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer files[i].Close()
+	}
+
+	stdinFile, stdoutFile, stderrFile := os.Stdin, os.Stdout, os.Stderr
+	os.Stdin, os.Stdout, os.Stderr = files[0], files[1], files[2]
+	code := run(args)
+	os.Stdin, os.Stdout, os.Stderr = stdinFile, stdoutFile, stderrFile
+
+	output, err := ioutil.ReadFile(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code, output
+}
+
+func TestExitCodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cpctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	blank := blankRdt(t)
+	blankFile := filepath.Join(dir, "blank.rdt")
+	if err := ioutil.WriteFile(blankFile, blank, 0644); err != nil {
+		t.Fatal(err)
+	}
+	missingFile := filepath.Join(dir, "missing.rdt")
+	invalid := invalidRdt(t)
+	short := []byte("not a codeplug")
+
+	code, text := runCpctl(t, blank, "-model", "md380", "export", "-")
+	if code != exitOK {
+		t.Fatalf("export exited with %d", code)
+	}
+
+	tests := []struct {
+		stdin []byte
+		args  []string
+		code  int
+	}{
+		{nil, []string{}, exitUsage},
+		{nil, []string{"bogus"}, exitUsage},
+		{blank, []string{"-model", "bogus", "export", "-"}, exitUsage},
+		{blank, []string{"export", "-"}, exitUsage},
+		{blank, []string{"-model", "md390", "export", "-"}, exitOK},
+
+		{blank, []string{"-model", "md380", "export", "-format", "yaml", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "export", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "export", "-format", "xml", "-"}, exitUsage},
+		{short, []string{"-model", "md380", "export", "-"}, exitError},
+
+		{text, []string{"-model", "md380", "import", "-template", blankFile, "-"}, exitOK},
+		{[]byte("Bogus:\n"), []string{"-model", "md380", "import", "-template", blankFile, "-"}, exitInvalid},
+		{text, []string{"-model", "md380", "import", "-"}, exitUsage},
+		{text, []string{"-model", "md380", "import", "-template", missingFile, "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "convert", "-to", "bin", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "convert", "-to", "bin", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "convert", "-to", "hex", "-"}, exitUsage},
+		{short, []string{"-model", "md380", "convert", "-to", "bin", "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "validate", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "validate", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "validate", "-", "extra"}, exitUsage},
+		{short, []string{"-model", "md380", "validate", "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "lint", "-rules", "duplicate-channel", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "lint", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "lint", "-rules", "bogus", "-"}, exitUsage},
+		{short, []string{"-model", "md380", "lint", "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "print", "-type", "ChannelInformation", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "print", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "print", "-type", "Bogus", "-"}, exitUsage},
+		{nil, []string{"-model", "md380", "print", missingFile}, exitError},
+
+		{blank, []string{"-model", "md380", "query", "-names", "-", "Name ~ Channel"}, exitOK},
+		{invalid, []string{"-model", "md380", "query", "-", "Name ~ Channel"}, exitInvalid},
+		{blank, []string{"-model", "md380", "query", "-", "Name ~"}, exitUsage},
+		{short, []string{"-model", "md380", "query", "-", "Name ~ Channel"}, exitError},
+
+		{blank, []string{"-model", "md380", "map", "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "map", "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "map", "-format", "pdf", "-"}, exitUsage},
+		{short, []string{"-model", "md380", "map", "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "diff", blankFile, "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "diff", blankFile, "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "diff", "-", "-"}, exitUsage},
+		{short, []string{"-model", "md380", "diff", blankFile, "-"}, exitError},
+
+		{blank, []string{"-model", "md380", "merge", blankFile, blankFile, "-"}, exitOK},
+		{invalid, []string{"-model", "md380", "merge", blankFile, blankFile, "-"}, exitInvalid},
+		{blank, []string{"-model", "md380", "merge", "-", "-", blankFile}, exitUsage},
+		{short, []string{"-model", "md380", "merge", blankFile, blankFile, "-"}, exitError},
+	}
+
+	for _, test := range tests {
+		code, _ := runCpctl(t, test.stdin, test.args...)
+		if code != test.code {
+			t.Errorf("cpctl %q exited with %d, not %d", test.args, code, test.code)
+		}
+	}
+}
@@ -86,6 +86,10 @@ func ciRecord(edt *editor, recordBox *ui.HBox) {
 		codeplug.FtPrivacy,
 		codeplug.FtPrivacyNumber)
 
+	if edt.codeplug.HasRecordType(codeplug.RtGpsSystem) {
+		form.AddFieldRows(r, codeplug.FtGpsSystem)
+	}
+
 	row = mainBox.AddHbox()
 	groupBox = row.AddGroupbox("Analog Data")
 	row = groupBox.AddHbox()
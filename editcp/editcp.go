@@ -29,6 +29,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/dalefarnsworth/codeplug/codeplug"
 	"github.com/dalefarnsworth/codeplug/ui"
@@ -45,7 +46,10 @@ type editorSettings struct {
 	sortAvailableChannels bool
 	sortAvailableContacts bool
 	autosaveInterval      int
+	model                 string
 	recentFiles           []string
+	bandPlan              string
+	bandPlanFile          string
 }
 
 var appSettings *ui.AppSettings
@@ -79,6 +83,7 @@ func checkAutosave(filename string) {
 
 	if fInfo.ModTime().After(asInfo.ModTime()) {
 		os.Remove(asFilename)
+		os.Remove(asFilename + codeplug.HistorySuffix)
 		return
 	}
 
@@ -91,6 +96,9 @@ func checkAutosave(filename string) {
 	case ui.PopupYes:
 		os.Rename(filename, backupFilename)
 		os.Rename(asFilename, filename)
+		historyFilename := filename + codeplug.HistorySuffix
+		os.Rename(historyFilename, backupFilename+codeplug.HistorySuffix)
+		os.Rename(asFilename+codeplug.HistorySuffix, historyFilename)
 		msg := fmt.Sprintf("%s has been saved as %s",
 			filename, backupFilename)
 		ui.InfoPopup("Backup created", msg)
@@ -110,10 +118,10 @@ func (edt *editor) revertFile() error {
 		switch ui.YesNoPopup(title, msg) {
 		case ui.PopupYes:
 			err := edt.codeplug.Revert()
+			ui.ResetWindows(cp)
 			if err != nil {
-				ui.WarningPopup("Revert Failed", err.Error())
+				edt.validationFailed("Revert Failed", err)
 			}
-			ui.ResetWindows(cp)
 
 		default:
 			break
@@ -131,7 +139,8 @@ func (edt *editor) saveAs(filename string) {
 	autosaveFilename := edt.codeplug.Filename() + autosaveSuffix
 
 	if filename == "" {
-		filename = ui.SaveFilename("Save md380 codeplug file")
+		title := fmt.Sprintf("Save %s codeplug file", edt.codeplug.Type())
+		filename = ui.SaveFilename(title)
 		if filename == "" {
 			return
 		}
@@ -139,11 +148,140 @@ func (edt *editor) saveAs(filename string) {
 	err := edt.codeplug.SaveAs(filename)
 	if err != nil {
 		title := fmt.Sprintf("%s: save failed", filename)
-		ui.WarningPopup(title, err.Error())
+		edt.validationFailed(title, err)
 		return
 	}
 
 	os.Remove(autosaveFilename)
+	os.Remove(autosaveFilename + codeplug.HistorySuffix)
+	edt.codeplug.SaveHistoryTo(filename + codeplug.HistorySuffix)
+	edt.mainWindow.SetTitle(filename + edt.titleSuffix())
+}
+
+// maxIssueLines limits the number of invalid fields listed in a popup.
+const maxIssueLines = 20
+
+// validationMessage returns a message describing err.  If err describes
+// invalid fields, at most maxIssueLines of them are listed.
+func validationMessage(err error) string {
+	vErr, ok := err.(*codeplug.ValidationError)
+	if !ok {
+		return err.Error()
+	}
+
+	lines := []string{}
+	count := 0
+	for _, issue := range vErr.Issues {
+		if issue.Severity != codeplug.SeverityError {
+			continue
+		}
+		if count < maxIssueLines {
+			lines = append(lines, issue.String())
+		}
+		count++
+	}
+	if count > maxIssueLines {
+		more := fmt.Sprintf("...and %d more", count-maxIssueLines)
+		lines = append(lines, more)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// validationFailed reports err and, if err describes invalid fields,
+// shows the first of them.
+func (edt *editor) validationFailed(title string, err error) {
+	ui.WarningPopup(title, validationMessage(err))
+
+	vErr, ok := err.(*codeplug.ValidationError)
+	if !ok {
+		return
+	}
+
+	for _, issue := range vErr.Issues {
+		if issue.Severity == codeplug.SeverityError {
+			edt.showIssue(issue)
+			break
+		}
+	}
+}
+
+// showIssue opens the window containing the field of the given issue
+// and moves the focus to that field.
+func (edt *editor) showIssue(issue codeplug.Issue) {
+	switch issue.RecordType {
+	case codeplug.RtGeneralSettings:
+		generalSettings(edt)
+	case codeplug.RtChannelInformation:
+		channelInformation(edt)
+	case codeplug.RtDigitalContacts:
+		digitalContacts(edt)
+	case codeplug.RtGroupList:
+		groupLists(edt)
+	case codeplug.RtScanList:
+		scanLists(edt)
+	case codeplug.RtZoneInformation:
+		zoneInformation(edt)
+	case codeplug.RtGpsSystem:
+		gpsSystems(edt)
+	default:
+		return
+	}
+
+	w := edt.mainWindow.RecordWindows()[issue.RecordType]
+	if w != nil {
+		w.FocusField(issue.RecordIndex, issue.FieldType)
+	}
+}
+
+// maxLintRows limits the number of issues listed by checkCodeplug.
+const maxLintRows = 50
+
+// checkCodeplug opens a window listing the programming mistakes found
+// in the codeplug by its lint rules.  Each issue's Show button opens
+// the window containing the issue's record.
+func checkCodeplug(edt *editor) {
+	w := edt.mainWindow.NewWindow()
+	w.SetTitle("Check codeplug")
+	column := w.AddVbox()
+	issueBox := column.AddHbox().AddVbox()
+
+	check := func() {
+		issueBox.Clear()
+		issues := edt.codeplug.Lint()
+		if len(issues) == 0 {
+			issueBox.AddLabel("No problems found.")
+		}
+		for i, issue := range issues {
+			if i == maxLintRows {
+				more := fmt.Sprintf("...and %d more", len(issues)-maxLintRows)
+				issueBox.AddLabel(more)
+				break
+			}
+			issue := issue
+			row := issueBox.AddHbox()
+			row.AddLabel(fmt.Sprintf("%s: %s (%s)",
+				issue.Severity, issue.String(), issue.Rule))
+			row.AddFiller()
+			show := row.AddButton("Show")
+			show.ConnectClicked(func() {
+				edt.showIssue(issue)
+			})
+		}
+	}
+	check()
+
+	row := column.AddHbox()
+	row.AddFiller()
+	again := row.AddButton("Check Again")
+	closeButton := row.AddButton("Close")
+
+	again.ConnectClicked(check)
+	closeButton.ConnectClicked(func() {
+		w.Close()
+	})
+
+	w.Show()
 }
 
 func (edt *editor) setAutosaveInterval(seconds int) {
@@ -162,6 +300,9 @@ func (edt *editor) setAutosaveInterval(seconds int) {
 
 func (edt *editor) autosave() {
 	cp := edt.codeplug
+	if cp.Filename() == "" {
+		return
+	}
 	filename := cp.Filename() + autosaveSuffix
 
 	hash := cp.CurrentHash()
@@ -171,8 +312,12 @@ func (edt *editor) autosave() {
 	edt.codeplugHash = hash
 
 	err := cp.SaveToFile(filename)
+	if err == nil {
+		err = cp.SaveHistoryTo(filename + codeplug.HistorySuffix)
+	}
 	if err != nil {
 		os.Remove(filename)
+		os.Remove(filename + codeplug.HistorySuffix)
 	}
 }
 
@@ -182,6 +327,7 @@ func main() {
 	app.SetApplicationName("Codeplug Editor")
 	appSettings = app.NewSettings()
 	loadSettings()
+	loadBandPlanSettings()
 
 	filenames := os.Args[1:]
 	if len(filenames) == 0 {
@@ -189,7 +335,7 @@ func main() {
 	}
 
 	for _, filename := range filenames {
-		newEditor(app, filename)
+		newEditor(app, nil, filename)
 	}
 
 	if len(editors) == 0 {
@@ -244,16 +390,25 @@ func (edt *editor) openCodeplugFile(filename string) {
 	if edt.codeplug == nil {
 		checkAutosave(filename)
 
-		cp, err := codeplug.NewCodeplug(filename, codeplug.CtMd380)
+		model, ok := codeplugModel(filename, int(fInfo.Size()))
+		if !ok {
+			return
+		}
+		cp, err := codeplug.NewCodeplug(filename, model)
 		if err != nil {
-			ui.WarningPopup("Codeplug Error", err.Error())
+			ui.WarningPopup("Codeplug Error", validationMessage(err))
 			return
 		}
 
+		// The history is restored only if it was saved along
+		// with the file's current contents.
+		cp.LoadHistoryFrom(filename + codeplug.HistorySuffix)
+
 		edt.codeplug = cp
 		edt.codeplugHash = edt.codeplug.CurrentHash()
 		loadSettings()
 		edt.setAutosaveInterval(settings.autosaveInterval)
+		cp.SetBandPlan(codeplug.BandPlanByName(settings.bandPlan))
 	}
 
 	addRecentFile(filename)
@@ -268,7 +423,37 @@ func (edt *editor) openCodeplugFile(filename string) {
 	edt.codeplugCount = highCount + 1
 }
 
-func newEditor(app *ui.App, filename string) {
+// codeplugModel returns the codeplug type of the named file, given its
+// size.  Several models share a file size, and their files can't be
+// told apart, so the user is asked to choose among them, with the
+// preferred model chosen initially.  It returns false if the user
+// cancels.
+func codeplugModel(filename string, size int) (codeplug.CodeplugType, bool) {
+	model := codeplug.CodeplugType(settings.model)
+	cpTypes := codeplug.CodeplugTypesForSize(size)
+	switch len(cpTypes) {
+	case 0:
+		return model, true
+	case 1:
+		return cpTypes[0], true
+	}
+
+	strs := make([]string, len(cpTypes))
+	for i, cpType := range cpTypes {
+		strs[i] = string(cpType)
+	}
+	msg := fmt.Sprintf("Radio model of %s:", filepath.Base(filename))
+	choice := ui.ChoicePopup("Codeplug Model", msg, strs, settings.model)
+	if choice == "" {
+		return "", false
+	}
+
+	return codeplug.CodeplugType(choice), true
+}
+
+// newEditor opens a main window editing the named codeplug file, or, if
+// blankCp is non-nil, the new, unsaved codeplug blankCp.
+func newEditor(app *ui.App, blankCp *codeplug.Codeplug, filename string) {
 	var edt *editor
 	for _, ed := range editors {
 		if ed.codeplug == nil {
@@ -289,7 +474,10 @@ func newEditor(app *ui.App, filename string) {
 		edt.mainWindow = mw
 	}
 
-	if filename != "" {
+	if blankCp != nil {
+		edt.codeplug = blankCp
+		edt.codeplugCount = 1
+	} else if filename != "" {
 		edt.openCodeplugFile(filename)
 	}
 
@@ -299,7 +487,9 @@ func newEditor(app *ui.App, filename string) {
 	}
 
 	title := "md-380 Codeplug Editor"
-	if cp != nil {
+	if blankCp != nil {
+		title = fmt.Sprintf("Untitled %s codeplug", blankCp.Type())
+	} else if cp != nil {
 		title = filename + edt.titleSuffix()
 	}
 	mw.SetTitle(title)
@@ -339,6 +529,7 @@ func newEditor(app *ui.App, filename string) {
 
 		asFilename := filename + autosaveSuffix
 		os.Remove(asFilename)
+		os.Remove(asFilename + codeplug.HistorySuffix)
 		return true
 	})
 
@@ -349,12 +540,16 @@ func newEditor(app *ui.App, filename string) {
 	mb := mw.MenuBar()
 	mb.Clear()
 	menu := mb.AddMenu("File")
+	menu.AddAction("New...", func() {
+		edt.newCodeplug()
+	})
 	menu.AddAction("Open...", func() {
-		filename = ui.OpenFilename("Open md380 codeplug file")
+		title := fmt.Sprintf("Open %s codeplug file", settings.model)
+		filename = ui.OpenFilename(title)
 		if filename == "" {
 			return
 		}
-		newEditor(edt.app, filename)
+		newEditor(edt.app, nil, filename)
 	})
 	recentMenu := menu.AddMenu("Open Recent...")
 	recentMenu.ConnectAboutToShow(func() {
@@ -373,6 +568,14 @@ func newEditor(app *ui.App, filename string) {
 		edt.importText()
 	}).SetDisabled(cp == nil)
 
+	menu.AddAction("Import channels from CHIRP CSV file...", func() {
+		edt.importChirp()
+	}).SetDisabled(cp == nil)
+
+	menu.AddAction("Import contacts from user database CSV file...", func() {
+		edt.importContacts()
+	}).SetDisabled(cp == nil)
+
 	menu.AddAction("Save", func() {
 		edt.save()
 	}).SetDisabled(cp == nil)
@@ -422,6 +625,32 @@ func newEditor(app *ui.App, filename string) {
 		zoneInformation(edt)
 	}).SetDisabled(cp == nil)
 
+	hasGps := cp != nil && cp.HasRecordType(codeplug.RtGpsSystem)
+
+	menu.AddAction("GPS Systems", func() {
+		gpsSystems(edt)
+	}).SetDisabled(!hasGps)
+
+	tableMenu := menu.AddMenu("Table View")
+	tableMenu.SetEnabled(cp != nil)
+	for _, rType := range tableRecordTypes {
+		if cp == nil || !cp.HasRecordType(rType) {
+			continue
+		}
+		rType := rType
+		tableMenu.AddAction(cp.RecordTypeName(rType), func() {
+			recordTable(edt, rType)
+		}).SetDisabled(len(cp.Records(rType)) == 0)
+	}
+
+	menu.AddAction("Check codeplug...", func() {
+		checkCodeplug(edt)
+	}).SetDisabled(cp == nil)
+
+	menu.AddAction("Hex Inspector...", func() {
+		hexInspector(edt)
+	}).SetDisabled(cp == nil)
+
 	edt.undoAction = menu.AddAction("Undo", func() {
 		edt.codeplug.UndoChange()
 	})
@@ -469,6 +698,10 @@ func newEditor(app *ui.App, filename string) {
 	ziButton.SetDisabled(cp == nil)
 	ziButton.ConnectClicked(func() { zoneInformation(edt) })
 
+	gpsButton := column.AddButton("GPS Systems")
+	gpsButton.SetDisabled(!hasGps)
+	gpsButton.ConnectClicked(func() { gpsSystems(edt) })
+
 	column.AddFiller()
 	row.AddSeparator()
 
@@ -531,7 +764,7 @@ func (edt *editor) updateRecentMenu(menu *ui.Menu) {
 	for i := range settings.recentFiles {
 		filename := settings.recentFiles[i]
 		menu.AddAction(filename, func() {
-			newEditor(edt.app, filename)
+			newEditor(edt.app, nil, filename)
 		})
 	}
 	menu.SetEnabled(len(settings.recentFiles) != 0)
@@ -622,6 +855,166 @@ func (edt *editor) importText() {
 	ui.ResetWindows(edt.codeplug)
 }
 
+func (edt *editor) importChirp() {
+	filename := ui.OpenFilename("Import channels from CHIRP CSV file")
+	if filename == "" {
+		return
+	}
+
+	skipped, err := edt.codeplug.ImportChirpCsvFrom(filename)
+	if err != nil {
+		title := fmt.Sprintf("Import from %s failed", filename)
+		ui.WarningPopup(title, err.Error())
+		return
+	}
+
+	if len(skipped) > 0 {
+		title := fmt.Sprintf("Import from %s", filename)
+		msg := "These rows could not be imported:\n"
+		for _, s := range skipped {
+			msg += s.String() + "\n"
+		}
+		ui.WarningPopup(title, msg)
+	}
+}
+
+func (edt *editor) newCodeplug() {
+	model := codeplug.CodeplugType(settings.model)
+	var models []string
+	for _, cpType := range codeplug.CodeplugTypes() {
+		models = append(models, string(cpType))
+	}
+
+	w := edt.mainWindow.NewWindow()
+	w.SetTitle("New codeplug")
+	column := w.AddVbox()
+	form := column.AddForm()
+
+	var fRanges []codeplug.FrequencyRange
+	var fRange codeplug.FrequencyRange
+	rangeBox := column.AddHbox()
+	setRanges := func() {
+		fRanges = codeplug.FrequencyRanges(model)
+		fRange = fRanges[0]
+		var strs []string
+		for _, r := range fRanges {
+			strs = append(strs, r.String())
+		}
+		rangeBox.Clear()
+		rangeForm := rangeBox.AddForm()
+		rangeForm.AddRow("Frequency range:", ui.NewCombobox(strs[0], strs, func(s string) {
+			for _, r := range fRanges {
+				if r.String() == s {
+					fRange = r
+				}
+			}
+		}))
+	}
+
+	form.AddRow("Model:", ui.NewCombobox(string(model), models, func(s string) {
+		model = codeplug.CodeplugType(s)
+		setRanges()
+	}))
+	setRanges()
+
+	row := column.AddHbox()
+	row.AddFiller()
+	cancel := row.AddButton("Cancel")
+	create := row.AddButton("Create")
+
+	cancel.ConnectClicked(func() {
+		w.Close()
+	})
+
+	create.ConnectClicked(func() {
+		w.Close()
+
+		cp, err := codeplug.NewBlankCodeplug(model, fRange)
+		if err != nil {
+			ui.WarningPopup("New codeplug failed", validationMessage(err))
+			return
+		}
+		cp.SetBandPlan(codeplug.BandPlanByName(settings.bandPlan))
+		newEditor(edt.app, cp, "")
+	})
+
+	w.Show()
+}
+
+func (edt *editor) importContacts() {
+	var countries, states, idPrefixes string
+
+	w := edt.mainWindow.NewWindow()
+	w.SetTitle("Import contacts from user database CSV file")
+	column := w.AddVbox()
+	groupBox := column.AddGroupbox("Import only users matching")
+	form := groupBox.AddForm()
+	form.AddRow("Countries:", ui.NewLineEdit("", func(s string) {
+		countries = s
+	}))
+	form.AddRow("States:", ui.NewLineEdit("", func(s string) {
+		states = s
+	}))
+	form.AddRow("Radio ID prefixes:", ui.NewLineEdit("", func(s string) {
+		idPrefixes = s
+	}))
+	column.AddLabel("Separate multiple values with commas.\n" +
+		"Leave a value empty to match all users.")
+
+	row := column.AddHbox()
+	row.AddFiller()
+	cancel := row.AddButton("Cancel")
+	importButton := row.AddButton("Import...")
+
+	cancel.ConnectClicked(func() {
+		w.Close()
+	})
+
+	importButton.ConnectClicked(func() {
+		filename := ui.OpenFilename("Import contacts from user database CSV file")
+		if filename == "" {
+			return
+		}
+		w.Close()
+
+		filter := codeplug.ContactFilter{
+			Countries:  splitList(countries),
+			States:     splitList(states),
+			IDPrefixes: splitList(idPrefixes),
+		}
+		skipped, err := edt.codeplug.ImportContactsCsvFrom(filename, filter)
+		if err != nil {
+			title := fmt.Sprintf("Import from %s failed", filename)
+			ui.WarningPopup(title, err.Error())
+			return
+		}
+
+		if len(skipped) > 0 {
+			title := fmt.Sprintf("Import from %s", filename)
+			msg := fmt.Sprintf("%d users were not imported:\n", len(skipped))
+			for _, s := range skipped {
+				msg += s.String() + "\n"
+			}
+			ui.WarningPopup(title, msg)
+		}
+	})
+
+	w.Show()
+}
+
+// splitList returns the comma-separated values of s, or nil if s is empty.
+func splitList(s string) []string {
+	var strs []string
+	for _, str := range strings.Split(s, ",") {
+		str = strings.TrimSpace(str)
+		if str != "" {
+			strs = append(strs, str)
+		}
+	}
+
+	return strs
+}
+
 func about() {
 	msg := fmt.Sprintf("editcp Version %s\n", version)
 	msg += `
@@ -686,7 +1079,8 @@ func (edt *editor) recordWindow(rType codeplug.RecordType, fillRecord fillRecord
 	var rl *ui.RecordList
 	var recordFunc func()
 
-	if cp.MaxRecords(rType) == 1 {
+	switch {
+	case cp.MaxRecords(rType) == 1:
 		selectorBox := windowBox.AddVbox()
 		selectorBox.SetContentsMargins(0, 0, 0, 0)
 		recordFunc = func() {
@@ -696,7 +1090,22 @@ func (edt *editor) recordWindow(rType codeplug.RecordType, fillRecord fillRecord
 			fillRecord(edt, recordBox)
 			w.EnableWidgets()
 		}
-	} else {
+
+	case r.NameField() == nil:
+		// Records without names can't be shown in a record
+		// list, so they are selected by index alone.
+		selectorBox := windowBox.AddVbox()
+		selectorBox.SetContentsMargins(0, 0, 0, 0)
+		recordFunc = func() {
+			selectorBox.Clear()
+			recordBox := selectorBox.AddHbox()
+			recordBox.SetContentsMargins(0, 0, 0, 0)
+			fillRecord(edt, recordBox)
+			addRecordSelector(selectorBox)
+			w.EnableWidgets()
+		}
+
+	default:
 		rl = windowBox.AddRecordList(rType)
 		if rl.Current() < 0 {
 			rl.SetCurrent(0)
@@ -728,38 +1137,54 @@ func addRecordSelector(box *ui.VBox) {
 	row.SetContentsMargins(0, 0, 0, 0)
 
 	decrement := row.AddButton("<")
-	rIndex := rl.Current()
+	rIndex := w.CurrentRecordIndex()
 	records := cp.Records(rType)
 	row.AddButton(fmt.Sprintf("%d of %d", rIndex+1, len(records)))
 	increment := row.AddButton(">")
-	row.AddSpace(3)
-	add := row.AddButton("Add")
-	row.AddSpace(3)
-	delete := row.AddButton("Delete")
-	row.AddFiller()
-	box.AddFiller()
 
 	decrement.ConnectClicked(func() {
-		rIndex := rl.Current()
+		rIndex := w.CurrentRecordIndex()
 		if rIndex <= 0 {
 			return
 		}
 
 		rIndex--
-		rl.SetCurrent(rIndex)
+		w.SetCurrentRecordIndex(rIndex)
 	})
 
 	increment.ConnectClicked(func() {
-		rIndex := rl.Current()
+		rIndex := w.CurrentRecordIndex()
 		records := cp.Records(rType)
 		if rIndex >= len(records)-1 {
 			return
 		}
 
 		rIndex++
-		rl.SetCurrent(rIndex)
+		w.SetCurrentRecordIndex(rIndex)
 	})
 
+	// Records without a record list are neither added nor deleted.
+	if rl == nil {
+		row.AddFiller()
+		box.AddFiller()
+		return
+	}
+
+	row.AddSpace(3)
+	add := row.AddButton("Add")
+	row.AddSpace(3)
+	delete := row.AddButton("Delete")
+	row.AddSpace(3)
+	setField := row.AddButton("Set Field...")
+	row.AddFiller()
+	box.AddFiller()
+
+	// Records such as zones and group lists have only a name and
+	// a list of members, and no field that can be set.
+	if records := cp.Records(rType); len(records) > 0 {
+		setField.SetDisabled(len(settableFieldTypes(records[0])) == 0)
+	}
+
 	add.ConnectClicked(func() {
 		err := rl.AddSelected()
 		if err != nil {
@@ -775,14 +1200,106 @@ func addRecordSelector(box *ui.VBox) {
 			return
 		}
 	})
+
+	setField.ConnectClicked(func() {
+		setFieldOnSelected(w, rl.SelectedRecords())
+	})
 }
 
-func currentRecord(w *ui.Window) *codeplug.Record {
-	rIndex := 0
-	rl := w.RecordList()
-	if rl != nil {
-		rIndex = rl.Current()
+// setFieldOnSelected shows a window in which a field may be set to a
+// single value in all of the given records.
+func setFieldOnSelected(parent *ui.Window, records []*codeplug.Record) {
+	if len(records) == 0 {
+		ui.WarningPopup("Set Field", "no records selected")
+		return
 	}
+
+	r := records[0]
+	fTypes := settableFieldTypes(r)
+	if len(fTypes) == 0 {
+		msg := fmt.Sprintf("%s records have no fields that can be set", r.TypeName())
+		ui.WarningPopup("Set Field", msg)
+		return
+	}
+	fTypeNames := make([]string, len(fTypes))
+	for i, fType := range fTypes {
+		fTypeNames[i] = r.Field(fType).TypeName()
+	}
+	fType := fTypes[0]
+	var value string
+
+	w := parent.MainWindow().NewWindow()
+	w.SetTitle(fmt.Sprintf("Set field on %d selected %s", len(records), r.TypeName()))
+	column := w.AddVbox()
+	form := column.AddForm()
+	valueBox := column.AddHbox()
+
+	setValues := func() {
+		f := r.Field(fType)
+		value = f.String()
+		valueBox.Clear()
+		valueForm := valueBox.AddForm()
+		changed := func(s string) {
+			value = s
+		}
+		switch f.ValueType() {
+		case codeplug.VtListIndex, codeplug.VtIStrings,
+			codeplug.VtIndexedStrings, codeplug.VtCtcssDcs:
+			valueForm.AddRow("Value:", ui.NewCombobox(value, f.Strings(), changed))
+		default:
+			valueForm.AddRow("Value:", ui.NewLineEdit(value, changed))
+		}
+	}
+
+	form.AddRow("Field:", ui.NewCombobox(fTypeNames[0], fTypeNames, func(s string) {
+		for i, name := range fTypeNames {
+			if name == s {
+				fType = fTypes[i]
+			}
+		}
+		setValues()
+	}))
+	setValues()
+
+	row := column.AddHbox()
+	row.AddFiller()
+	cancel := row.AddButton("Cancel")
+	set := row.AddButton("Set")
+
+	cancel.ConnectClicked(func() {
+		w.Close()
+	})
+
+	set.ConnectClicked(func() {
+		cp := r.Codeplug()
+		err := cp.SetFields(records, fType, value)
+		if err != nil {
+			ui.WarningPopup("Set Field", err.Error())
+			return
+		}
+		w.Close()
+	})
+
+	w.Show()
+}
+
+// settableFieldTypes returns the types of the record's fields that may
+// be set by setFieldOnSelected, those having a single value, other than
+// the record's name.
+func settableFieldTypes(r *codeplug.Record) []codeplug.FieldType {
+	var fTypes []codeplug.FieldType
+	for _, fType := range r.FieldTypes() {
+		if r.MaxFields(fType) != 1 || fType == r.NameFieldType() {
+			continue
+		}
+		fTypes = append(fTypes, fType)
+	}
+
+	return fTypes
+}
+
+func currentRecord(w *ui.Window) *codeplug.Record {
+	rIndex := w.CurrentRecordIndex()
 	records := w.MainWindow().Codeplug().Records(w.RecordType())
 
 	return records[rIndex]
@@ -801,6 +1318,9 @@ func loadSettings() {
 	settings.sortAvailableChannels = as.Bool("sortAvailableChannels", false)
 	settings.sortAvailableContacts = as.Bool("sortAvailableContacts", false)
 	settings.autosaveInterval = as.Int("autosaveInterval", 1)
+	settings.model = as.String("model", string(codeplug.CtMd380))
+	settings.bandPlan = as.String("bandPlan", "")
+	settings.bandPlanFile = as.String("bandPlanFile", "")
 	size := as.BeginReadArray("recentFiles")
 	settings.recentFiles = make([]string, size)
 	for i := 0; i < size; i++ {
@@ -815,6 +1335,9 @@ func saveSettings() {
 	as.SetBool("sortAvailableChannels", settings.sortAvailableChannels)
 	as.SetBool("sortAvailableContacts", settings.sortAvailableContacts)
 	as.SetInt("autosaveInterval", settings.autosaveInterval)
+	as.SetString("model", settings.model)
+	as.SetString("bandPlan", settings.bandPlan)
+	as.SetString("bandPlanFile", settings.bandPlanFile)
 	as.BeginWriteArray("recentFiles", len(settings.recentFiles))
 	for i, name := range settings.recentFiles {
 		as.SetArrayIndex(i)
@@ -0,0 +1,91 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Editcp.
+//
+// Editcp is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// Editcp is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Editcp.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dalefarnsworth/codeplug/codeplug"
+	"github.com/dalefarnsworth/codeplug/ui"
+)
+
+// hexInspector opens a window showing the codeplug's rdt image as a
+// hex dump.  Selecting a byte shows the record, field and bits that
+// hold it.  Bytes may be edited in place, each edit being a change
+// that may be undone.
+func hexInspector(edt *editor) {
+	cp := edt.codeplug
+	w := edt.mainWindow.NewWindow()
+	w.SetTitle("Hex Inspector")
+	column := w.AddVbox()
+
+	hv := column.AddHbox().AddHexView()
+	hv.SetBytes(0, cp.ImageBytes())
+
+	location := column.AddLabel("")
+	current := 0
+	showLocation := func(offset int) {
+		current = offset
+		l, err := cp.Locate(offset)
+		if err != nil {
+			location.SetText(err.Error())
+			return
+		}
+		location.SetText(l.String())
+	}
+	showLocation(current)
+
+	hv.ConnectCurrentChanged(showLocation)
+	hv.ConnectSet(func(offset int, b byte) error {
+		return cp.SetBytes(offset, []byte{b})
+	})
+
+	cancel := cp.SubscribeChanges(func(change *codeplug.Change) {
+		hv.SetBytes(0, cp.ImageBytes())
+		showLocation(current)
+	})
+	w.ConnectClose(func() bool {
+		cancel()
+		return true
+	})
+
+	form := column.AddForm()
+	form.AddRow("Go to offset:", ui.NewLineEdit("", func(str string) {
+		str = strings.TrimPrefix(strings.TrimSpace(str), "0x")
+		offset, err := strconv.ParseUint(str, 16, 32)
+		if err == nil {
+			hv.SetCurrent(int(offset))
+		}
+	}))
+
+	row := column.AddHbox()
+	row.AddFiller()
+	closeButton := row.AddButton("Close")
+	closeButton.ConnectClicked(func() {
+		w.Close()
+	})
+
+	w.Show()
+}
@@ -24,6 +24,7 @@
 package main
 
 import (
+	"github.com/dalefarnsworth/codeplug/codeplug"
 	"github.com/dalefarnsworth/codeplug/ui"
 )
 
@@ -47,5 +48,97 @@ func (edt *editor) preferences() {
 	})
 	form.AddRow("Auto Save interval (minutes):", spinbox)
 
+	groupBox = column.AddGroupbox("Radio")
+	form = groupBox.AddForm()
+
+	models := []string{}
+	for _, cpType := range codeplug.CodeplugTypes() {
+		models = append(models, string(cpType))
+	}
+	combobox := ui.NewCombobox(settings.model, models, func(s string) {
+		settings.model = s
+		saveSettings()
+	})
+	form.AddRow("Model of codeplug files opened:", combobox)
+
+	groupBox = column.AddGroupbox("Band Plan")
+	box := groupBox.AddVbox()
+	form = box.AddForm()
+
+	plans := []string{noBandPlan}
+	for _, bp := range codeplug.BandPlans() {
+		plans = append(plans, bp.Name)
+	}
+	bandPlan := settings.bandPlan
+	if codeplug.BandPlanByName(bandPlan) == nil {
+		bandPlan = noBandPlan
+	}
+	combobox = ui.NewCombobox(bandPlan, plans, func(s string) {
+		if s == noBandPlan {
+			s = ""
+		}
+		settings.bandPlan = s
+		saveSettings()
+		applyBandPlan()
+	})
+	form.AddRow("Band plan for repeater offsets:", combobox)
+
+	button := box.AddButton("Load Band Plan File...")
+	button.ConnectClicked(func() {
+		edt.loadBandPlanFile()
+	})
+
 	edt.prefWindow.Show()
 }
+
+// noBandPlan is the band plan choice that disables the band plan.
+const noBandPlan = "None"
+
+// loadBandPlanFile loads the band plans in a user-chosen file,
+// selects the first of them, and remembers the file so that its plans
+// are loaded again when the editor is next started.
+func (edt *editor) loadBandPlanFile() {
+	filename := ui.OpenFilename("Load band plan file")
+	if filename == "" {
+		return
+	}
+
+	plans, err := codeplug.LoadBandPlanFile(filename)
+	if err != nil {
+		ui.WarningPopup("Band Plan Error", err.Error())
+		return
+	}
+
+	settings.bandPlanFile = filename
+	if len(plans) > 0 {
+		settings.bandPlan = plans[0].Name
+	}
+	saveSettings()
+	applyBandPlan()
+
+	// Rebuild the window so that its list includes the new plans.
+	edt.prefWindow.Close()
+	edt.prefWindow = nil
+	edt.preferences()
+}
+
+// applyBandPlan sets the band plan of every open codeplug to the
+// preferred band plan.
+func applyBandPlan() {
+	bp := codeplug.BandPlanByName(settings.bandPlan)
+	for _, cp := range codeplug.Codeplugs() {
+		cp.SetBandPlan(bp)
+	}
+}
+
+// loadBandPlanSettings loads the preferred band plan file, if any.
+func loadBandPlanSettings() {
+	if settings.bandPlanFile == "" {
+		return
+	}
+
+	_, err := codeplug.LoadBandPlanFile(settings.bandPlanFile)
+	if err != nil {
+		ui.WarningPopup("Band Plan Error", err.Error())
+	}
+}
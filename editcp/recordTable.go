@@ -0,0 +1,72 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Editcp.
+//
+// Editcp is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// Editcp is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Editcp.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"github.com/dalefarnsworth/codeplug/codeplug"
+	"github.com/dalefarnsworth/codeplug/ui"
+)
+
+// tableRecordTypes are the record types that may be viewed as tables.
+var tableRecordTypes = []codeplug.RecordType{
+	codeplug.RtChannelInformation,
+	codeplug.RtDigitalContacts,
+	codeplug.RtGroupList,
+	codeplug.RtScanList,
+	codeplug.RtZoneInformation,
+	codeplug.RtGpsSystem,
+}
+
+// recordTable opens a window showing the records of the given type as
+// a table, one row per record.
+func recordTable(edt *editor, rType codeplug.RecordType) {
+	cp := edt.codeplug
+	w := edt.mainWindow.NewWindow()
+	typeName := cp.RecordTypeName(rType)
+	w.SetTitle(cp.Filename() + edt.titleSuffix() + " " + typeName + " Table")
+	column := w.AddVbox()
+
+	filterRow := column.AddHbox()
+	form := filterRow.AddForm()
+	tableRow := column.AddHbox()
+	t := tableRow.AddRecordTable(rType)
+	form.AddRow("Filter:", ui.NewLineEdit("", t.SetFilter))
+
+	cancel := cp.SubscribeChanges(func(change *codeplug.Change) {
+		t.Update()
+	})
+	w.ConnectClose(func() bool {
+		cancel()
+		return true
+	})
+
+	row := column.AddHbox()
+	row.AddFiller()
+	closeButton := row.AddButton("Close")
+	closeButton.ConnectClicked(func() {
+		w.Close()
+	})
+
+	w.Show()
+}
@@ -0,0 +1,170 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of GenLibTypes.
+//
+// GenLibTypes is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// GenLibTypes is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with GenLibTypes.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+// Band plans are read from a JSON file, in the form read by the codeplug
+// library's LoadBandPlans, and written as the Go code of the library's
+// built-in band plans.
+
+type BandPlans struct {
+	Comments  []string    `json:"comments"`
+	BandPlans []*BandPlan `json:"bandPlans"`
+	Filename  string
+}
+
+type BandPlan struct {
+	Name  string      `json:"name"`
+	Bands []*PlanBand `json:"bands"`
+}
+
+type PlanBand struct {
+	Name     string         `json:"name"`
+	Low      float64        `json:"low"`
+	High     float64        `json:"high"`
+	Segments []*PlanSegment `json:"segments"`
+}
+
+type PlanSegment struct {
+	Low    float64 `json:"low"`
+	High   float64 `json:"high"`
+	Use    string  `json:"use"`
+	Offset float64 `json:"offset"`
+	Note   string  `json:"note"`
+}
+
+// segmentUses maps the segment uses of the JSON file to the names of
+// the library's SegmentUse constants.
+var segmentUses = map[string]string{
+	"simplex":        "SegmentSimplex",
+	"repeaterOutput": "SegmentRepeaterOutput",
+	"repeaterInput":  "SegmentRepeaterInput",
+	"noTransmit":     "SegmentNoTransmit",
+}
+
+const bandPlansCode = `// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Codeplug.
+//
+// Codeplug is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Codeplug is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Codeplug.  If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by genCodeplugInfo from {{.Filename}}. DO NOT EDIT.
+
+package codeplug
+
+// builtinBandPlans holds the band plans of {{.Filename}}.
+{{- if .Comments}}
+//
+{{- range $c := .Comments}}
+//{{if $c}} {{$c}}{{end}}
+{{- end}}
+{{- end}}
+var builtinBandPlans = []*BandPlan{
+{{- range $bp := .BandPlans}}
+	{
+		Name: {{printf "%q" $bp.Name}},
+		Bands: []*Band{
+		{{- range $b := $bp.Bands}}
+			{
+				Name: {{printf "%q" $b.Name}},
+				Low: {{$b.Low}},
+				High: {{$b.High}},
+				Segments: []*Segment{
+				{{- range $s := $b.Segments}}
+					{Low: {{$s.Low}}, High: {{$s.High}}, Use: {{use $s.Use}}
+					{{- if $s.Offset}}, Offset: {{$s.Offset}}{{end}}
+					{{- if $s.Note}}, Note: {{printf "%q" $s.Note}}{{end}}},
+				{{- end}}
+				},
+			},
+		{{- end}}
+		},
+	},
+{{- end}}
+}
+`
+
+// writeBandPlans writes the band plans of the JSON file inFilename as
+// Go code to codeFilename.
+func writeBandPlans(codeFilename string, inFilename string) {
+	bytes, err := ioutil.ReadFile(inFilename)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var bps BandPlans
+	err = json.Unmarshal(bytes, &bps)
+	if err != nil {
+		log.Fatal(fmt.Errorf("%s: %s", inFilename, err))
+	}
+	bps.Filename = filepath.Base(inFilename)
+
+	for _, bp := range bps.BandPlans {
+		for _, b := range bp.Bands {
+			for _, s := range b.Segments {
+				if segmentUses[s.Use] == "" {
+					log.Fatalf("%s: %s: %s: unknown segment use: %s",
+						inFilename, bp.Name, b.Name, s.Use)
+				}
+			}
+		}
+	}
+
+	funcs := template.FuncMap{
+		"use": func(use string) string { return segmentUses[use] },
+	}
+	t := template.Must(template.New("bandPlans").Funcs(funcs).Parse(bandPlansCode))
+	writeReferenceFile(codeFilename, func(w io.Writer) error {
+		return t.Execute(w, bps)
+	})
+
+	exec.Command("gofmt", "-w", codeFilename).Run()
+}
@@ -0,0 +1,421 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of GenLibTypes.
+//
+// GenLibTypes is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// GenLibTypes is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with GenLibTypes.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// The field reference describes the layout and values of every field
+// of every record type, for each codeplug type.  It is written from the
+// same codeplug descriptions as the generated code, so the two always
+// agree.
+
+type RefCodeplug struct {
+	Name    string
+	RdtSize int
+	BinSize int
+	Bands   []*Band
+	Records []*RefRecord
+}
+
+type RefRecord struct {
+	TypeName    string
+	Type        string
+	RdtOffset   string
+	BinOffset   string
+	Size        int
+	Max         int
+	DeletedWhen string
+	Fields      []*RefField
+}
+
+type RefField struct {
+	TypeName  string
+	Type      string
+	Byte      string
+	Mask      string
+	BitOffset int
+	BitSize   int
+	Max       int
+	ValueType string
+	Default   string
+	Values    string
+	Enabling  string
+	ListType  string
+}
+
+// fileOffsetBin is the offset of a codeplug's data in an .rdt file.
+// A .bin file contains the data without the .rdt header.
+const fileOffsetBin = 549
+
+// refCodeplugs returns the field reference descriptions of the codeplugs.
+func refCodeplugs() []*RefCodeplug {
+	rcs := []*RefCodeplug{}
+	for _, c := range codeplugs.Codeplugs {
+		rc := &RefCodeplug{
+			Name:    c.Name,
+			RdtSize: c.RdtSize,
+			BinSize: c.BinSize,
+			Bands:   c.Bands,
+		}
+		for _, r := range c.Records {
+			rc.Records = append(rc.Records, refRecord(r))
+		}
+		rcs = append(rcs, rc)
+	}
+
+	return rcs
+}
+
+func refRecord(r *Record) *RefRecord {
+	rr := &RefRecord{
+		TypeName:  r.TypeName,
+		Type:      r.Type,
+		RdtOffset: fmt.Sprintf("0x%05x", r.Offset),
+		BinOffset: "-",
+		Size:      r.Size,
+		Max:       r.Max,
+	}
+	if r.Offset >= fileOffsetBin {
+		rr.BinOffset = fmt.Sprintf("0x%05x", r.Offset-fileOffsetBin)
+	}
+
+	deleted := []string{}
+	for _, dd := range r.DelDescs {
+		str := fmt.Sprintf("byte 0x%02x is 0x%02x", dd.Offset, dd.Value)
+		if dd.Size > 1 {
+			str = fmt.Sprintf("bytes 0x%02x-0x%02x are all 0x%02x",
+				dd.Offset, dd.Offset+dd.Size-1, dd.Value)
+		}
+		deleted = append(deleted, str)
+	}
+	rr.DeletedWhen = strings.Join(deleted, " or ")
+
+	fields := make([]*Field, len(r.Fields))
+	copy(fields, r.Fields)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].BitOffset < fields[j].BitOffset
+	})
+
+	for _, f := range fields {
+		rr.Fields = append(rr.Fields, refField(r, f))
+	}
+
+	return rr
+}
+
+func refField(r *Record, f *Field) *RefField {
+	rf := &RefField{
+		TypeName:  f.TypeName,
+		Type:      f.Type,
+		Byte:      fmt.Sprintf("0x%02x", f.BitOffset/8),
+		Mask:      "-",
+		BitOffset: f.BitOffset,
+		BitSize:   f.BitSize,
+		Max:       f.Max,
+		ValueType: f.ValueType,
+		Default:   f.DefaultValue,
+		Values:    refValues(f),
+		Enabling:  refEnabling(r, f),
+	}
+
+	// Bits are numbered from the most significant bit of each byte.
+	if f.BitSize < 8 {
+		shift := 8 - (f.BitOffset%8 + f.BitSize)
+		rf.Mask = fmt.Sprintf("0x%02x", (1<<uint(f.BitSize)-1)<<uint(shift))
+	}
+
+	if f.ListType != nil {
+		rf.ListType = *f.ListType
+	}
+
+	return rf
+}
+
+// refValues returns a description of the values a field may contain,
+// along with the values stored for them, where they differ.
+func refValues(f *Field) string {
+	values := []string{}
+
+	switch f.ValueType {
+	case "onOff":
+		values = append(values, "0=On", "1=Off")
+
+	case "offOn":
+		values = append(values, "0=Off", "1=On")
+
+	case "iStrings":
+		if f.Strings != nil {
+			for i, s := range *f.Strings {
+				if s != "" {
+					values = append(values, fmt.Sprintf("%d=%s", i, s))
+				}
+			}
+		}
+	}
+
+	if f.IndexedStrings != nil {
+		for _, is := range *f.IndexedStrings {
+			values = append(values,
+				fmt.Sprintf("%d=%s", is.Index, is.String))
+		}
+	}
+
+	if f.Span != nil {
+		values = append(values, refSpan(f.Span)...)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// refSpan returns a description of the values of a span.
+func refSpan(sp *Span) []string {
+	values := []string{}
+
+	min := sp.Min
+	if sp.MinString != "" {
+		values = append(values, fmt.Sprintf("%d=%s", sp.Min, sp.MinString))
+		min += sp.Interval
+	}
+
+	str := fmt.Sprintf("%d-%d", min*sp.Scale, sp.Max*sp.Scale)
+	if sp.Interval != 1 || sp.Scale != 1 {
+		str += fmt.Sprintf(" in steps of %d", sp.Interval*sp.Scale)
+	}
+	if sp.Scale != 1 {
+		str += fmt.Sprintf(" (stored divided by %d)", sp.Scale)
+	}
+
+	return append(values, str)
+}
+
+// refEnabling returns a description of the fields a field enables or
+// disables, and of the field that enables or disables it.
+func refEnabling(r *Record, f *Field) string {
+	rules := []string{}
+
+	if f.Enabling != nil {
+		if len(f.Enabling.Enables) > 0 {
+			rules = append(rules, fmt.Sprintf("%s enables %s",
+				f.Enabling.Value, refFieldNames(r, f.Enabling.Enables)))
+		}
+		if len(f.Enabling.Disables) > 0 {
+			rules = append(rules, fmt.Sprintf("%s disables %s",
+				f.Enabling.Value, refFieldNames(r, f.Enabling.Disables)))
+		}
+	}
+
+	if f.Enabler != "" {
+		rules = append(rules, fmt.Sprintf("enabled when %s is %s",
+			refFieldNames(r, []string{f.Enabler}),
+			refEnablingValue(r, f.Enabler)))
+	}
+
+	if f.Disabler != "" {
+		rules = append(rules, fmt.Sprintf("disabled when %s is %s",
+			refFieldNames(r, []string{f.Disabler}),
+			refEnablingValue(r, f.Disabler)))
+	}
+
+	return strings.Join(rules, "; ")
+}
+
+// refFieldNames returns the names of the fields of the given types.
+func refFieldNames(r *Record, fTypes []string) string {
+	names := []string{}
+	for _, fType := range fTypes {
+		name := fType
+		for _, f := range r.Fields {
+			if f.Type == fType {
+				name = f.TypeName
+				break
+			}
+		}
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// refEnablingValue returns the enabling value of the field of the given
+// type.
+func refEnablingValue(r *Record, fType string) string {
+	for _, f := range r.Fields {
+		if f.Type == fType {
+			return f.EnablingValue
+		}
+	}
+
+	return ""
+}
+
+// markdownEscape escapes the characters that are special within a
+// Markdown table cell.
+func markdownEscape(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		"|", `\|`,
+		"*", `\*`,
+		"_", `\_`,
+		"<", "&lt;",
+	)
+
+	return replacer.Replace(s)
+}
+
+const markdownReference = `# Codeplug Field Reference
+
+This file is generated by genCodeplugInfo from codeplugs.json.
+Do not edit it.
+
+Record offsets are the file offsets of each codeplug's first record of
+a type, in .rdt files and in .bin files.  Each further record follows
+the previous one.  Field bytes are offsets within a record.  A field's
+mask selects its bits within that byte, for fields smaller than a byte.
+A field with a count greater than 1 is an array of values, each of the
+field's bit size.
+{{range $c := .}}
+## {{$c.Name}}
+
+.rdt size: {{$c.RdtSize}} bytes, .bin size: {{$c.BinSize}} bytes
+{{- if $c.Bands}}, bands:
+{{- range $i, $b := $c.Bands}}{{if $i}},{{end}} {{$b.Low}}-{{$b.High}} MHz{{end}}
+{{- end}}
+{{range $r := $c.Records}}
+### {{$c.Name}} {{$r.TypeName}} ({{$r.Type}})
+
+.rdt offset: {{$r.RdtOffset}}, .bin offset: {{$r.BinOffset}},
+size: {{$r.Size}} bytes, count: {{$r.Max}}
+{{- if $r.DeletedWhen}}, unused when {{$r.DeletedWhen}}{{end}}
+
+| Field | Type | Byte | Mask | Bit offset | Bit size | Count | Value type | Default | Values | Enabling | List of |
+| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |
+{{- range $f := $r.Fields}}
+| {{md $f.TypeName}} | {{$f.Type}} | {{$f.Byte}} | {{$f.Mask}} | {{$f.BitOffset}} | {{$f.BitSize}} | {{$f.Max}} | {{$f.ValueType}} | {{md $f.Default}} | {{md $f.Values}} | {{md $f.Enabling}} | {{$f.ListType}} |
+{{- end}}
+{{end}}
+{{- end}}`
+
+const htmlReference = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Codeplug Field Reference</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 2px 6px; vertical-align: top; }
+th { background: #eee; }
+</style>
+</head>
+<body>
+<!-- This file is generated by genCodeplugInfo from codeplugs.json.
+     Do not edit it. -->
+<h1>Codeplug Field Reference</h1>
+<p>
+Record offsets are the file offsets of each codeplug's first record of
+a type, in .rdt files and in .bin files.  Each further record follows
+the previous one.  Field bytes are offsets within a record.  A field's
+mask selects its bits within that byte, for fields smaller than a byte.
+A field with a count greater than 1 is an array of values, each of the
+field's bit size.
+</p>
+{{range $c := .}}
+<h2 id="{{$c.Name}}">{{$c.Name}}</h2>
+<p>
+.rdt size: {{$c.RdtSize}} bytes, .bin size: {{$c.BinSize}} bytes
+{{- if $c.Bands}}, bands:
+{{- range $i, $b := $c.Bands}}{{if $i}},{{end}} {{$b.Low}}-{{$b.High}} MHz{{end}}
+{{- end}}
+</p>
+{{range $r := $c.Records}}
+<h3 id="{{$c.Name}}-{{$r.Type}}">{{$c.Name}} {{$r.TypeName}} (<code>{{$r.Type}}</code>)</h3>
+<p>
+.rdt offset: {{$r.RdtOffset}}, .bin offset: {{$r.BinOffset}},
+size: {{$r.Size}} bytes, count: {{$r.Max}}
+{{- if $r.DeletedWhen}}, unused when {{$r.DeletedWhen}}{{end}}
+</p>
+<table>
+<tr><th>Field</th><th>Type</th><th>Byte</th><th>Mask</th><th>Bit offset</th><th>Bit size</th><th>Count</th><th>Value type</th><th>Default</th><th>Values</th><th>Enabling</th><th>List of</th></tr>
+{{- range $f := $r.Fields}}
+<tr><td>{{$f.TypeName}}</td><td>{{$f.Type}}</td><td>{{$f.Byte}}</td><td>{{$f.Mask}}</td><td>{{$f.BitOffset}}</td><td>{{$f.BitSize}}</td><td>{{$f.Max}}</td><td>{{$f.ValueType}}</td><td>{{$f.Default}}</td><td>{{$f.Values}}</td><td>{{$f.Enabling}}</td><td>
+{{- if $f.ListType}}<a href="#{{$c.Name}}-{{$f.ListType}}">{{$f.ListType}}</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{end}}
+{{- end}}
+</body>
+</html>
+`
+
+// writeReference writes the field reference of the codeplugs described
+// in inFilenames, in Markdown to mdFilename and in HTML to htmlFilename.
+// Either filename may be empty, in which case that file is not written.
+func writeReference(mdFilename, htmlFilename string, inFilenames []string) {
+	for _, filename := range inFilenames {
+		readCodeplugJson(filename)
+	}
+
+	rcs := refCodeplugs()
+
+	if mdFilename != "" {
+		funcs := template.FuncMap{"md": markdownEscape}
+		t := template.Must(template.New("markdown").Funcs(funcs).Parse(markdownReference))
+		writeReferenceFile(mdFilename, func(w io.Writer) error {
+			return t.Execute(w, rcs)
+		})
+	}
+
+	if htmlFilename != "" {
+		t := htmltemplate.Must(htmltemplate.New("html").Parse(htmlReference))
+		writeReferenceFile(htmlFilename, func(w io.Writer) error {
+			return t.Execute(w, rcs)
+		})
+	}
+}
+
+func writeReferenceFile(filename string, execute func(io.Writer) error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = execute(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
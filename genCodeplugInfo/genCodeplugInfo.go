@@ -26,6 +26,7 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"html/template"
 	"io"
@@ -45,8 +46,17 @@ type Codeplugs struct {
 }
 
 type Codeplug struct {
-	Name    string    `json:"name"`
-	Records []*Record `json:"records"`
+	Name     string    `json:"name"`
+	Inherits string    `json:"inherits"`
+	RdtSize  int       `json:"rdtSize"`
+	BinSize  int       `json:"binSize"`
+	Bands    []*Band   `json:"bands"`
+	Records  []*Record `json:"records"`
+}
+
+type Band struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
 }
 
 type Record struct {
@@ -57,6 +67,11 @@ type Record struct {
 	Max      int       `json:"max"`
 	DelDescs []DelDesc `json:"delDescs"`
 	Fields   []*Field  `json:"fields"`
+	NameType string
+
+	// UnknownMask holds the lines of the generated mask of the
+	// record's bits that no field describes.
+	UnknownMask []string
 }
 
 type DelDesc struct {
@@ -119,6 +134,8 @@ type TemplateVars struct {
 	FieldMap     FieldMap
 	ValueTypeMap ValueTypeMap
 	Codeplugs    []*Codeplug
+	RecordDocs   map[string]string
+	FieldDocs    map[string]string
 	Capitalize   func(string) string
 	SliceAfter2  func(string) string
 }
@@ -186,6 +203,98 @@ func doEnables(r *Record, f *Field) {
 	}
 }
 
+// findCodeplug returns the codeplug with the given name.
+func findCodeplug(name string) *Codeplug {
+	for _, c := range codeplugs.Codeplugs {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// inherit replaces the records of a codeplug that inherits from another
+// with a copy of the other codeplug's records, modified by its own.
+// A record whose type is not inherited is added.  Otherwise, the
+// record's fields replace the inherited fields of the same type, or are
+// added, and its non-zero offset, size, max and delDescs replace those
+// of the inherited record.
+func inherit(c *Codeplug) {
+	base := findCodeplug(c.Inherits)
+	if base == nil || base == c {
+		log.Fatalf("%s: bad inherits: %s", c.Name, c.Inherits)
+	}
+	if base.Inherits != "" {
+		inherit(base)
+	}
+
+	// Copy the inherited records, so that they may be modified.
+	bytes, err := json.Marshal(base.Records)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var records []*Record
+	err = json.Unmarshal(bytes, &records)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+nextRecord:
+	for _, r := range c.Records {
+		for _, br := range records {
+			if br.Type == r.Type {
+				inheritRecord(br, r)
+				continue nextRecord
+			}
+		}
+		records = append(records, r)
+	}
+
+	c.Records = records
+	c.Inherits = ""
+
+	if c.RdtSize == 0 {
+		c.RdtSize = base.RdtSize
+	}
+	if c.BinSize == 0 {
+		c.BinSize = base.BinSize
+	}
+	if c.Bands == nil {
+		c.Bands = base.Bands
+	}
+}
+
+// inheritRecord modifies the inherited record br by the record r.
+func inheritRecord(br *Record, r *Record) {
+	if r.TypeName != "" {
+		br.TypeName = r.TypeName
+	}
+	if r.Offset != 0 {
+		br.Offset = r.Offset
+	}
+	if r.Size != 0 {
+		br.Size = r.Size
+	}
+	if r.Max != 0 {
+		br.Max = r.Max
+	}
+	if r.DelDescs != nil {
+		br.DelDescs = r.DelDescs
+	}
+
+nextField:
+	for _, f := range r.Fields {
+		for i, bf := range br.Fields {
+			if bf.Type == f.Type {
+				br.Fields[i] = f
+				continue nextField
+			}
+		}
+		br.Fields = append(br.Fields, f)
+	}
+}
+
 func readCodeplugJson(filename string) {
 	bytes, err := ioutil.ReadFile(filename)
 	if err != nil {
@@ -198,6 +307,15 @@ func readCodeplugJson(filename string) {
 	}
 
 	for _, c := range codeplugs.Codeplugs {
+		if c.Inherits != "" {
+			inherit(c)
+		}
+	}
+
+	for _, c := range codeplugs.Codeplugs {
+		if c.RdtSize == 0 || c.BinSize == 0 {
+			log.Fatalf("%s: missing rdtSize or binSize", c.Name)
+		}
 		for _, r := range c.Records {
 			if r.Max == 0 {
 				r.Max = 1
@@ -211,6 +329,15 @@ func readCodeplugJson(filename string) {
 					if span.MinString != "" {
 						span.Min = 0
 					}
+					if span.Scale == 0 {
+						span.Scale = 1
+					}
+					if span.Interval == 0 {
+						span.Interval = 1
+					}
+				}
+				if f.ValueType == "name" {
+					r.NameType = f.Type
 				}
 				if f.Enabling != nil {
 					doEnables(r, f)
@@ -219,6 +346,19 @@ func readCodeplugJson(filename string) {
 		}
 	}
 
+	if errs := validateCodeplugs(); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", filename, err)
+		}
+		os.Exit(1)
+	}
+
+	for _, c := range codeplugs.Codeplugs {
+		for _, r := range c.Records {
+			r.UnknownMask = unknownMask(r)
+		}
+	}
+
 	templateVars.Codeplugs = codeplugs.Codeplugs
 
 	codeplugMap := map[string]int{}
@@ -241,6 +381,88 @@ func readCodeplugJson(filename string) {
 	templateVars.RecordMap = sortAndEnumTypes(recordMap)
 	templateVars.FieldMap = sortAndEnumTypes(fieldMap)
 	templateVars.ValueTypeMap = sortAndEnumTypes(valueTypeMap)
+	templateVars.RecordDocs, templateVars.FieldDocs = typeDocs()
+}
+
+// unknownMask returns, as lines of Go byte values, a mask of the bits
+// of a record that are not described by any of its fields, or nil if
+// there are none.
+func unknownMask(r *Record) []string {
+	mask := make([]byte, r.Size)
+	for i := range mask {
+		mask[i] = 0xff
+	}
+
+	for _, f := range r.Fields {
+		end := f.BitOffset + f.Max*f.BitSize
+		for bit := f.BitOffset; bit < end && bit/8 < len(mask); bit++ {
+			mask[bit/8] &^= 0x80 >> uint(bit%8)
+		}
+	}
+
+	unknown := false
+	for _, b := range mask {
+		if b != 0 {
+			unknown = true
+			break
+		}
+	}
+	if !unknown {
+		return nil
+	}
+
+	const bytesPerLine = 12
+	var lines []string
+	for i := 0; i < len(mask); i += bytesPerLine {
+		var strs []string
+		for j := i; j < i+bytesPerLine && j < len(mask); j++ {
+			strs = append(strs, fmt.Sprintf("0x%02x,", mask[j]))
+		}
+		lines = append(lines, strings.Join(strs, " "))
+	}
+
+	return lines
+}
+
+// typeDocs returns descriptions of each record type and each field
+// type, for the doc comments of their generated constants.
+func typeDocs() (recordDocs map[string]string, fieldDocs map[string]string) {
+	recordDocs = map[string]string{}
+	fieldNames := map[string]string{}
+	fieldRecords := map[string][]string{}
+
+	for _, c := range templateVars.Codeplugs {
+		for _, r := range c.Records {
+			if recordDocs[r.Type] == "" {
+				recordDocs[r.Type] = r.TypeName
+			}
+		nextField:
+			for _, f := range r.Fields {
+				if fieldNames[f.Type] == "" {
+					fieldNames[f.Type] = f.TypeName
+				}
+				for _, name := range fieldRecords[f.Type] {
+					if name == r.TypeName {
+						continue nextField
+					}
+				}
+				fieldRecords[f.Type] = append(fieldRecords[f.Type], r.TypeName)
+			}
+		}
+	}
+
+	fieldDocs = map[string]string{}
+	for fType, name := range fieldNames {
+		names := fieldRecords[fType]
+		records := names[0]
+		if len(names) > 1 {
+			last := len(names) - 1
+			records = strings.Join(names[:last], ", ") + " and " + names[last]
+		}
+		fieldDocs[fType] = fmt.Sprintf("%s field of %s records", name, records)
+	}
+
+	return recordDocs, fieldDocs
 }
 
 func writeTypesFile(codeFilename string, inFilenames []string) {
@@ -282,7 +504,28 @@ func main() {
 	codeFilename := "genTypes.code"
 	linesFilename := "genTypes.lines"
 
-	filenames := os.Args[1:]
+	mdFilename := flag.String("markdown", "",
+		"write a Markdown field reference to `file`")
+	htmlFilename := flag.String("html", "",
+		"write an HTML field reference to `file`")
+	bandPlansFilename := flag.String("bandplans", "",
+		"write the band plans of a JSON file as Go code to `file`")
+	flag.Parse()
+
+	filenames := flag.Args()
+	if *bandPlansFilename != "" {
+		if len(filenames) != 1 {
+			log.Fatal("-bandplans needs one band plan file")
+		}
+		writeBandPlans(*bandPlansFilename, filenames[0])
+		return
+	}
+
+	if *mdFilename != "" || *htmlFilename != "" {
+		writeReference(*mdFilename, *htmlFilename, filenames)
+		return
+	}
+
 	if len(filenames) > 0 {
 		writeTypesFile(codeFilename, filenames)
 	}
@@ -0,0 +1,203 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of GenLibTypes.
+//
+// GenLibTypes is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU General Public License
+// as published by the Free Software Foundation.
+//
+// GenLibTypes is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with GenLibTypes.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+)
+
+// An extent is the range of bytes, [start, end), occupied by a record
+// within a codeplug file, or the range of bits occupied by a field
+// within a record.
+type extent struct {
+	name  string
+	unit  string
+	start int
+	end   int
+}
+
+func (e extent) String() string {
+	return fmt.Sprintf("%s (%s %d-%d)", e.name, e.unit, e.start, e.end-1)
+}
+
+// overlaps returns an error message for each pair of the extents that
+// overlap.
+func overlaps(ranges []extent) []string {
+	errs := []string{}
+	for i, r1 := range ranges {
+		for _, r2 := range ranges[i+1:] {
+			if r1.start < r2.end && r2.start < r1.end {
+				errs = append(errs, fmt.Sprintf("%s overlaps %s", r1, r2))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateCodeplugs checks the layouts of the codeplugs, after
+// inheritance and defaults have been applied.  It returns a message
+// describing each problem found.
+func validateCodeplugs() []string {
+	errs := []string{}
+	for _, c := range codeplugs.Codeplugs {
+		for _, err := range validateCodeplug(c) {
+			errs = append(errs, fmt.Sprintf("%s: %s", c.Name, err))
+		}
+	}
+
+	return errs
+}
+
+func validateCodeplug(c *Codeplug) []string {
+	errs := []string{}
+	rTypes := map[string]bool{}
+	ranges := []extent{}
+
+	for _, r := range c.Records {
+		if rTypes[r.Type] {
+			errs = append(errs, fmt.Sprintf("duplicate record type %s", r.Type))
+		}
+		rTypes[r.Type] = true
+
+		end := r.Offset + r.Size*r.Max
+		if r.Size <= 0 || r.Max <= 0 {
+			errs = append(errs, fmt.Sprintf("%s: bad size %d or max %d",
+				r.Type, r.Size, r.Max))
+		} else if r.Offset < 0 || end > c.RdtSize {
+			errs = append(errs, fmt.Sprintf(
+				"%s: bytes %d-%d are outside the %d-byte file",
+				r.Type, r.Offset, end-1, c.RdtSize))
+		}
+		ranges = append(ranges, extent{r.Type, "bytes", r.Offset, end})
+
+		for _, err := range validateRecord(r) {
+			errs = append(errs, fmt.Sprintf("%s.%s", r.Type, err))
+		}
+	}
+	errs = append(errs, overlaps(ranges)...)
+
+	for _, r := range c.Records {
+		for _, f := range r.Fields {
+			if f.ListType != nil && !rTypes[*f.ListType] {
+				errs = append(errs, fmt.Sprintf(
+					"%s.%s: listType %s is not a record type",
+					r.Type, f.Type, *f.ListType))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateRecord checks the layout of a record's fields.  Each message
+// it returns begins with the name of the field concerned.
+func validateRecord(r *Record) []string {
+	errs := []string{}
+	fields := map[string]bool{}
+	ranges := []extent{}
+
+	for _, f := range r.Fields {
+		if fields[f.Type] {
+			errs = append(errs, fmt.Sprintf("%s: duplicate field type", f.Type))
+		}
+		fields[f.Type] = true
+
+		end := f.BitOffset + f.BitSize*f.Max
+		if f.BitSize <= 0 || f.Max <= 0 {
+			errs = append(errs, fmt.Sprintf("%s: bad bitSize %d or max %d",
+				f.Type, f.BitSize, f.Max))
+		} else if f.BitOffset < 0 || end > r.Size*8 {
+			errs = append(errs, fmt.Sprintf(
+				"%s: bits %d-%d are outside the %d-byte record",
+				f.Type, f.BitOffset, end-1, r.Size))
+		}
+		ranges = append(ranges, extent{f.Type, "bits", f.BitOffset, end})
+
+		errs = append(errs, validateValues(f)...)
+	}
+
+	errs = append(errs, overlaps(ranges)...)
+
+	for _, f := range r.Fields {
+		if f.Enabling == nil {
+			continue
+		}
+		for _, fTypes := range [][]string{f.Enabling.Enables, f.Enabling.Disables} {
+			for _, fType := range fTypes {
+				if !fields[fType] {
+					errs = append(errs, fmt.Sprintf(
+						"%s: enabling target %s is not a field of the record",
+						f.Type, fType))
+				}
+			}
+		}
+	}
+
+	for _, dd := range r.DelDescs {
+		if dd.Offset < 0 || dd.Size <= 0 || dd.Offset+dd.Size > r.Size {
+			errs = append(errs, fmt.Sprintf(
+				"delDescs: bytes %d-%d are outside the %d-byte record",
+				dd.Offset, dd.Offset+dd.Size-1, r.Size))
+		}
+	}
+
+	return errs
+}
+
+// validateValues checks that the values of a field's strings,
+// indexedStrings and span fit in its bitSize.
+func validateValues(f *Field) []string {
+	errs := []string{}
+
+	// Values wider than an int are not stored as indexes.
+	if f.BitSize >= 31 {
+		return errs
+	}
+	limit := 1 << uint(f.BitSize)
+
+	if f.Strings != nil && len(*f.Strings) > limit {
+		errs = append(errs, fmt.Sprintf(
+			"%s: %d strings do not fit in %d bits",
+			f.Type, len(*f.Strings), f.BitSize))
+	}
+
+	if f.IndexedStrings != nil {
+		for _, is := range *f.IndexedStrings {
+			if is.Index < 0 || is.Index >= limit {
+				errs = append(errs, fmt.Sprintf(
+					"%s: index %d of %q does not fit in %d bits",
+					f.Type, is.Index, is.String, f.BitSize))
+			}
+		}
+	}
+
+	if f.Span != nil && (f.Span.Min < 0 || f.Span.Max >= limit) {
+		errs = append(errs, fmt.Sprintf(
+			"%s: span %d-%d does not fit in %d bits",
+			f.Type, f.Span.Min, f.Span.Max, f.BitSize))
+	}
+
+	return errs
+}
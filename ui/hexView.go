@@ -0,0 +1,193 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Ui.
+//
+// Ui is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Ui is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Ui.  If not, see <http://www.gnu.org/licenses/>.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/gui"
+	"github.com/therecipe/qt/widgets"
+)
+
+// hexViewColumns is the number of bytes shown in each row of a HexView.
+const hexViewColumns = 16
+
+// A HexView shows bytes as a hex dump, one byte per cell, with the
+// offset of each row in its vertical header.  If a set function is
+// connected, bytes may be edited in place.
+type HexView struct {
+	qTableView *widgets.QTableView
+	model      *core.QAbstractTableModel
+	bytes      []byte
+	offset     int
+	setFunc    func(offset int, b byte) error
+}
+
+func (parent *HBox) AddHexView() *HexView {
+	hv := new(HexView)
+	model := core.NewQAbstractTableModel(nil)
+	hv.model = model
+
+	model.ConnectRowCount(func(parent *core.QModelIndex) int {
+		return (len(hv.bytes) + hexViewColumns - 1) / hexViewColumns
+	})
+
+	model.ConnectColumnCount(func(parent *core.QModelIndex) int {
+		return hexViewColumns
+	})
+
+	model.ConnectData(func(idx *core.QModelIndex, role int) *core.QVariant {
+		switch role {
+		case int(core.Qt__DisplayRole), int(core.Qt__EditRole):
+			i := hv.index(idx)
+			if i >= 0 {
+				return core.NewQVariant14(fmt.Sprintf("%02x", hv.bytes[i]))
+			}
+		}
+
+		return core.NewQVariant()
+	})
+
+	model.ConnectHeaderData(func(section int, orientation core.Qt__Orientation, role int) *core.QVariant {
+		if role != int(core.Qt__DisplayRole) {
+			return core.NewQVariant()
+		}
+		if orientation == core.Qt__Vertical {
+			offset := hv.offset + section*hexViewColumns
+			return core.NewQVariant14(fmt.Sprintf("%05x", offset))
+		}
+
+		return core.NewQVariant14(fmt.Sprintf("%x", section))
+	})
+
+	model.ConnectFlags(func(idx *core.QModelIndex) core.Qt__ItemFlag {
+		flags := core.Qt__ItemIsSelectable | core.Qt__ItemIsEnabled
+		if hv.setFunc != nil && hv.index(idx) >= 0 {
+			flags |= core.Qt__ItemIsEditable
+		}
+		return flags
+	})
+
+	model.ConnectSetData(func(idx *core.QModelIndex, value *core.QVariant, role int) bool {
+		i := hv.index(idx)
+		if role != int(core.Qt__EditRole) || hv.setFunc == nil || i < 0 {
+			return false
+		}
+
+		str := strings.TrimSpace(value.ToString())
+		b, err := strconv.ParseUint(str, 16, 8)
+		if err != nil {
+			WarningPopup("Edit Byte", str+" is not a hexadecimal byte")
+			return false
+		}
+
+		err = hv.setFunc(hv.offset+i, byte(b))
+		if err != nil {
+			WarningPopup("Edit Byte", err.Error())
+			return false
+		}
+
+		return true
+	})
+
+	view := widgets.NewQTableView(nil)
+	hv.qTableView = view
+	view.SetModel(model)
+	view.SetFont(gui.QFontDatabase_SystemFont(gui.QFontDatabase__FixedFont))
+	view.SetSelectionMode(widgets.QAbstractItemView__SingleSelection)
+	view.HorizontalHeader().SetSectionResizeMode(widgets.QHeaderView__ResizeToContents)
+	view.SetMinimumWidth(view.HorizontalHeader().Length() * 3 / 2)
+
+	parent.layout.AddWidget(view, 0, 0)
+
+	return hv
+}
+
+// index returns the index into the view's bytes of the byte shown at
+// idx, or -1 if no byte is shown there.
+func (hv *HexView) index(idx *core.QModelIndex) int {
+	if !idx.IsValid() {
+		return -1
+	}
+
+	i := idx.Row()*hexViewColumns + idx.Column()
+	if i >= len(hv.bytes) {
+		return -1
+	}
+
+	return i
+}
+
+// SetBytes sets the bytes shown by the view, and the offset shown for
+// the first of them.
+func (hv *HexView) SetBytes(offset int, bytes []byte) {
+	if offset == hv.offset && len(bytes) == len(hv.bytes) {
+		hv.bytes = bytes
+		rowCount := hv.model.RowCount(core.NewQModelIndex())
+		topLeft := hv.model.CreateIndex(0, 0, nil)
+		bottomRight := hv.model.CreateIndex(rowCount-1, hexViewColumns-1, nil)
+		hv.model.DataChanged(topLeft, bottomRight, []int{})
+		return
+	}
+
+	hv.model.BeginResetModel()
+	hv.offset = offset
+	hv.bytes = bytes
+	hv.model.EndResetModel()
+}
+
+// ConnectSet makes the view's bytes editable.  fn is called with the
+// offset and new value of each byte edited.  If fn returns an error,
+// it is shown and the edit is discarded.
+func (hv *HexView) ConnectSet(fn func(offset int, b byte) error) {
+	hv.setFunc = fn
+}
+
+// ConnectCurrentChanged causes fn to be called with the offset of the
+// byte that becomes the view's current byte.
+func (hv *HexView) ConnectCurrentChanged(fn func(offset int)) {
+	selectionModel := hv.qTableView.SelectionModel()
+	selectionModel.ConnectCurrentChanged(func(current *core.QModelIndex, previous *core.QModelIndex) {
+		i := hv.index(current)
+		if i >= 0 {
+			fn(hv.offset + i)
+		}
+	})
+}
+
+// SetCurrent makes the byte at offset the view's current byte, and
+// scrolls the view to show it.
+func (hv *HexView) SetCurrent(offset int) {
+	i := offset - hv.offset
+	if i < 0 || i >= len(hv.bytes) {
+		return
+	}
+
+	idx := hv.model.CreateIndex(i/hexViewColumns, i%hexViewColumns, nil)
+	hv.qTableView.SetCurrentIndex(idx)
+	hv.qTableView.ScrollTo(idx, widgets.QAbstractItemView__EnsureVisible)
+}
@@ -171,18 +171,23 @@ func (rl *RecordList) RemoveSelected() error {
 		return fmt.Errorf("can't delete last record")
 	}
 
-	model := rl.qListView.Model()
-	qModelIndex := core.NewQModelIndex()
-
-	change := cp.RemoveRecordsChange(records)
-	for _, r := range records {
-		row := r.Index()
+	err := cp.CheckReferences(records)
+	if err != nil {
+		title := fmt.Sprintf("Delete %s", w.recordType)
+		msg := err.Error() + "\n\n"
+		msg += "Deleting will remove these references.\n"
+		msg += "Are you sure you want to delete?"
+		if YesNoPopup(title, msg) != PopupYes {
+			return nil
+		}
+	}
 
-		model.BeginRemoveRows(qModelIndex, row, row)
-		cp.RemoveRecord(r)
-		model.EndRemoveRows()
+	w.recordModel.BeginResetModel()
+	err = cp.RemoveRecords(records, codeplug.DeleteCascade)
+	w.recordModel.EndResetModel()
+	if err != nil {
+		return err
 	}
-	change.Complete()
 
 	rl.Update()
 	w.recordFunc()
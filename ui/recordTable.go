@@ -0,0 +1,359 @@
+// Copyright 2017 Dale Farnsworth. All rights reserved.
+
+// Dale Farnsworth
+// 1007 W Mendoza Ave
+// Mesa, AZ  85210
+// USA
+//
+// dale@farnsworth.org
+
+// This file is part of Ui.
+//
+// Ui is free software: you can redistribute it and/or modify
+// it under the terms of version 3 of the GNU Lesser General Public
+// License as published by the Free Software Foundation.
+//
+// Ui is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with Ui.  If not, see <http://www.gnu.org/licenses/>.
+
+package ui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dalefarnsworth/codeplug/codeplug"
+	"github.com/therecipe/qt/core"
+	"github.com/therecipe/qt/gui"
+	"github.com/therecipe/qt/widgets"
+)
+
+// A RecordTable shows the records of a type as a table, with a row for
+// each record and a column for each field type.  Cells are edited with
+// the same widgets used in record windows.  Rows may be sorted by any
+// column and filtered by their contents.  Tab-separated text may be
+// copied from, and pasted into, a block of cells.  Each edit or paste
+// is a change that may be undone.
+type RecordTable struct {
+	codeplug   *codeplug.Codeplug
+	recordType codeplug.RecordType
+	fieldTypes []codeplug.FieldType
+	rowCount   int
+	model      *core.QAbstractTableModel
+	proxy      *core.QSortFilterProxyModel
+	qTableView *widgets.QTableView
+}
+
+// AddRecordTable adds a table of the records of the given type, with
+// columns for the given field types.  If no field types are given,
+// there is a column for each field type having a single value, with
+// the name field first, once there are records of the type.
+func (parent *HBox) AddRecordTable(rType codeplug.RecordType, fTypes ...codeplug.FieldType) *RecordTable {
+	t := new(RecordTable)
+	t.codeplug = parent.window.mainWindow.codeplug
+	t.recordType = rType
+	t.fieldTypes = fTypes
+	t.setFieldTypes()
+	t.rowCount = len(t.records())
+
+	model := core.NewQAbstractTableModel(nil)
+	t.model = model
+
+	model.ConnectRowCount(func(parent *core.QModelIndex) int {
+		return t.rowCount
+	})
+
+	model.ConnectColumnCount(func(parent *core.QModelIndex) int {
+		return len(t.fieldTypes)
+	})
+
+	model.ConnectData(func(idx *core.QModelIndex, role int) *core.QVariant {
+		if role == int(core.Qt__DisplayRole) {
+			f := t.field(idx)
+			if f != nil {
+				return core.NewQVariant14(f.String())
+			}
+		}
+
+		return core.NewQVariant()
+	})
+
+	model.ConnectHeaderData(func(section int, orientation core.Qt__Orientation, role int) *core.QVariant {
+		if role != int(core.Qt__DisplayRole) {
+			return core.NewQVariant()
+		}
+		if orientation == core.Qt__Vertical {
+			return core.NewQVariant14(strconv.Itoa(section + 1))
+		}
+
+		fType := t.fieldTypes[section]
+		return core.NewQVariant14(t.codeplug.FieldTypeName(t.recordType, fType))
+	})
+
+	model.ConnectFlags(func(idx *core.QModelIndex) core.Qt__ItemFlag {
+		f := t.field(idx)
+		if f == nil || !f.IsEnabled() {
+			return core.Qt__NoItemFlags
+		}
+		return core.Qt__ItemIsSelectable | core.Qt__ItemIsEnabled |
+			core.Qt__ItemIsEditable
+	})
+
+	proxy := core.NewQSortFilterProxyModel(nil)
+	t.proxy = proxy
+	proxy.SetSourceModel(model)
+	proxy.SetFilterKeyColumn(-1)
+	proxy.SetFilterCaseSensitivity(core.Qt__CaseInsensitive)
+	proxy.ConnectLessThan(func(left *core.QModelIndex, right *core.QModelIndex) bool {
+		return lessFieldString(t.cellString(left), t.cellString(right))
+	})
+
+	// The editor of a cell is the widget used for its field in a
+	// record window, which sets the field itself.
+	delegate := widgets.NewQStyledItemDelegate(nil)
+	delegate.ConnectCreateEditor(func(parent *widgets.QWidget, option *widgets.QStyleOptionViewItem, idx *core.QModelIndex) *widgets.QWidget {
+		f := t.field(proxy.MapToSource(idx))
+		if f == nil {
+			return nil
+		}
+		qw := newFieldWidget[f.ValueType()](f).qWidget.QWidget_PTR()
+		qw.SetParent(parent)
+		return qw
+	})
+	delegate.ConnectSetEditorData(func(editor *widgets.QWidget, idx *core.QModelIndex) {
+	})
+	delegate.ConnectSetModelData(func(editor *widgets.QWidget, model *core.QAbstractItemModel, idx *core.QModelIndex) {
+	})
+
+	view := widgets.NewQTableView(nil)
+	t.qTableView = view
+	view.SetModel(proxy)
+	view.SetItemDelegate(delegate)
+	view.HorizontalHeader().SetSortIndicator(-1, core.Qt__AscendingOrder)
+	view.SetSortingEnabled(true)
+	view.SetSelectionMode(widgets.QAbstractItemView__ExtendedSelection)
+	view.SetEditTriggers(widgets.QAbstractItemView__DoubleClicked |
+		widgets.QAbstractItemView__EditKeyPressed |
+		widgets.QAbstractItemView__AnyKeyPressed)
+	view.HorizontalHeader().SetSectionResizeMode(widgets.QHeaderView__ResizeToContents)
+
+	view.ConnectKeyPressEvent(func(event *gui.QKeyEvent) {
+		switch {
+		case event.Matches(gui.QKeySequence__Copy):
+			t.copy()
+		case event.Matches(gui.QKeySequence__Paste):
+			t.paste()
+		default:
+			view.KeyPressEventDefault(event)
+		}
+	})
+
+	parent.layout.AddWidget(view, 0, 0)
+
+	return t
+}
+
+// tableFieldTypes returns the field types of the record having a
+// single value that can be edited in a table, with the name field
+// type first.
+func tableFieldTypes(r *codeplug.Record) []codeplug.FieldType {
+	nameType := r.NameFieldType()
+	fTypes := []codeplug.FieldType{}
+	if nameType != "" {
+		fTypes = append(fTypes, nameType)
+	}
+
+	for _, fType := range r.FieldTypes() {
+		if fType == nameType || r.MaxFields(fType) != 1 {
+			continue
+		}
+		f := r.Field(fType)
+		if f == nil || f.ValueType() == codeplug.VtTextMessage {
+			continue
+		}
+		if newFieldWidget[f.ValueType()] == nil {
+			continue
+		}
+		fTypes = append(fTypes, fType)
+	}
+
+	return fTypes
+}
+
+// setFieldTypes sets the table's field types, if none were given, to
+// those of the first record.
+func (t *RecordTable) setFieldTypes() {
+	records := t.records()
+	if len(t.fieldTypes) == 0 && len(records) > 0 {
+		t.fieldTypes = tableFieldTypes(records[0])
+	}
+}
+
+func (t *RecordTable) records() []*codeplug.Record {
+	return t.codeplug.Records(t.recordType)
+}
+
+// field returns the field shown in the cell of the table's model at
+// idx, or nil if there is none.
+func (t *RecordTable) field(idx *core.QModelIndex) *codeplug.Field {
+	records := t.records()
+	row, column := idx.Row(), idx.Column()
+	if !idx.IsValid() || row >= len(records) || column >= len(t.fieldTypes) {
+		return nil
+	}
+
+	return records[row].Field(t.fieldTypes[column])
+}
+
+// cellString returns the value shown in the cell of the table's model
+// at idx.
+func (t *RecordTable) cellString(idx *core.QModelIndex) string {
+	f := t.field(idx)
+	if f == nil {
+		return ""
+	}
+
+	return f.String()
+}
+
+// lessFieldString orders field values numerically, if both are
+// numbers, and otherwise alphabetically, ignoring case.
+func lessFieldString(a string, b string) bool {
+	aValue, aErr := strconv.ParseFloat(a, 64)
+	bValue, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		return aValue < bValue
+	}
+
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// SetFilter shows only the rows having a cell containing str, ignoring
+// case.  An empty str shows all rows.
+func (t *RecordTable) SetFilter(str string) {
+	t.proxy.SetFilterFixedString(str)
+}
+
+// Update updates the table to show the current state of the records.
+// It should be called after each change to the codeplug.
+func (t *RecordTable) Update() {
+	rowCount := len(t.records())
+	if rowCount != t.rowCount {
+		t.model.BeginResetModel()
+		t.rowCount = rowCount
+		t.setFieldTypes()
+		t.model.EndResetModel()
+		return
+	}
+
+	if rowCount == 0 {
+		return
+	}
+	topLeft := t.model.CreateIndex(0, 0, nil)
+	bottomRight := t.model.CreateIndex(rowCount-1, len(t.fieldTypes)-1, nil)
+	t.model.DataChanged(topLeft, bottomRight, []int{})
+}
+
+// selectedCells returns the indexes, in the table's view, of the
+// selected cells, ordered by row and then by column.
+func (t *RecordTable) selectedCells() []*core.QModelIndex {
+	indexes := t.qTableView.SelectedIndexes()
+	sort.Slice(indexes, func(i, j int) bool {
+		if indexes[i].Row() != indexes[j].Row() {
+			return indexes[i].Row() < indexes[j].Row()
+		}
+		return indexes[i].Column() < indexes[j].Column()
+	})
+
+	return indexes
+}
+
+// copy copies the values of the selected cells to the clipboard, as
+// lines of tab-separated values.
+func (t *RecordTable) copy() {
+	indexes := t.selectedCells()
+	if len(indexes) == 0 {
+		return
+	}
+
+	lines := []string{}
+	var line []string
+	row := indexes[0].Row()
+	for _, idx := range indexes {
+		if idx.Row() != row {
+			lines = append(lines, strings.Join(line, "\t"))
+			line = nil
+			row = idx.Row()
+		}
+		line = append(line, t.cellString(t.proxy.MapToSource(idx)))
+	}
+	lines = append(lines, strings.Join(line, "\t"))
+
+	clipboard := gui.QGuiApplication_Clipboard()
+	clipboard.SetText(strings.Join(lines, "\n")+"\n", gui.QClipboard__Clipboard)
+}
+
+// paste sets cells to the lines of tab-separated values on the
+// clipboard, starting at the current cell, as a single change.  A
+// single value is pasted into every selected cell.  Values that would
+// fall outside the table, or into disabled cells, are ignored.
+func (t *RecordTable) paste() {
+	clipboard := gui.QGuiApplication_Clipboard()
+	text := strings.TrimRight(clipboard.Text(gui.QClipboard__Clipboard), "\r\n")
+	if text == "" {
+		return
+	}
+
+	var values [][]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		values = append(values, strings.Split(line, "\t"))
+	}
+
+	var fields []*codeplug.Field
+	var strs []string
+	add := func(idx *core.QModelIndex, value string) {
+		f := t.field(t.proxy.MapToSource(idx))
+		if f == nil || !f.IsEnabled() || f.String() == value {
+			return
+		}
+		fields = append(fields, f)
+		strs = append(strs, value)
+	}
+
+	selected := t.selectedCells()
+	current := t.qTableView.CurrentIndex()
+	switch {
+	case len(values) == 1 && len(values[0]) == 1 && len(selected) > 1:
+		for _, idx := range selected {
+			add(idx, values[0][0])
+		}
+
+	case current.IsValid():
+		rowCount := t.proxy.RowCount(core.NewQModelIndex())
+		for i, line := range values {
+			row := current.Row() + i
+			if row >= rowCount {
+				break
+			}
+			for j, value := range line {
+				column := current.Column() + j
+				if column >= len(t.fieldTypes) {
+					break
+				}
+				add(t.proxy.Index(row, column, core.NewQModelIndex()), value)
+			}
+		}
+	}
+
+	err := t.codeplug.SetFieldStrings(fields, strs)
+	if err != nil {
+		WarningPopup("Paste", err.Error())
+	}
+}
@@ -145,6 +145,15 @@ func (mw *MainWindow) SetCodeplug(cp *codeplug.Codeplug) {
 			if mw.codeplug != change.Codeplug() {
 				continue
 			}
+			if change.Type() == codeplug.BytesChange {
+				// Setting bytes may change records of any type.
+				for _, w := range mw.recordWindows {
+					w.handleChange(change)
+				}
+				mw.connectChange(change)
+				continue
+			}
+
 			changes := append(change.Changes(), change)
 			for _, change := range changes {
 				w := mw.recordWindows[change.RecordType()]
@@ -300,6 +309,47 @@ func (w *Window) RecordType() codeplug.RecordType {
 	return w.recordType
 }
 
+// FocusField makes the record at rIndex the window's current record
+// and gives the keyboard focus to the widget of the given field type,
+// if the window has one.
+func (w *Window) FocusField(rIndex int, fType codeplug.FieldType) {
+	if rIndex < len(w.records()) {
+		w.SetCurrentRecordIndex(rIndex)
+	}
+	w.Show()
+
+	widget := w.widgets[fType]
+	if widget != nil {
+		widget.qWidget.QWidget_PTR().SetFocus(core.Qt__OtherFocusReason)
+	}
+}
+
+// CurrentRecordIndex returns the index of the window's current record.
+func (w *Window) CurrentRecordIndex() int {
+	if w.recordList != nil {
+		return w.recordList.Current()
+	}
+
+	return w.recordIndex
+}
+
+// SetCurrentRecordIndex makes the record at rIndex the window's current
+// record.  Windows of records without names have no record list, so
+// the index is kept in the window.
+func (w *Window) SetCurrentRecordIndex(rIndex int) {
+	if w.recordList != nil {
+		w.recordList.SetCurrent(rIndex)
+		return
+	}
+
+	if rIndex != w.recordIndex {
+		w.recordIndex = rIndex
+		if w.recordFunc != nil {
+			w.recordFunc()
+		}
+	}
+}
+
 func (box *HBox) Clear() {
 	clear(box.qWidget)
 }
@@ -344,6 +394,7 @@ type Window struct {
 	subscriptions map[codeplug.FieldType][]codeplug.FieldType
 	recordModel   *core.QAbstractListModel
 	recordList    *RecordList
+	recordIndex   int
 	connectClose  func() bool
 	handleChange  func(*codeplug.Change)
 }
@@ -355,6 +406,14 @@ func (mw *MainWindow) NewWindow() *Window {
 	w.mainWindow = mw
 	w.window = w
 
+	w.qWidget.ConnectCloseEvent(func(event *gui.QCloseEvent) {
+		if w.connectClose != nil && !w.connectClose() {
+			event.Ignore()
+			return
+		}
+		event.Accept()
+	})
+
 	return w
 }
 
@@ -415,6 +474,22 @@ func (mw *MainWindow) NewRecordWindow(rType codeplug.RecordType) *Window {
 		case codeplug.RemoveRecordsChange:
 			updateRecordList = true
 
+		case codeplug.BatchFieldChange:
+			// Its field changes are handled individually.
+
+		case codeplug.BytesChange:
+			// The change has no record, and may have added or
+			// removed records, so the current record is redisplayed.
+			if rl != nil {
+				rl.Update()
+			} else if n := len(w.records()); w.recordIndex >= n && n > 0 {
+				w.recordIndex = n - 1
+			}
+			if w.recordFunc != nil {
+				w.recordFunc()
+			}
+			return
+
 		case codeplug.MoveFieldsChange,
 			codeplug.InsertFieldsChange,
 			codeplug.RemoveFieldsChange,
@@ -425,16 +500,16 @@ func (mw *MainWindow) NewRecordWindow(rType codeplug.RecordType) *Window {
 			log.Fatal("Unknown change type", changeType)
 		}
 
-		if updateRecordList {
+		if updateRecordList && rl != nil {
 			rl.Update()
 		}
 
 		if newCurrentRecord >= 0 {
-			rl.SetCurrent(newCurrentRecord)
+			w.SetCurrentRecordIndex(newCurrentRecord)
 		}
 
 		if updateRecordList || updateRecord {
-			if w.recordList.Current() == change.Record().Index() {
+			if w.CurrentRecordIndex() == change.Record().Index() {
 				w.recordFunc()
 			}
 		}
@@ -647,14 +722,27 @@ func (vBox *VBox) AddSeparator() {
 	vBox.layout.AddWidget(frame, 0, 0)
 }
 
-func (parent *HBox) AddLabel(str string) {
+// A Label is a line of text, which may be changed.
+type Label struct {
+	qLabel *widgets.QLabel
+}
+
+func (parent *HBox) AddLabel(str string) *Label {
 	qLabel := widgets.NewQLabel2(str, nil, 0)
 	parent.layout.AddWidget(qLabel, 0, 0)
+
+	return &Label{qLabel}
 }
 
-func (parent *VBox) AddLabel(str string) {
+func (parent *VBox) AddLabel(str string) *Label {
 	qLabel := widgets.NewQLabel2(str, nil, 0)
 	parent.layout.AddWidget(qLabel, 0, 0)
+
+	return &Label{qLabel}
+}
+
+func (label *Label) SetText(str string) {
+	label.qLabel.SetText(str)
 }
 
 func (parent *HBox) AddSpace(width int) {
@@ -828,6 +916,13 @@ func setString(f *codeplug.Field, str string) error {
 		return nil
 	}
 
+	txField, tx, ok := proposedTxFrequency(f, str)
+	if ok {
+		cp := f.Record().Codeplug()
+		fields := []*codeplug.Field{f, txField}
+		return cp.SetFieldStrings(fields, []string{str, tx})
+	}
+
 	err := f.SetString(str)
 	if err == nil {
 		change := f.Change(previousString)
@@ -836,6 +931,31 @@ func setString(f *codeplug.Field, str string) error {
 	return err
 }
 
+// proposedTxFrequency returns the transmit frequency field of a
+// channel whose receive frequency field, f, is being set to rx, and
+// the transmit frequency proposed for it by the codeplug's band plan.
+// It returns false unless the transmit frequency is the same as the
+// current receive frequency, as in a new channel.  The caller sets
+// both frequencies as a single change, so that one undo restores them.
+func proposedTxFrequency(f *codeplug.Field, rx string) (*codeplug.Field, string, bool) {
+	if f.Type() != codeplug.FtRxFrequency {
+		return nil, "", false
+	}
+
+	r := f.Record()
+	txField := r.Field(codeplug.FtTxFrequency)
+	if txField == nil || txField.String() != f.String() {
+		return nil, "", false
+	}
+
+	tx, ok := r.Codeplug().ProposedTxFrequency(rx)
+	if !ok || tx == txField.String() {
+		return nil, "", false
+	}
+
+	return txField, tx, true
+}
+
 func newFieldCheckbox(f *codeplug.Field) *Widget {
 	qw := widgets.NewQCheckBox(nil)
 	w := new(Widget)
@@ -919,6 +1039,32 @@ func setQSpinBox(sb *widgets.QSpinBox, f *codeplug.Field) {
 	sb.SetValue(value)
 }
 
+// NewCombobox returns a combobox offering the given strings, initially
+// showing value.  changedFunc is called with the string chosen.
+func NewCombobox(value string, strs []string, changedFunc func(string)) *Widget {
+	qw := widgets.NewQComboBox(nil)
+	widget := new(Widget)
+	widget.qWidget = qw
+	qw.InsertItems(0, strs)
+	qw.SetCurrentText(value)
+
+	qw.ConnectActivated2(changedFunc)
+
+	return widget
+}
+
+// NewLineEdit returns a line edit initially containing value.
+// changedFunc is called with the text whenever it changes.
+func NewLineEdit(value string, changedFunc func(string)) *Widget {
+	qw := widgets.NewQLineEdit2(value, nil)
+	widget := new(Widget)
+	widget.qWidget = qw
+
+	qw.ConnectTextChanged(changedFunc)
+
+	return widget
+}
+
 func NewSpinbox(value, min, max int, changedFunc func(int)) *Widget {
 	qw := widgets.NewQSpinBox(nil)
 	widget := new(Widget)
@@ -1141,6 +1287,25 @@ func YesNoPopup(title string, msg string) PopupValue {
 	return PopupNo
 }
 
+// ChoicePopup asks the user to choose one of strs, initially current.
+// It returns the chosen string, or "" if the user cancels.
+func ChoicePopup(title string, msg string, strs []string, current string) string {
+	index := 0
+	for i, s := range strs {
+		if s == current {
+			index = i
+		}
+	}
+
+	var ok bool
+	choice := widgets.QInputDialog_GetItem(nil, title, msg, strs, index, false, &ok, 0, 0)
+	if !ok {
+		return ""
+	}
+
+	return choice
+}
+
 func OpenFilename(title string) string {
 	return widgets.QFileDialog_GetOpenFileName(nil, title, "", "", "", 0)
 }
@@ -1161,6 +1326,7 @@ func ResetWindows(cp *codeplug.Codeplug) {
 				rl.SetCurrent(0)
 				rl.Update()
 			}
+			w.recordIndex = 0
 
 			w.recordFunc()
 		}